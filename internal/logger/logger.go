@@ -1,12 +1,14 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"os"
+	"strings"
 	"sync"
-	"time"
 )
 
 // Level represents log level
@@ -39,22 +41,95 @@ func (l Level) String() string {
 	}
 }
 
-// Logger provides structured logging
+// slogLevel maps a level to its slog equivalent
+func (l Level) slogLevel() slog.Level {
+	switch l {
+	case DebugLevel:
+		return slog.LevelDebug
+	case WarnLevel:
+		return slog.LevelWarn
+	case ErrorLevel:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Format selects the slog handler used to render records
+type Format string
+
+const (
+	// TextFormat renders key=value lines
+	TextFormat Format = "text"
+	// JSONFormat renders one JSON object per line
+	JSONFormat Format = "json"
+)
+
+// ParseFormat parses a --log-format value; empty means text
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(s))) {
+	case "", TextFormat:
+		return TextFormat, nil
+	case JSONFormat:
+		return JSONFormat, nil
+	default:
+		return "", fmt.Errorf("invalid log format %q (must be text or json)", s)
+	}
+}
+
+// Attribute keys shared by every component that logs about an operation or job
+const (
+	OperationIDKey = "operation_id"
+	JobIDKey       = "job_id"
+	ModelKey       = "model"
+	ComponentKey   = "component"
+)
+
+// OperationID returns the attribute identifying an operation
+func OperationID(id string) slog.Attr {
+	return slog.String(OperationIDKey, id)
+}
+
+// JobID returns the attribute identifying a batch or queue job
+func JobID(id string) slog.Attr {
+	return slog.String(JobIDKey, id)
+}
+
+// Model returns the attribute identifying a model
+func Model(model string) slog.Attr {
+	return slog.String(ModelKey, model)
+}
+
+// Logger provides structured logging on top of log/slog
 type Logger struct {
 	mu      sync.Mutex
+	writeMu *sync.Mutex // shared by loggers writing to the same output
 	level   Level
 	output  io.Writer
+	format  Format
 	verbose bool
 	quiet   bool
 	prefix  string
+	attrs   []slog.Attr
+	handler slog.Handler
 }
 
 var (
 	// Default is the default logger instance
 	Default *Logger
 	once    sync.Once
+	logFile *os.File
 )
 
+// Options configures the default logger
+type Options struct {
+	Verbose bool
+	Quiet   bool
+	Format  Format
+	// File appends logs to this path instead of writing to stderr
+	File string
+}
+
 // Init initializes the default logger
 func Init(verbose, quiet bool) {
 	once.Do(func() {
@@ -62,64 +137,151 @@ func Init(verbose, quiet bool) {
 		if verbose {
 			level = DebugLevel
 		}
-		Default = &Logger{
-			level:   level,
-			output:  os.Stderr,
-			verbose: verbose,
-			quiet:   quiet,
-		}
+		Default = NewLogger(level, os.Stderr, verbose, quiet)
 	})
 }
 
+// Configure replaces the default logger according to opts. A previously
+// opened log file is closed.
+func Configure(opts Options) error {
+	output := io.Writer(os.Stderr)
+	var file *os.File
+	if opts.File != "" {
+		var err error
+		file, err = os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		output = file
+	}
+
+	level := InfoLevel
+	if opts.Verbose {
+		level = DebugLevel
+	}
+
+	l := NewLogger(level, output, opts.Verbose, opts.Quiet)
+	l.format = opts.Format
+
+	// Later Init calls must not replace the configured logger
+	once.Do(func() {})
+	if logFile != nil {
+		_ = logFile.Close()
+	}
+	Default = l
+	logFile = file
+	return nil
+}
+
 // NewLogger creates a new logger with the given configuration
 func NewLogger(level Level, output io.Writer, verbose, quiet bool) *Logger {
 	return &Logger{
+		writeMu: &sync.Mutex{},
 		level:   level,
 		output:  output,
+		format:  TextFormat,
 		verbose: verbose,
 		quiet:   quiet,
 	}
 }
 
-// WithPrefix returns a new logger with the given prefix
-func (l *Logger) WithPrefix(prefix string) *Logger {
+// NewJSONLogger creates a logger that writes JSON records
+func NewJSONLogger(level Level, output io.Writer, verbose, quiet bool) *Logger {
+	l := NewLogger(level, output, verbose, quiet)
+	l.format = JSONFormat
+	return l
+}
+
+// derive returns a copy of the logger sharing its output
+func (l *Logger) derive() *Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	return &Logger{
+		writeMu: l.writeMu,
 		level:   l.level,
 		output:  l.output,
+		format:  l.format,
 		verbose: l.verbose,
 		quiet:   l.quiet,
-		prefix:  prefix,
+		prefix:  l.prefix,
+		attrs:   append([]slog.Attr{}, l.attrs...),
 	}
 }
 
-func (l *Logger) log(level Level, format string, v ...interface{}) {
+// WithPrefix returns a new logger with the given prefix, logged as the component attribute
+func (l *Logger) WithPrefix(prefix string) *Logger {
+	child := l.derive()
+	child.prefix = prefix
+	return child
+}
+
+// With returns a new logger that adds attributes to every record, e.g.
+// logger.With(logger.OperationID(id), logger.Model(model))
+func (l *Logger) With(attrs ...slog.Attr) *Logger {
+	child := l.derive()
+	child.attrs = append(child.attrs, attrs...)
+	return child
+}
+
+// Handler returns the slog handler behind the logger, including its attributes
+func (l *Logger) Handler() slog.Handler {
 	if l == nil {
-		return
+		return slog.DiscardHandler
 	}
 
-	// Skip if level is below threshold
-	if level < l.level {
-		return
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.handler != nil {
+		return l.handler
 	}
 
-	// Skip info messages if quiet mode
-	if l.quiet && level == InfoLevel {
+	options := &slog.HandlerOptions{Level: l.level.slogLevel()}
+	out := &lockedWriter{mu: l.writeMu, w: l.output}
+
+	var handler slog.Handler
+	if l.format == JSONFormat {
+		handler = slog.NewJSONHandler(out, options)
+	} else {
+		handler = slog.NewTextHandler(out, options)
+	}
+
+	attrs := l.attrs
+	if l.prefix != "" {
+		attrs = append([]slog.Attr{slog.String(ComponentKey, l.prefix)}, attrs...)
+	}
+	if len(attrs) > 0 {
+		handler = handler.WithAttrs(attrs)
+	}
+
+	l.handler = handler
+	return handler
+}
+
+// Slog returns a *slog.Logger writing through this logger
+func (l *Logger) Slog() *slog.Logger {
+	return slog.New(l.Handler())
+}
+
+func (l *Logger) log(level Level, format string, v ...interface{}) {
+	if l == nil {
 		return
 	}
 
 	l.mu.Lock()
-	defer l.mu.Unlock()
+	threshold, quiet := l.level, l.quiet
+	l.mu.Unlock()
 
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	prefix := ""
-	if l.prefix != "" {
-		prefix = fmt.Sprintf("[%s] ", l.prefix)
+	// Skip if level is below threshold
+	if level < threshold {
+		return
 	}
 
-	message := fmt.Sprintf(format, v...)
-	logLine := fmt.Sprintf("%s [%s] %s%s\n", timestamp, level.String(), prefix, message)
+	// Skip info messages if quiet mode
+	if quiet && level == InfoLevel {
+		return
+	}
 
-	_, _ = l.output.Write([]byte(logLine))
+	l.Slog().Log(context.Background(), level.slogLevel(), fmt.Sprintf(format, v...))
 }
 
 // Debug logs debug message (only when verbose is enabled)
@@ -153,6 +315,7 @@ func (l *Logger) SetLevel(level Level) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.level = level
+	l.handler = nil
 }
 
 // SetOutput sets the output writer
@@ -160,6 +323,7 @@ func (l *Logger) SetOutput(output io.Writer) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.output = output
+	l.handler = nil
 }
 
 // IsDebug returns true if debug logging is enabled
@@ -177,6 +341,18 @@ func (l *Logger) IsQuiet() bool {
 	return l != nil && l.quiet
 }
 
+// lockedWriter serializes writes from loggers sharing an output
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
 // Global convenience functions that use the default logger
 
 // Debug logs debug message using default logger
@@ -223,3 +399,9 @@ func WithPrefix(prefix string) *Logger {
 	}
 	return NewLogger(InfoLevel, os.Stderr, false, false).WithPrefix(prefix)
 }
+
+// Slog returns the default logger as a *slog.Logger, discarding records when
+// no default logger has been initialized
+func Slog() *slog.Logger {
+	return Default.Slog()
+}
@@ -2,11 +2,15 @@ package logger
 
 import (
 	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLevel_String(t *testing.T) {
@@ -58,7 +62,7 @@ func TestLogger_Debug(t *testing.T) {
 	logger.Debug("test debug message: %s", "value")
 	output := buf.String()
 
-	assert.Contains(t, output, "[DEBUG]")
+	assert.Contains(t, output, "level=DEBUG")
 	assert.Contains(t, output, "test debug message: value")
 }
 
@@ -79,7 +83,7 @@ func TestLogger_Info(t *testing.T) {
 	logger.Info("test info message: %d", 42)
 	output := buf.String()
 
-	assert.Contains(t, output, "[INFO]")
+	assert.Contains(t, output, "level=INFO")
 	assert.Contains(t, output, "test info message: 42")
 }
 
@@ -100,7 +104,7 @@ func TestLogger_Warn(t *testing.T) {
 	logger.Warn("test warning: %s", "something")
 	output := buf.String()
 
-	assert.Contains(t, output, "[WARN]")
+	assert.Contains(t, output, "level=WARN")
 	assert.Contains(t, output, "test warning: something")
 }
 
@@ -111,7 +115,7 @@ func TestLogger_Error(t *testing.T) {
 	logger.Error("test error: %v", "failed")
 	output := buf.String()
 
-	assert.Contains(t, output, "[ERROR]")
+	assert.Contains(t, output, "level=ERROR")
 	assert.Contains(t, output, "test error: failed")
 }
 
@@ -123,7 +127,7 @@ func TestLogger_WithPrefix_Output(t *testing.T) {
 	prefixedLogger.Info("test message")
 	output := buf.String()
 
-	assert.Contains(t, output, "[MODULE]")
+	assert.Contains(t, output, "component=MODULE")
 	assert.Contains(t, output, "test message")
 }
 
@@ -138,7 +142,7 @@ func TestLogger_SetLevel(t *testing.T) {
 	// Change to debug level
 	logger.SetLevel(DebugLevel)
 	logger.Debug("should appear")
-	assert.Contains(t, buf.String(), "[DEBUG]")
+	assert.Contains(t, buf.String(), "level=DEBUG")
 }
 
 func TestLogger_SetOutput(t *testing.T) {
@@ -268,7 +272,7 @@ func TestWithPrefix_GlobalFunction(t *testing.T) {
 
 	prefixedLogger.Info("test message")
 	output := buf.String()
-	assert.Contains(t, output, "[GLOBAL]")
+	assert.Contains(t, output, "component=GLOBAL")
 	assert.Contains(t, output, "test message")
 
 	// Reset
@@ -426,3 +430,76 @@ func TestInit_MultipleCalls(t *testing.T) {
 	Default = nil
 	once = sync.Once{}
 }
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, TextFormat, format)
+
+	format, err = ParseFormat("JSON")
+	require.NoError(t, err)
+	assert.Equal(t, JSONFormat, format)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestLogger_JSONFormatWithAttributes(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewJSONLogger(InfoLevel, buf, false, false).
+		WithPrefix("batch").
+		With(JobID("job-1"), OperationID("operations/abc"), Model("veo-3.1-generate-preview"))
+
+	logger.Info("job finished in %ds", 42)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "INFO", record["level"])
+	assert.Equal(t, "job finished in 42s", record["msg"])
+	assert.Equal(t, "batch", record[ComponentKey])
+	assert.Equal(t, "job-1", record[JobIDKey])
+	assert.Equal(t, "operations/abc", record[OperationIDKey])
+	assert.Equal(t, "veo-3.1-generate-preview", record[ModelKey])
+}
+
+func TestLogger_SlogHonoursLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewLogger(InfoLevel, buf, false, false).With(OperationID("operations/abc"))
+
+	logger.Slog().Debug("hidden")
+	logger.Slog().Warn("polling slowly", "interval", "30s")
+
+	output := buf.String()
+	assert.NotContains(t, output, "hidden")
+	assert.Contains(t, output, "level=WARN")
+	assert.Contains(t, output, "operation_id=operations/abc")
+	assert.Contains(t, output, "interval=30s")
+}
+
+func TestConfigure_LogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "veo3.log")
+	require.NoError(t, Configure(Options{Verbose: true, Format: JSONFormat, File: path}))
+	defer func() {
+		_ = logFile.Close()
+		Default, logFile = nil, nil
+		once = sync.Once{}
+	}()
+
+	Debug("written to %s", "file")
+
+	// Init must not replace a configured logger
+	Init(false, true)
+	assert.True(t, Default.IsVerbose())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"msg":"written to file"`)
+	assert.Contains(t, string(data), `"level":"DEBUG"`)
+}
+
+func TestSlog_NilDefault(t *testing.T) {
+	Default = nil
+	assert.NotPanics(t, func() {
+		Slog().Info("discarded")
+	})
+}
@@ -0,0 +1,161 @@
+package batch
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+)
+
+const (
+	// interpolationSeconds is the fixed length of an interpolated video
+	interpolationSeconds = 8
+	// extensionSeconds is the length an extend job adds to a video
+	extensionSeconds = 7
+	// defaultVideoSeconds is the API's default duration
+	defaultVideoSeconds = 8
+)
+
+// Budget caps how much a batch run may generate. Zero fields are not capped.
+type Budget struct {
+	MaxJobs         int     `yaml:"max_jobs,omitempty" json:"max_jobs,omitempty"`
+	MaxVideoSeconds int     `yaml:"max_video_seconds,omitempty" json:"max_video_seconds,omitempty"`
+	MaxCost         float64 `yaml:"max_cost,omitempty" json:"max_cost,omitempty"` // Estimated USD
+}
+
+// IsZero reports whether the budget has no caps
+func (b Budget) IsZero() bool {
+	return b.MaxJobs == 0 && b.MaxVideoSeconds == 0 && b.MaxCost == 0
+}
+
+// Validate checks that no cap is negative
+func (b Budget) Validate() error {
+	switch {
+	case b.MaxJobs < 0:
+		return fmt.Errorf("max_jobs must not be negative")
+	case b.MaxVideoSeconds < 0:
+		return fmt.Errorf("max_video_seconds must not be negative")
+	case b.MaxCost < 0:
+		return fmt.Errorf("max_cost must not be negative")
+	}
+	return nil
+}
+
+// JobDefaults are the model and duration a job uses when its options do
+// not set them
+type JobDefaults struct {
+	Model    string
+	Duration int
+}
+
+// JobEstimate is the expected size and cost of one job
+type JobEstimate struct {
+	Model   string
+	Seconds int
+	Cost    float64
+	Priced  bool // false if the model's price is unknown
+}
+
+// EstimateJob estimates the seconds of video a job generates and their cost
+func EstimateJob(job BatchJob, defaults JobDefaults) JobEstimate {
+	estimate := JobEstimate{Model: job.StringOption("model", defaults.Model)}
+	switch job.Type {
+	case "interpolate":
+		estimate.Seconds = interpolationSeconds
+	case "extend":
+		estimate.Seconds = extensionSeconds
+	default:
+		estimate.Seconds = job.IntOption("duration", defaults.Duration)
+		if estimate.Seconds <= 0 {
+			estimate.Seconds = defaultVideoSeconds
+		}
+	}
+	estimate.Cost, estimate.Priced = veo3.EstimateCost(estimate.Model, estimate.Seconds)
+	return estimate
+}
+
+// BudgetEstimate is the expected size and cost of a list of jobs
+type BudgetEstimate struct {
+	Jobs         int
+	VideoSeconds int
+	Cost         float64
+	Unpriced     []string // models without a known price, which are not in Cost
+}
+
+// EstimateJobs adds up the estimates of a list of jobs
+func EstimateJobs(jobs []BatchJob, defaults JobDefaults) BudgetEstimate {
+	var total BudgetEstimate
+	unpriced := make(map[string]bool)
+	for _, job := range jobs {
+		estimate := EstimateJob(job, defaults)
+		total.Jobs++
+		total.VideoSeconds += estimate.Seconds
+		total.Cost += estimate.Cost
+		if !estimate.Priced {
+			unpriced[estimate.Model] = true
+		}
+	}
+	for model := range unpriced {
+		total.Unpriced = append(total.Unpriced, model)
+	}
+	sort.Strings(total.Unpriced)
+	return total
+}
+
+// Check returns an error if the jobs would exceed the budget
+func (b Budget) Check(jobs []BatchJob, defaults JobDefaults) (BudgetEstimate, error) {
+	estimate := EstimateJobs(jobs, defaults)
+	switch {
+	case b.MaxJobs > 0 && estimate.Jobs > b.MaxJobs:
+		return estimate, fmt.Errorf("batch has %d jobs, over max_jobs %d", estimate.Jobs, b.MaxJobs)
+	case b.MaxVideoSeconds > 0 && estimate.VideoSeconds > b.MaxVideoSeconds:
+		return estimate, fmt.Errorf("batch generates about %ds of video, over max_video_seconds %d",
+			estimate.VideoSeconds, b.MaxVideoSeconds)
+	case b.MaxCost > 0 && len(estimate.Unpriced) > 0:
+		return estimate, fmt.Errorf("cannot check max_cost: no price known for model %s", estimate.Unpriced[0])
+	case b.MaxCost > 0 && estimate.Cost > b.MaxCost:
+		return estimate, fmt.Errorf("batch costs an estimated $%.2f, over max_cost $%.2f", estimate.Cost, b.MaxCost)
+	}
+	return estimate, nil
+}
+
+// budgetTracker enforces a budget while jobs are submitted. Its methods are
+// safe for concurrent use.
+type budgetTracker struct {
+	mu       sync.Mutex
+	budget   Budget
+	defaults JobDefaults
+	spent    BudgetEstimate
+}
+
+// reserve counts a job against the budget before it is submitted. If the
+// job does not fit it returns the reason and counts nothing.
+func (t *budgetTracker) reserve(job BatchJob) string {
+	return t.add(job, 1)
+}
+
+// reserveRetry counts a retry of a job against the budget. Retries generate
+// video again but do not count as another job.
+func (t *budgetTracker) reserveRetry(job BatchJob) string {
+	return t.add(job, 0)
+}
+
+func (t *budgetTracker) add(job BatchJob, jobs int) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	estimate := EstimateJob(job, t.defaults)
+	b := t.budget
+	switch {
+	case b.MaxJobs > 0 && t.spent.Jobs+jobs > b.MaxJobs:
+		return fmt.Sprintf("max_jobs budget of %d reached", b.MaxJobs)
+	case b.MaxVideoSeconds > 0 && t.spent.VideoSeconds+estimate.Seconds > b.MaxVideoSeconds:
+		return fmt.Sprintf("max_video_seconds budget of %d reached", b.MaxVideoSeconds)
+	case b.MaxCost > 0 && (!estimate.Priced || t.spent.Cost+estimate.Cost > b.MaxCost):
+		return fmt.Sprintf("max_cost budget of $%.2f reached", b.MaxCost)
+	}
+	t.spent.Jobs += jobs
+	t.spent.VideoSeconds += estimate.Seconds
+	t.spent.Cost += estimate.Cost
+	return ""
+}
@@ -0,0 +1,290 @@
+package batch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var jobsRef = refPattern("jobs")
+
+// jobRefFields are the fields of an earlier job that ${jobs.<id>.<field>}
+// can reference
+var jobRefFields = map[string]bool{"output": true}
+
+// splitJobRef splits "intro.output" into the job ID and field
+func splitJobRef(name string) (string, string) {
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return name, ""
+	}
+	return name[:i], name[i+1:]
+}
+
+// Dependencies returns the jobs this job waits for: its depends_on entries
+// followed by the jobs its options reference, without duplicates
+func (j BatchJob) Dependencies() ([]string, error) {
+	var deps []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			deps = append(deps, id)
+		}
+	}
+	for _, id := range j.DependsOn {
+		add(id)
+	}
+
+	var walk func(value interface{}) error
+	walk = func(value interface{}) error {
+		switch v := value.(type) {
+		case string:
+			for _, match := range jobsRef.FindAllStringSubmatch(v, -1) {
+				id, field := splitJobRef(match[1])
+				if !jobRefFields[field] {
+					return fmt.Errorf("invalid reference %s (expected ${jobs.<id>.output})", match[0])
+				}
+				add(id)
+			}
+		case []interface{}:
+			for _, item := range v {
+				if err := walk(item); err != nil {
+					return err
+				}
+			}
+		case []string:
+			for _, item := range v {
+				if err := walk(item); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	for _, key := range sortedKeys(j.Options) {
+		if err := walk(j.Options[key]); err != nil {
+			return nil, err
+		}
+	}
+	return deps, nil
+}
+
+// HasDependencies reports whether the job waits for other jobs
+func (j BatchJob) HasDependencies() bool {
+	deps, err := j.Dependencies()
+	return err != nil || len(deps) > 0
+}
+
+// UsesJobOutput reports whether an option references another job's output
+func (j BatchJob) UsesJobOutput(key string) bool {
+	return jobsRef.MatchString(fmt.Sprint(j.Options[key]))
+}
+
+// IsJobOutputRef reports whether value is a single ${jobs.<id>.output}
+// reference and nothing else
+func IsJobOutputRef(value string) bool {
+	match := jobsRef.FindStringIndex(value)
+	return match != nil && match[0] == 0 && match[1] == len(value)
+}
+
+// ResolveJobOutputs returns the job with its ${jobs.<id>.output} references
+// replaced by the given outputs of other jobs
+func ResolveJobOutputs(job BatchJob, outputs map[string]string) (BatchJob, error) {
+	ref := refs{scope: "jobs", kind: "output", pattern: jobsRef, get: func(name string) (interface{}, bool) {
+		id, _ := splitJobRef(name)
+		output, ok := outputs[id]
+		return output, ok
+	}}
+
+	options := make(map[string]interface{}, len(job.Options))
+	for key, value := range job.Options {
+		resolved, err := ref.substituteValue(value)
+		if err != nil {
+			return job, fmt.Errorf("job %s: %w", job.ID, err)
+		}
+		options[key] = resolved
+	}
+	job.Options = options
+	return job, nil
+}
+
+// jobGraph schedules jobs so each starts only after the jobs it depends on
+// have succeeded
+type jobGraph struct {
+	jobs       []BatchJob
+	deps       [][]int // jobs each job waits for
+	dependents [][]int // jobs waiting for each job
+	waiting    []int   // unfinished dependencies per job
+	done       []bool
+	ready      []int // jobs whose dependencies have all succeeded, in manifest order
+	outputs    map[string]string
+}
+
+// newJobGraph builds the dependency graph of a list of jobs. It returns an
+// error for unknown dependencies and cycles.
+func newJobGraph(jobs []BatchJob) (*jobGraph, error) {
+	index := make(map[string]int, len(jobs))
+	for i, job := range jobs {
+		index[job.ID] = i
+	}
+
+	g := &jobGraph{
+		jobs:       jobs,
+		deps:       make([][]int, len(jobs)),
+		dependents: make([][]int, len(jobs)),
+		waiting:    make([]int, len(jobs)),
+		done:       make([]bool, len(jobs)),
+		outputs:    make(map[string]string),
+	}
+	for i, job := range jobs {
+		deps, err := job.Dependencies()
+		if err != nil {
+			return nil, fmt.Errorf("job %s: %w", job.ID, err)
+		}
+		for _, id := range deps {
+			d, ok := index[id]
+			switch {
+			case !ok:
+				return nil, fmt.Errorf("job %s depends on unknown job %s", job.ID, id)
+			case d == i:
+				return nil, fmt.Errorf("job %s depends on itself", job.ID)
+			}
+			g.deps[i] = append(g.deps[i], d)
+			g.dependents[d] = append(g.dependents[d], i)
+		}
+		g.waiting[i] = len(g.deps[i])
+		if g.waiting[i] == 0 {
+			g.ready = append(g.ready, i)
+		}
+	}
+
+	if cycle := g.findCycle(); cycle != nil {
+		ids := make([]string, len(cycle))
+		for n, i := range cycle {
+			ids[n] = jobs[i].ID
+		}
+		return nil, fmt.Errorf("dependency cycle: %s", strings.Join(ids, " -> "))
+	}
+	return g, nil
+}
+
+// findCycle returns the jobs of a dependency cycle, starting and ending with
+// the same job, or nil if there is none
+func (g *jobGraph) findCycle() []int {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(g.jobs))
+	var path []int
+
+	var visit func(i int) []int
+	visit = func(i int) []int {
+		state[i] = visiting
+		path = append(path, i)
+		for _, d := range g.deps[i] {
+			switch state[d] {
+			case visiting:
+				for n, p := range path {
+					if p == d {
+						return append(append([]int(nil), path[n:]...), d)
+					}
+				}
+			case unvisited:
+				if cycle := visit(d); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		return nil
+	}
+
+	for i := range g.jobs {
+		if state[i] == unvisited {
+			if cycle := visit(i); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// next returns the first ready job in manifest order
+func (g *jobGraph) next() (int, bool) {
+	if len(g.ready) == 0 {
+		return 0, false
+	}
+	sort.Ints(g.ready)
+	i := g.ready[0]
+	g.ready = g.ready[1:]
+	return i, true
+}
+
+// resolve returns job i with its ${jobs.<id>.output} references replaced by
+// the outputs of the finished jobs
+func (g *jobGraph) resolve(i int) (BatchJob, error) {
+	return ResolveJobOutputs(g.jobs[i], g.outputs)
+}
+
+// finish records the outcome of job i. On success its dependents whose other
+// dependencies are done become ready; on failure they are skipped, along with
+// everything downstream of them, and returned as skipped results.
+func (g *jobGraph) finish(i int, result JobResult) []JobResult {
+	g.done[i] = true
+	if result.Success {
+		output := result.Output
+		if output == "" {
+			output = g.jobs[i].Output
+		}
+		g.outputs[g.jobs[i].ID] = output
+		for _, d := range g.dependents[i] {
+			if g.waiting[d]--; g.waiting[d] == 0 && !g.done[d] {
+				g.ready = append(g.ready, d)
+			}
+		}
+		return nil
+	}
+
+	var skipped []JobResult
+	for _, d := range g.dependents[i] {
+		if g.done[d] {
+			continue
+		}
+		result := JobResult{
+			JobID:   g.jobs[d].ID,
+			Skipped: true,
+			Error:   fmt.Sprintf("skipped: dependency %s did not succeed", g.jobs[i].ID),
+		}
+		result.describe(g.jobs[d])
+		skipped = append(skipped, result)
+		skipped = append(skipped, g.finish(d, result)...)
+	}
+	return skipped
+}
+
+// skipRemaining marks every unfinished job as skipped
+func (g *jobGraph) skipRemaining(reason string) []JobResult {
+	var skipped []JobResult
+	for i, job := range g.jobs {
+		if g.done[i] {
+			continue
+		}
+		g.done[i] = true
+		result := JobResult{JobID: job.ID, Skipped: true, Error: reason}
+		result.describe(job)
+		skipped = append(skipped, result)
+	}
+	g.ready = nil
+	return skipped
+}
+
+// checkDependencies validates the depends_on entries and job references of a
+// list of jobs
+func checkDependencies(jobs []BatchJob) error {
+	_, err := newJobGraph(jobs)
+	return err
+}
@@ -0,0 +1,360 @@
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Data file formats
+const (
+	DataFormatCSV   = "csv"
+	DataFormatJSONL = "jsonl"
+)
+
+// DataSource expands one job definition into a job per row of a CSV or JSONL
+// file:
+//
+//	data:
+//	  file: products.csv
+//	  id_column: sku
+//
+// Every column becomes a template variable, and columns named after job
+// options (prompt, model, duration, image, ...) also set that option. Rows
+// can be referenced as ${row.column} in the id, output, options, vars and
+// depends_on.
+// The short form "data: products.csv" sets only the file.
+type DataSource struct {
+	File string `yaml:"file" json:"file"`
+	// Format is csv or jsonl; by default it is taken from the file extension
+	Format string `yaml:"format,omitempty" json:"format,omitempty"`
+	// IDColumn names the column job IDs are derived from (default: "id" when
+	// present, otherwise the row number)
+	IDColumn string `yaml:"id_column,omitempty" json:"id_column,omitempty"`
+	// OutputColumn names the column holding each job's output path (default:
+	// "output" when present, otherwise derived from the job's output and ID)
+	OutputColumn string `yaml:"output_column,omitempty" json:"output_column,omitempty"`
+}
+
+// UnmarshalYAML accepts either a file name or a mapping
+func (d *DataSource) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		d.File = node.Value
+		return nil
+	}
+	type plain DataSource
+	return node.Decode((*plain)(d))
+}
+
+// DataRow is one row of a data file, keyed by column name
+type DataRow map[string]interface{}
+
+// dataOptionColumns are the columns that set the job option of the same name
+var dataOptionColumns = map[string]bool{
+	"prompt":            true,
+	"negative_prompt":   true,
+	"model":             true,
+	"resolution":        true,
+	"aspect_ratio":      true,
+	"duration":          true,
+	"seed":              true,
+	"person_generation": true,
+	"image":             true,
+	"first_frame":       true,
+	"last_frame":        true,
+	"match_dimensions":  true,
+	"reference_images":  true,
+	"video":             true,
+}
+
+// ReadDataFile reads the rows of a CSV or JSONL file. An empty format is
+// taken from the file extension.
+func ReadDataFile(path, format string) ([]DataRow, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+		if format == "ndjson" {
+			format = DataFormatJSONL
+		}
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- path comes from the manifest or CLI argument
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	var rows []DataRow
+	switch format {
+	case DataFormatCSV:
+		rows, err = parseCSV(data)
+	case DataFormatJSONL:
+		rows, err = parseJSONL(data)
+	default:
+		return nil, fmt.Errorf("unsupported data format %q for %s (must be %s or %s)", format, path, DataFormatCSV, DataFormatJSONL)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%s has no rows", path)
+	}
+	return rows, nil
+}
+
+// parseCSV reads rows keyed by the header row. Empty cells are left out.
+func parseCSV(data []byte) ([]DataRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+		if header[i] == "" {
+			return nil, fmt.Errorf("CSV column %d has no name", i+1)
+		}
+	}
+
+	var rows []DataRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		row := make(DataRow, len(header))
+		for i, value := range record {
+			if value = strings.TrimSpace(value); value != "" {
+				row[header[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+// parseJSONL reads one JSON object per line, skipping blank lines
+func parseJSONL(data []byte) ([]DataRow, error) {
+	var rows []DataRow
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var row DataRow
+		if err := json.Unmarshal([]byte(text), &row); err != nil {
+			return nil, fmt.Errorf("line %d: invalid JSON object: %w", line, err)
+		}
+		for key, value := range row {
+			if value == nil || value == "" {
+				delete(row, key)
+			}
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// refs returns the resolver for the row's ${row.column} references
+func (r DataRow) refs() refs {
+	return refs{scope: "row", kind: "column", pattern: rowRef, get: func(column string) (interface{}, bool) {
+		value, ok := r[column]
+		return value, ok
+	}}
+}
+
+// text returns a column as a string; lists are comma-separated like template
+// list variables
+func (r DataRow) text(column string) string {
+	switch value := r[column].(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		items := make([]string, len(value))
+		for i, item := range value {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ", ")
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+var rowRef = refPattern("row")
+
+// ExpandData replaces every job that has a data source with one job per row.
+// Relative data file paths are resolved against baseDir.
+func ExpandData(manifest *BatchManifest, baseDir string) error {
+	jobs := make([]BatchJob, 0, len(manifest.Jobs))
+	for i, job := range manifest.Jobs {
+		if job.Data == nil {
+			jobs = append(jobs, job)
+			continue
+		}
+
+		expanded, err := expandDataJob(job, baseDir)
+		if err != nil {
+			name := job.ID
+			if name == "" {
+				name = fmt.Sprintf("at index %d", i)
+			}
+			return fmt.Errorf("job %s: %w", name, err)
+		}
+		jobs = append(jobs, expanded...)
+	}
+	manifest.Jobs = jobs
+	return nil
+}
+
+// expandDataJob builds a job for each row of a job's data file
+func expandDataJob(job BatchJob, baseDir string) ([]BatchJob, error) {
+	source := job.Data
+	if source.File == "" {
+		return nil, fmt.Errorf("data source missing required field: file")
+	}
+	path := source.File
+	if baseDir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	rows, err := ReadDataFile(path, source.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	idColumn := columnOr(source.IDColumn, "id", rows)
+	outputColumn := columnOr(source.OutputColumn, "output", rows)
+
+	jobs := make([]BatchJob, 0, len(rows))
+	for n, row := range rows {
+		expanded, err := expandRow(job, row, n+1, idColumn, outputColumn)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", n+1, err)
+		}
+		jobs = append(jobs, expanded)
+	}
+	return jobs, nil
+}
+
+// expandRow builds the job for one row. Without an ID column the row number
+// identifies the job.
+func expandRow(job BatchJob, row DataRow, number int, idColumn, outputColumn string) (BatchJob, error) {
+	ref := row.refs()
+	expanded := job
+	expanded.Data = nil
+
+	suffix := fmt.Sprint(number)
+	if idColumn != "" {
+		if suffix = slugify(row.text(idColumn)); suffix == "" {
+			return expanded, fmt.Errorf("column %s is empty", idColumn)
+		}
+	}
+
+	var err error
+	if expanded.ID, err = ref.substitute(job.ID); err != nil {
+		return expanded, err
+	}
+	if !rowRef.MatchString(job.ID) {
+		base := job.ID
+		if base == "" && idColumn == "" {
+			base = "row"
+		}
+		expanded.ID = joinSlug(base, suffix)
+	}
+
+	switch {
+	case outputColumn != "" && row.text(outputColumn) != "":
+		expanded.Output = row.text(outputColumn)
+	case rowRef.MatchString(job.Output):
+		if expanded.Output, err = ref.substitute(job.Output); err != nil {
+			return expanded, err
+		}
+	case job.Output != "":
+		ext := filepath.Ext(job.Output)
+		expanded.Output = joinSlug(strings.TrimSuffix(job.Output, ext), suffix) + ext
+	default:
+		expanded.Output = expanded.ID + ".mp4"
+	}
+
+	expanded.Options = make(map[string]interface{}, len(job.Options)+len(row))
+	for key, value := range job.Options {
+		if expanded.Options[key], err = ref.substituteValue(value); err != nil {
+			return expanded, err
+		}
+	}
+	for _, column := range sortedKeys(row) {
+		if column == "type" && job.Type == "" {
+			expanded.Type = row.text(column)
+		}
+		if !dataOptionColumns[column] {
+			continue
+		}
+		value := row[column]
+		if text, ok := value.(string); ok && column == "reference_images" {
+			value = splitColumnList(text)
+		}
+		expanded.Options[column] = value
+	}
+
+	if job.Template != "" {
+		vars := make(map[string]string, len(row)+len(job.Vars))
+		for column := range row {
+			vars[column] = row.text(column)
+		}
+		explicit, err := ref.substituteAll(job.Vars)
+		if err != nil {
+			return expanded, err
+		}
+		for key, value := range explicit {
+			vars[key] = value
+		}
+		expanded.Vars = vars
+	} else if expanded.Vars, err = ref.substituteAll(job.Vars); err != nil {
+		return expanded, err
+	}
+	if expanded.DependsOn, err = ref.substituteList(job.DependsOn); err != nil {
+		return expanded, err
+	}
+
+	return expanded, nil
+}
+
+// columnOr returns the configured column, or fallback when any row has it
+func columnOr(column, fallback string, rows []DataRow) string {
+	if column != "" {
+		return column
+	}
+	for _, row := range rows {
+		if _, ok := row[fallback]; ok {
+			return fallback
+		}
+	}
+	return ""
+}
+
+// splitColumnList splits a comma-separated cell into its trimmed items
+func splitColumnList(s string) []interface{} {
+	var items []interface{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,195 @@
+package batch
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Defaults are options merged into the jobs of a manifest. Options apply to
+// every job and Types to jobs of one type. A job's own options win over its
+// type's defaults, which win over Options; nested maps are merged key by key.
+type Defaults struct {
+	Options map[string]interface{}            `yaml:"options,omitempty" json:"options,omitempty"`
+	Types   map[string]map[string]interface{} `yaml:"types,omitempty" json:"types,omitempty"`
+}
+
+// IsZero reports whether there are no defaults
+func (d Defaults) IsZero() bool {
+	return len(d.Options) == 0 && len(d.Types) == 0
+}
+
+// Validate checks that the defaults only name known job types
+func (d Defaults) Validate() error {
+	for _, jobType := range sortedTypes(d.Types) {
+		if !validJobTypes[jobType] {
+			return fmt.Errorf("types: unknown job type %s (must be generate, animate, interpolate, or extend)", jobType)
+		}
+	}
+	return nil
+}
+
+// ForType returns the default options of a job type
+func (d Defaults) ForType(jobType string) map[string]interface{} {
+	options := copyOptions(d.Types[jobType])
+	mergeOptions(options, d.Options)
+	return options
+}
+
+// applyDefaults fills in the options a job does not set. Templated jobs keep
+// the defaults until ApplyTemplates has merged the template's preset, which
+// takes precedence over them.
+func (j *BatchJob) applyDefaults(defaults Defaults) {
+	if defaults.IsZero() {
+		return
+	}
+	if j.Template != "" {
+		j.pendingDefaults = append(j.pendingDefaults, defaults)
+		return
+	}
+	if j.Options == nil {
+		j.Options = make(map[string]interface{})
+	}
+	mergeOptions(j.Options, defaults.ForType(j.Type))
+}
+
+// mergeOptions copies the options dst does not set from src, merging nested
+// maps
+func mergeOptions(dst, src map[string]interface{}) {
+	for key, value := range src {
+		existing, ok := dst[key]
+		if !ok {
+			dst[key] = copyOption(value)
+			continue
+		}
+		if dstMap, ok := existing.(map[string]interface{}); ok {
+			if srcMap, ok := value.(map[string]interface{}); ok {
+				mergeOptions(dstMap, srcMap)
+			}
+		}
+	}
+}
+
+// copyOptions returns a deep copy of an options map
+func copyOptions(options map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(options))
+	for key, value := range options {
+		result[key] = copyOption(value)
+	}
+	return result
+}
+
+// copyOption returns a deep copy of an option value, so jobs never share
+// nested maps or lists
+func copyOption(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return copyOptions(v)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = copyOption(item)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// sortedTypes returns the job types of per-type defaults in order
+func sortedTypes(types map[string]map[string]interface{}) []string {
+	keys := make([]string, 0, len(types))
+	for key := range types {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// envRef matches ${NAME} and ${NAME:-fallback} environment references. Names
+// cannot contain dots, so ${matrix.x}, ${row.x} and ${jobs.x.output} never
+// match.
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-[^}]*)?\}`)
+
+// envLookup returns the value of an environment variable and whether it is set
+type envLookup func(name string) (string, bool)
+
+// interpolate replaces the environment references in a string
+func (lookup envLookup) interpolate(s string) (string, error) {
+	var missing string
+	result := envRef.ReplaceAllStringFunc(s, func(ref string) string {
+		match := envRef.FindStringSubmatch(ref)
+		if value, ok := lookup(match[1]); ok {
+			return value
+		}
+		if match[2] != "" {
+			return strings.TrimPrefix(match[2], ":-")
+		}
+		if missing == "" {
+			missing = match[1]
+		}
+		return ref
+	})
+	if missing != "" {
+		return "", fmt.Errorf("environment variable %s is not set", missing)
+	}
+	return result, nil
+}
+
+// interpolateValue replaces the environment references in the strings of an
+// option value
+func (lookup envLookup) interpolateValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return lookup.interpolate(v)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			interpolated, err := lookup.interpolateValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i+1, err)
+			}
+			result[i] = interpolated
+		}
+		return result, nil
+	case map[string]interface{}:
+		return lookup.interpolateOptions(v)
+	default:
+		return value, nil
+	}
+}
+
+// interpolateOptions replaces the environment references in every option.
+// Errors name the option.
+func (lookup envLookup) interpolateOptions(options map[string]interface{}) (map[string]interface{}, error) {
+	if options == nil {
+		return nil, nil
+	}
+	result := make(map[string]interface{}, len(options))
+	for _, key := range sortedKeys(options) {
+		interpolated, err := lookup.interpolateValue(options[key])
+		if err != nil {
+			return nil, fmt.Errorf("option %s: %w", key, err)
+		}
+		result[key] = interpolated
+	}
+	return result, nil
+}
+
+// interpolateDefaults replaces the environment references in defaults
+func (lookup envLookup) interpolateDefaults(defaults *Defaults) error {
+	options, err := lookup.interpolateOptions(defaults.Options)
+	if err != nil {
+		return err
+	}
+	defaults.Options = options
+	for _, jobType := range sortedTypes(defaults.Types) {
+		options, err := lookup.interpolateOptions(defaults.Types[jobType])
+		if err != nil {
+			return fmt.Errorf("types: %s: %w", jobType, err)
+		}
+		defaults.Types[jobType] = options
+	}
+	return nil
+}
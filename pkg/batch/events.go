@@ -0,0 +1,208 @@
+package batch
+
+import (
+	"context"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+)
+
+// Event is something that happened during a batch run: a *JobQueuedEvent,
+// *JobStartedEvent, *OperationSubmittedEvent, *JobProgressEvent,
+// *JobRetryingEvent, *JobFinishedEvent or *BatchFinishedEvent
+type Event interface {
+	event()
+}
+
+// JobQueuedEvent is sent for every job of a manifest when its run starts
+type JobQueuedEvent struct {
+	Time      time.Time
+	JobID     string
+	Type      string
+	DependsOn []string // dependencies, including those of job output references
+}
+
+// JobStartedEvent is sent when an attempt of a job is handed to the executor
+type JobStartedEvent struct {
+	Time    time.Time
+	JobID   string
+	Type    string
+	Attempt int
+}
+
+// OperationSubmittedEvent is sent when an executor has submitted a job's
+// API operation
+type OperationSubmittedEvent struct {
+	Time        time.Time
+	JobID       string
+	Attempt     int
+	OperationID string
+}
+
+// Job progress stages
+const (
+	StageGenerating  = "generating"
+	StageDownloading = "downloading"
+)
+
+// JobProgressEvent is sent when an executor polls a job's operation or
+// downloads its video
+type JobProgressEvent struct {
+	Time        time.Time
+	JobID       string
+	Attempt     int
+	Stage       string // StageGenerating or StageDownloading
+	OperationID string
+	Polls       int     // polls of the operation so far
+	Percent     float64 // generation progress reported by the API, 0-100
+	Bytes       int64   // downloaded so far
+	TotalBytes  int64   // download size, 0 if unknown
+}
+
+// JobRetryingEvent is sent when a failed attempt of a job is about to be
+// retried
+type JobRetryingEvent struct {
+	Time        time.Time
+	JobID       string
+	Attempt     JobAttempt // the attempt that failed
+	MaxAttempts int
+	Delay       time.Duration // before the next attempt
+}
+
+// JobFinishedEvent is sent once per job with its final result, including
+// jobs that are skipped
+type JobFinishedEvent struct {
+	Time   time.Time
+	Result JobResult
+}
+
+// BatchFinishedEvent is the last event of a run
+type BatchFinishedEvent struct {
+	Time     time.Time
+	Results  []JobResult
+	Duration time.Duration
+	Err      error // why the run stopped early, if it did
+}
+
+func (*JobQueuedEvent) event()          {}
+func (*JobStartedEvent) event()         {}
+func (*OperationSubmittedEvent) event() {}
+func (*JobProgressEvent) event()        {}
+func (*JobRetryingEvent) event()        {}
+func (*JobFinishedEvent) event()        {}
+func (*BatchFinishedEvent) event()      {}
+
+// OnEvent adds a handler that receives every event of the processor's runs.
+// Events are delivered one at a time, in order, from the goroutines running
+// the batch, so a handler should return quickly; one that forwards events to
+// a channel should not block on it. A handler may add further handlers, which
+// receive the events after the current one.
+func (p *Processor) OnEvent(handler func(Event)) {
+	p.eventMu.Lock()
+	defer p.eventMu.Unlock()
+	p.handlers = append(p.handlers, handler)
+}
+
+// emit delivers an event to every handler. Handlers are copied under eventMu
+// and called under deliverMu, so events stay in order without blocking
+// registration.
+func (p *Processor) emit(event Event) {
+	p.deliverMu.Lock()
+	defer p.deliverMu.Unlock()
+
+	p.eventMu.Lock()
+	handlers := append([]func(Event){}, p.handlers...)
+	p.eventMu.Unlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// finished emits the final results of jobs
+func (p *Processor) finished(results ...JobResult) {
+	for _, result := range results {
+		p.emit(&JobFinishedEvent{Time: time.Now(), Result: result})
+	}
+}
+
+type reporterKey struct{}
+
+// JobReporter lets an executor report the progress of the job it runs to the
+// processor's event handlers. A nil JobReporter does nothing.
+type JobReporter struct {
+	processor   *Processor
+	jobID       string
+	attempt     int
+	operationID string
+	polls       int
+	downloaded  int64 // step of the last download progress reported
+}
+
+// Reporter returns the reporter of the job an executor was given ctx for,
+// or nil outside a batch run
+func Reporter(ctx context.Context) *JobReporter {
+	reporter, _ := ctx.Value(reporterKey{}).(*JobReporter)
+	return reporter
+}
+
+// withReporter returns a context carrying a reporter for one attempt of a job
+func (p *Processor) withReporter(ctx context.Context, jobID string, attempt int) context.Context {
+	return context.WithValue(ctx, reporterKey{}, &JobReporter{processor: p, jobID: jobID, attempt: attempt})
+}
+
+// OperationSubmitted reports the operation the job is waiting for
+func (r *JobReporter) OperationSubmitted(operationID string) {
+	if r == nil {
+		return
+	}
+	r.operationID = operationID
+	r.processor.emit(&OperationSubmittedEvent{Time: time.Now(), JobID: r.jobID, Attempt: r.attempt, OperationID: operationID})
+}
+
+// Polled reports a poll of the job's operation
+func (r *JobReporter) Polled(operation *veo3.Operation) {
+	if r == nil {
+		return
+	}
+	r.polls++
+	event := &JobProgressEvent{
+		Time:        time.Now(),
+		JobID:       r.jobID,
+		Attempt:     r.attempt,
+		Stage:       StageGenerating,
+		OperationID: r.operationID,
+		Polls:       r.polls,
+	}
+	if operation != nil {
+		event.Percent = operation.Progress
+	}
+	r.processor.emit(event)
+}
+
+// Downloading reports download progress; total is 0 when the size is
+// unknown. Progress is reported at the start and end of the download and for
+// every percent, or every megabyte when the size is unknown, in between.
+func (r *JobReporter) Downloading(written, total int64) {
+	if r == nil {
+		return
+	}
+	step := written >> 20
+	if total > 0 {
+		step = written * 100 / total
+	}
+	if written > 0 && written != total && step == r.downloaded {
+		return
+	}
+	r.downloaded = step
+	r.processor.emit(&JobProgressEvent{
+		Time:        time.Now(),
+		JobID:       r.jobID,
+		Attempt:     r.attempt,
+		Stage:       StageDownloading,
+		OperationID: r.operationID,
+		Polls:       r.polls,
+		Bytes:       written,
+		TotalBytes:  total,
+	})
+}
@@ -3,6 +3,9 @@ package batch
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -13,24 +16,142 @@ type BatchManifest struct {
 	Concurrency     int        `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
 	ContinueOnError bool       `yaml:"continue_on_error,omitempty" json:"continue_on_error,omitempty"`
 	OutputDirectory string     `yaml:"output_directory,omitempty" json:"output_directory,omitempty"`
+	// Budget caps the jobs, seconds of video and estimated cost of a run
+	Budget `yaml:",inline"`
+	// Defaults are options merged into every job
+	Defaults Defaults `yaml:"defaults,omitempty" json:"defaults,omitempty"`
+	// Retry is the retry policy of jobs that do not set their own
+	Retry *RetryPolicy `yaml:"retry,omitempty" json:"retry,omitempty"`
+	// Include lists manifest files whose jobs run before this manifest's
+	// own. Parsing resolves and clears it.
+	Include []string `yaml:"include,omitempty" json:"include,omitempty"`
 }
 
 // BatchJob represents a single video generation job in a batch
 type BatchJob struct {
 	ID      string                 `yaml:"id" json:"id"`
-	Type    string                 `yaml:"type" json:"type"` // "generate", "animate", "interpolate", "extend"
-	Options map[string]interface{} `yaml:"options" json:"options"`
+	Type    string                 `yaml:"type,omitempty" json:"type"` // "generate", "animate", "interpolate", "extend"
+	Options map[string]interface{} `yaml:"options,omitempty" json:"options"`
 	Output  string                 `yaml:"output" json:"output"`
+	// Template names a saved prompt template ("name" or "name@version") whose
+	// preset fills in the type and options the job does not set; Vars render
+	// its prompt
+	Template string            `yaml:"template,omitempty" json:"template,omitempty"`
+	Vars     map[string]string `yaml:"vars,omitempty" json:"vars,omitempty"`
+	// TemplateRef records the template version a job was expanded from
+	TemplateRef string `yaml:"template_ref,omitempty" json:"template_ref,omitempty"`
+	// Matrix expands the job into one job per combination of values
+	Matrix *Matrix `yaml:"matrix,omitempty" json:"matrix,omitempty"`
+	// Data expands the job into one job per row of a CSV or JSONL file
+	Data *DataSource `yaml:"data,omitempty" json:"data,omitempty"`
+	// DependsOn lists jobs that must succeed before this one starts. Options
+	// referencing ${jobs.<id>.output} depend on that job implicitly.
+	DependsOn []string `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
+	// Retry replaces the manifest's retry policy for this job
+	Retry *RetryPolicy `yaml:"retry,omitempty" json:"retry,omitempty"`
+
+	// pendingDefaults are the manifest defaults of a templated job, innermost
+	// manifest first, which ApplyTemplates merges after the template's preset
+	pendingDefaults []Defaults
 }
 
-// ParseManifest parses a YAML manifest from bytes
+// StringOption returns a string option, or fallback if it is unset or empty
+func (j BatchJob) StringOption(key, fallback string) string {
+	value, ok := j.Options[key]
+	if !ok || value == nil {
+		return fallback
+	}
+	str := fmt.Sprintf("%v", value)
+	if str == "" {
+		return fallback
+	}
+	return str
+}
+
+// IntOption returns an integer option, or fallback if it is unset or not a number
+func (j BatchJob) IntOption(key string, fallback int) int {
+	switch value := j.Options[key].(type) {
+	case int:
+		return value
+	case int64:
+		return int(value)
+	case float64:
+		return int(value)
+	case string:
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+// StringSliceOption returns a list option; a single string is treated as a one-element list
+func (j BatchJob) StringSliceOption(key string) []string {
+	switch value := j.Options[key].(type) {
+	case []string:
+		return value
+	case []interface{}:
+		result := make([]string, 0, len(value))
+		for _, item := range value {
+			result = append(result, fmt.Sprintf("%v", item))
+		}
+		return result
+	case string:
+		if value != "" {
+			return []string{value}
+		}
+	}
+	return nil
+}
+
+// ParseManifest parses a YAML manifest from bytes. Included manifests and
+// data files are resolved against the current directory.
 func ParseManifest(data []byte) (*BatchManifest, error) {
+	return manifestParser{lookupEnv: os.LookupEnv}.parse(data, "")
+}
+
+// ParseRemoteManifest parses a manifest received from a client. It may not
+// include files or read job data from them, and ${NAME} references are not
+// read from the environment.
+func ParseRemoteManifest(data []byte) (*BatchManifest, error) {
+	return manifestParser{remote: true}.parse(data, "")
+}
+
+// ParseManifestFile parses a YAML manifest from a file
+func ParseManifestFile(path string) (*BatchManifest, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is user-provided CLI argument
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest file: %w", err)
+	}
+
+	return manifestParser{lookupEnv: os.LookupEnv}.parse(data, path)
+}
+
+// manifestParser resolves the includes, defaults and environment references
+// of a manifest
+type manifestParser struct {
+	lookupEnv envLookup // nil leaves ${NAME} references as they are
+	remote    bool      // included files are not allowed
+}
+
+// parse parses a manifest read from path, or from no file if path is empty
+func (p manifestParser) parse(data []byte, path string) (*BatchManifest, error) {
 	var manifest BatchManifest
 
 	if err := yaml.Unmarshal(data, &manifest); err != nil {
 		return nil, fmt.Errorf("failed to parse manifest: %w", err)
 	}
 
+	var files []string
+	if path != "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			files = append(files, abs)
+		}
+	}
+	if err := p.resolve(&manifest, dirOf(path), files); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
 	// Apply defaults
 	ApplyDefaults(&manifest)
 
@@ -42,14 +163,124 @@ func ParseManifest(data []byte) (*BatchManifest, error) {
 	return &manifest, nil
 }
 
-// ParseManifestFile parses a YAML manifest from a file
-func ParseManifestFile(path string) (*BatchManifest, error) {
-	data, err := os.ReadFile(path) // #nosec G304 -- path is user-provided CLI argument
+// resolve expands a manifest's jobs and adds the jobs of its includes.
+// Relative paths are resolved against baseDir; files lists the manifests
+// being parsed, outermost first, to detect include cycles.
+func (p manifestParser) resolve(manifest *BatchManifest, baseDir string, files []string) error {
+	if err := manifest.Defaults.Validate(); err != nil {
+		return fmt.Errorf("defaults: %w", err)
+	}
+	if err := p.interpolate(manifest); err != nil {
+		return err
+	}
+
+	if p.remote {
+		for i, job := range manifest.Jobs {
+			if job.Data != nil {
+				name := job.ID
+				if name == "" {
+					name = fmt.Sprintf("at index %d", i)
+				}
+				return fmt.Errorf("job %s: data sources are not allowed in submitted manifests", name)
+			}
+		}
+	}
+
+	// Expand data-driven jobs, then matrix jobs, so each row and combination
+	// is validated as its own job
+	if err := ExpandData(manifest, baseDir); err != nil {
+		return err
+	}
+	if err := expandMatrices(manifest); err != nil {
+		return err
+	}
+
+	var jobs []BatchJob
+	for _, include := range manifest.Include {
+		included, err := p.include(include, baseDir, files)
+		if err != nil {
+			return fmt.Errorf("include %s: %w", include, err)
+		}
+		jobs = append(jobs, included...)
+	}
+	jobs = append(jobs, manifest.Jobs...)
+
+	// Included jobs already carry their own manifest's defaults, which take
+	// precedence
+	for i := range jobs {
+		jobs[i].applyDefaults(manifest.Defaults)
+	}
+	manifest.Jobs = jobs
+	manifest.Include = nil
+	return nil
+}
+
+// include parses an included manifest and returns its jobs. Only its jobs,
+// defaults and includes are used.
+func (p manifestParser) include(path, baseDir string, files []string) ([]BatchJob, error) {
+	if p.remote {
+		return nil, fmt.Errorf("includes are not allowed in submitted manifests")
+	}
+	if baseDir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	abs, err := filepath.Abs(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read manifest file: %w", err)
+		return nil, err
+	}
+	for _, file := range files {
+		if file == abs {
+			return nil, fmt.Errorf("include cycle: %s", strings.Join(append(files, abs), " -> "))
+		}
 	}
 
-	return ParseManifest(data)
+	data, err := os.ReadFile(abs) // #nosec G304 -- included by a user-provided manifest
+	if err != nil {
+		return nil, err
+	}
+	var manifest BatchManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if err := p.resolve(&manifest, filepath.Dir(abs), append(files, abs)); err != nil {
+		return nil, err
+	}
+	return manifest.Jobs, nil
+}
+
+// interpolate replaces ${NAME} environment references in the string options
+// of the defaults and jobs. It runs before expansion, so values read from
+// data files are left as they are.
+func (p manifestParser) interpolate(manifest *BatchManifest) error {
+	lookup := p.lookupEnv
+	if lookup == nil {
+		return nil
+	}
+
+	if err := lookup.interpolateDefaults(&manifest.Defaults); err != nil {
+		return fmt.Errorf("defaults: %w", err)
+	}
+	for i := range manifest.Jobs {
+		job := &manifest.Jobs[i]
+		options, err := lookup.interpolateOptions(job.Options)
+		if err != nil {
+			name := job.ID
+			if name == "" {
+				name = fmt.Sprintf("at index %d", i)
+			}
+			return fmt.Errorf("job %s: %w", name, err)
+		}
+		job.Options = options
+	}
+	return nil
+}
+
+// dirOf returns the directory of a manifest path, or "" for no file
+func dirOf(path string) string {
+	if path == "" {
+		return ""
+	}
+	return filepath.Dir(path)
 }
 
 // ValidateManifest validates a batch manifest
@@ -62,6 +293,20 @@ func ValidateManifest(manifest *BatchManifest) error {
 		return fmt.Errorf("concurrency must be at least 1")
 	}
 
+	if err := manifest.Budget.Validate(); err != nil {
+		return err
+	}
+
+	if err := manifest.Defaults.Validate(); err != nil {
+		return fmt.Errorf("defaults: %w", err)
+	}
+
+	if manifest.Retry != nil {
+		if err := manifest.Retry.Validate(); err != nil {
+			return fmt.Errorf("retry: %w", err)
+		}
+	}
+
 	// Check for duplicate job IDs
 	seen := make(map[string]bool)
 	for i, job := range manifest.Jobs {
@@ -74,27 +319,56 @@ func ValidateManifest(manifest *BatchManifest) error {
 		}
 		seen[job.ID] = true
 
-		if job.Output == "" {
-			return fmt.Errorf("job %s missing required field: output", job.ID)
+		if err := ValidateJob(job); err != nil {
+			return err
 		}
+	}
 
-		// Validate job type
-		validTypes := map[string]bool{
-			"generate":    true,
-			"animate":     true,
-			"interpolate": true,
-			"extend":      true,
-		}
-		if !validTypes[job.Type] {
-			return fmt.Errorf("job %s has invalid type: %s (must be generate, animate, interpolate, or extend)", job.ID, job.Type)
-		}
+	return checkDependencies(manifest.Jobs)
+}
+
+// validJobTypes are the job types a manifest may use
+var validJobTypes = map[string]bool{
+	"generate":    true,
+	"animate":     true,
+	"interpolate": true,
+	"extend":      true,
+}
 
-		// Validate job-specific options
-		if err := validateJobOptions(job); err != nil {
-			return fmt.Errorf("job %s: %w", job.ID, err)
+// ValidateJob validates a single job's output, type and required options
+func ValidateJob(job BatchJob) error {
+	if job.ID == "" {
+		return fmt.Errorf("job missing required field: id")
+	}
+
+	if job.Output == "" {
+		return fmt.Errorf("job %s missing required field: output", job.ID)
+	}
+
+	if job.Retry != nil {
+		if err := job.Retry.Validate(); err != nil {
+			return fmt.Errorf("job %s: retry: %w", job.ID, err)
 		}
 	}
 
+	// Templated jobs are fully validated once ApplyTemplates fills them in
+	if job.Template != "" && job.Type == "" {
+		return nil
+	}
+
+	// Validate job type
+	if !validJobTypes[job.Type] {
+		return fmt.Errorf("job %s has invalid type: %s (must be generate, animate, interpolate, or extend)", job.ID, job.Type)
+	}
+
+	// Validate job-specific options
+	if job.Template != "" {
+		return nil
+	}
+	if err := validateJobOptions(job); err != nil {
+		return fmt.Errorf("job %s: %w", job.ID, err)
+	}
+
 	return nil
 }
 
@@ -151,6 +425,25 @@ func GenerateTemplate() string {
 concurrency: 3              # Number of jobs to run in parallel (default: 3)
 continue_on_error: true     # Continue processing if a job fails (default: true)
 output_directory: ./videos  # Optional: Override output directory for all jobs
+# max_jobs: 50               # Optional: Cap the number of jobs submitted
+# max_video_seconds: 600     # Optional: Cap the seconds of video generated
+# max_cost: 25.00            # Optional: Cap the estimated cost in USD
+# retry:                     # Optional: Run failed jobs again (jobs may set their own)
+#   max_attempts: 3
+#   backoff: 30s
+#   retry_on: [transient]     # transient, safety, other
+#   vary_seed: false
+# include:                   # Optional: Add the jobs of other manifests
+#   - shared/jobs.yaml
+
+# Optional: Options merged into every job (job options take precedence);
+# ${NAME} references are read from the environment
+# defaults:
+#   options:
+#     aspect_ratio: "16:9"
+#   types:
+#     generate:
+#       negative_prompt: "${NEGATIVE_PROMPT:-blurry, low quality}"
 
 # List of jobs to process
 jobs:
@@ -182,12 +475,21 @@ jobs:
       last_frame: path/to/end.png
     output: interpolated.mp4
 
-  # Video extension
+  # Video extension of job1's video; it starts once job1 succeeds and is
+  # skipped if job1 fails (depends_on lists dependencies without references)
   - id: job4
     type: extend
     options:
-      video: path/to/existing.mp4
+      video: "${jobs.job1.output}"
       prompt: "The action continues"
     output: extended.mp4
+
+  # Job from a saved template (veo3 templates save); the template's preset
+  # supplies the type and options, and options set here take precedence
+  - id: job5
+    template: promo
+    vars:
+      product: "a smartwatch"
+    output: promo.mp4
 `
 }
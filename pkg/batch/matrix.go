@@ -0,0 +1,345 @@
+package batch
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Matrix expands one job definition into a job per combination of its
+// dimensions:
+//
+//	matrix:
+//	  prompt: ["A cat", "A dog"]
+//	  aspect_ratio: ["16:9", "9:16"]
+//	  seed: [1, 2, 3]
+//	  exclude:
+//	    - {prompt: "A dog", aspect_ratio: "9:16"}
+//	  include:
+//	    - {prompt: "A bird", aspect_ratio: "16:9", seed: 7}
+//
+// Each combination's values are set as job options of the same name and can
+// be referenced as ${matrix.key} in the id, output, options, vars and
+// depends_on.
+type Matrix struct {
+	Dimensions []MatrixDimension
+	// Exclude removes every combination matching all of an entry's values
+	Exclude []map[string]interface{}
+	// Include adds extra combinations after exclusion
+	Include []map[string]interface{}
+}
+
+// MatrixDimension is one matrix key and the values it takes
+type MatrixDimension struct {
+	Key    string
+	Values []interface{}
+}
+
+// UnmarshalYAML decodes a matrix, keeping the order of its dimensions
+func (m *Matrix) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: matrix must be a mapping of keys to value lists", node.Line)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		switch key {
+		case "exclude":
+			if err := value.Decode(&m.Exclude); err != nil {
+				return fmt.Errorf("matrix exclude: %w", err)
+			}
+		case "include":
+			if err := value.Decode(&m.Include); err != nil {
+				return fmt.Errorf("matrix include: %w", err)
+			}
+		default:
+			var values []interface{}
+			if err := value.Decode(&values); err != nil {
+				return fmt.Errorf("matrix %s must be a list of values: %w", key, err)
+			}
+			m.Dimensions = append(m.Dimensions, MatrixDimension{Key: key, Values: values})
+		}
+	}
+	return nil
+}
+
+// MarshalYAML encodes the matrix in the form UnmarshalYAML reads
+func (m Matrix) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	add := func(key string, value interface{}) error {
+		var v yaml.Node
+		if err := v.Encode(value); err != nil {
+			return err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &v)
+		return nil
+	}
+	for _, dim := range m.Dimensions {
+		if err := add(dim.Key, dim.Values); err != nil {
+			return nil, err
+		}
+	}
+	if len(m.Exclude) > 0 {
+		if err := add("exclude", m.Exclude); err != nil {
+			return nil, err
+		}
+	}
+	if len(m.Include) > 0 {
+		if err := add("include", m.Include); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// combination is one set of matrix values, in dimension order
+type combination []matrixValue
+
+type matrixValue struct {
+	key   string
+	value interface{}
+}
+
+func (c combination) get(key string) (interface{}, bool) {
+	for _, v := range c {
+		if v.key == key {
+			return v.value, true
+		}
+	}
+	return nil, false
+}
+
+// matches reports whether the combination has every value of entry
+func (c combination) matches(entry map[string]interface{}) bool {
+	for key, want := range entry {
+		got, ok := c.get(key)
+		if !ok || fmt.Sprint(got) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
+
+// combinations returns the cartesian product of the dimensions, minus
+// excluded combinations, plus included ones
+func (m *Matrix) combinations() ([]combination, error) {
+	keys := make(map[string]bool)
+	for _, dim := range m.Dimensions {
+		if len(dim.Values) == 0 {
+			return nil, fmt.Errorf("matrix %s has no values", dim.Key)
+		}
+		keys[dim.Key] = true
+	}
+	for i, entry := range m.Exclude {
+		for key := range entry {
+			if !keys[key] {
+				return nil, fmt.Errorf("matrix exclude entry %d uses unknown key %s", i+1, key)
+			}
+		}
+	}
+
+	var product []combination
+	if len(m.Dimensions) > 0 {
+		product = []combination{nil}
+	}
+	for _, dim := range m.Dimensions {
+		next := make([]combination, 0, len(product)*len(dim.Values))
+		for _, combo := range product {
+			for _, value := range dim.Values {
+				extended := append(append(combination(nil), combo...), matrixValue{dim.Key, value})
+				next = append(next, extended)
+			}
+		}
+		product = next
+	}
+
+	var result []combination
+	for _, combo := range product {
+		excluded := false
+		for _, entry := range m.Exclude {
+			if combo.matches(entry) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			result = append(result, combo)
+		}
+	}
+
+	for _, entry := range m.Include {
+		var combo combination
+		for _, dim := range m.Dimensions {
+			if value, ok := entry[dim.Key]; ok {
+				combo = append(combo, matrixValue{dim.Key, value})
+			}
+		}
+		for _, key := range sortedKeys(entry) {
+			if !keys[key] {
+				combo = append(combo, matrixValue{key, entry[key]})
+			}
+		}
+		if len(combo) > 0 {
+			result = append(result, combo)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("matrix produces no jobs")
+	}
+	return result, nil
+}
+
+// sortedKeys returns a map's keys in sorted order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var matrixRef = refPattern("matrix")
+
+// refs returns the resolver for the combination's ${matrix.key} references
+func (c combination) refs() refs {
+	return refs{scope: "matrix", kind: "key", pattern: matrixRef, get: c.get}
+}
+
+// slug returns the combination's values joined into a filename-safe suffix
+func (c combination) slug() string {
+	parts := make([]string, 0, len(c))
+	for _, v := range c {
+		if part := slugify(fmt.Sprint(v.value)); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+var slugUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify lowercases s, replaces runs of other characters with "-" and limits
+// the length to keep derived IDs and filenames readable
+func slugify(s string) string {
+	s = strings.Trim(slugUnsafe.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if len(s) > 24 {
+		s = strings.TrimRight(s[:24], "-")
+	}
+	return s
+}
+
+// expandMatrices replaces every job that has a matrix with one job per
+// combination
+func expandMatrices(manifest *BatchManifest) error {
+	jobs := make([]BatchJob, 0, len(manifest.Jobs))
+	for i, job := range manifest.Jobs {
+		if job.Matrix == nil {
+			jobs = append(jobs, job)
+			continue
+		}
+
+		expanded, err := expandJob(job)
+		if err != nil {
+			name := job.ID
+			if name == "" {
+				name = fmt.Sprintf("at index %d", i)
+			}
+			return fmt.Errorf("job %s: %w", name, err)
+		}
+		jobs = append(jobs, expanded...)
+	}
+	manifest.Jobs = jobs
+	return nil
+}
+
+// expandJob builds the jobs for each combination of a job's matrix. The id
+// and output get the combination's slug appended unless they reference
+// matrix values themselves.
+func expandJob(job BatchJob) ([]BatchJob, error) {
+	combos, err := job.Matrix.combinations()
+	if err != nil {
+		return nil, err
+	}
+
+	// The ID each combination derives, so that the fallback for values that
+	// slugify alike avoids the IDs of later combinations too
+	ids := make([]string, len(combos))
+	derived := make(map[string]bool, len(combos))
+	for n, combo := range combos {
+		if ids[n], err = combo.refs().substitute(job.ID); err != nil {
+			return nil, err
+		}
+		if !matrixRef.MatchString(job.ID) {
+			ids[n] = joinSlug(job.ID, combo.slug())
+		}
+		derived[ids[n]] = true
+	}
+
+	seen := make(map[string]bool)
+	jobs := make([]BatchJob, 0, len(combos))
+	for n, combo := range combos {
+		ref := combo.refs()
+		expanded := job
+		expanded.Matrix = nil
+
+		// Values that slugify alike: fall back to the first free number,
+		// starting at the combination's own
+		expanded.ID = ids[n]
+		number := ""
+		for k := n + 1; seen[expanded.ID]; k++ {
+			if id := fmt.Sprintf("%s-%d", ids[n], k); !seen[id] && !derived[id] {
+				expanded.ID, number = id, fmt.Sprint(k)
+			}
+		}
+		seen[expanded.ID] = true
+
+		if expanded.Output, err = ref.substitute(job.Output); err != nil {
+			return nil, err
+		}
+		if job.Output != "" {
+			ext := filepath.Ext(expanded.Output)
+			base := strings.TrimSuffix(expanded.Output, ext)
+			if !matrixRef.MatchString(job.Output) {
+				base = joinSlug(base, combo.slug())
+			}
+			expanded.Output = joinSlug(base, number) + ext
+		}
+
+		expanded.Options = make(map[string]interface{}, len(job.Options)+len(combo))
+		for key, value := range job.Options {
+			if expanded.Options[key], err = ref.substituteValue(value); err != nil {
+				return nil, err
+			}
+		}
+		for _, v := range combo {
+			expanded.Options[v.key] = v.value
+		}
+
+		if expanded.Vars, err = ref.substituteAll(job.Vars); err != nil {
+			return nil, err
+		}
+		if expanded.DependsOn, err = ref.substituteList(job.DependsOn); err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, expanded)
+	}
+	return jobs, nil
+}
+
+func joinSlug(base, slug string) string {
+	switch {
+	case slug == "":
+		return base
+	case base == "":
+		return slug
+	default:
+		return base + "-" + slug
+	}
+}
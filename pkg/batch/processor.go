@@ -2,9 +2,13 @@ package batch
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/hooks"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
 )
 
 // JobExecutor is an interface for executing batch jobs
@@ -14,19 +18,39 @@ type JobExecutor interface {
 
 // JobResult represents the result of a batch job execution
 type JobResult struct {
-	JobID     string        `json:"job_id"`
-	Success   bool          `json:"success"`
-	Output    string        `json:"output,omitempty"`
-	Error     string        `json:"error,omitempty"`
-	Duration  time.Duration `json:"duration"`
-	StartTime time.Time     `json:"start_time"`
-	EndTime   time.Time     `json:"end_time"`
+	JobID     string         `json:"job_id"`
+	Success   bool           `json:"success"`
+	Output    string         `json:"output,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	Duration  time.Duration  `json:"duration"`
+	StartTime time.Time      `json:"start_time"`
+	EndTime   time.Time      `json:"end_time"`
+	Hooks     []hooks.Result `json:"hooks,omitempty"`    // Post-download hook outcomes
+	Template  string         `json:"template,omitempty"` // Prompt template and version the job used
+	Skipped   bool           `json:"skipped,omitempty"`  // Not run because a dependency did not succeed
+
+	// ErrorClass is the retry class of Error: transient, safety or other
+	ErrorClass string `json:"error_class,omitempty"`
+	// Attempts records every run of a job whose retry policy allows retries
+	Attempts []JobAttempt `json:"attempts,omitempty"`
+
+	// What was run, for reports
+	Type        string                 `json:"type,omitempty"`
+	Model       string                 `json:"model,omitempty"`
+	OperationID string                 `json:"operation_id,omitempty"`
+	Options     map[string]interface{} `json:"options,omitempty"` // With job references resolved
 }
 
 // Processor handles concurrent execution of batch jobs
 type Processor struct {
 	executor    JobExecutor
 	Concurrency int
+	observer    veo3.Observer
+	defaults    JobDefaults
+
+	eventMu   sync.Mutex // guards handlers
+	deliverMu sync.Mutex // serializes event delivery
+	handlers  []func(Event)
 }
 
 // BatchSummary provides statistics about batch execution
@@ -34,6 +58,7 @@ type BatchSummary struct {
 	TotalJobs      int           `json:"total_jobs"`
 	SuccessfulJobs int           `json:"successful_jobs"`
 	FailedJobs     int           `json:"failed_jobs"`
+	SkippedJobs    int           `json:"skipped_jobs,omitempty"`
 	TotalDuration  time.Duration `json:"total_duration"`
 	Results        []JobResult   `json:"results"`
 }
@@ -47,127 +72,256 @@ func NewProcessor(executor JobExecutor, concurrency int) *Processor {
 	return &Processor{
 		executor:    executor,
 		Concurrency: concurrency,
+		observer:    veo3.NopObserver{},
+	}
+}
+
+// SetObserver sets the observer notified of every job outcome
+func (p *Processor) SetObserver(observer veo3.Observer) {
+	if observer == nil {
+		observer = veo3.NopObserver{}
 	}
+	p.observer = observer
+}
+
+// SetJobDefaults sets the model and duration budget estimates assume for jobs
+// that do not set them
+func (p *Processor) SetJobDefaults(defaults JobDefaults) {
+	p.defaults = defaults
 }
 
-// ProcessManifest processes all jobs in a manifest with concurrency control
+// ProcessManifest processes all jobs in a manifest with concurrency control.
+// A job starts once every job it depends on has succeeded; jobs downstream of
+// a failure are skipped. Failed jobs are retried as their retry policy
+// allows. Once submitting another job would exceed the manifest's budget, the
+// remaining jobs are skipped, as they are when the run is cancelled or stops
+// on an error; jobs already running are waited for. Event handlers see the
+// run as it happens.
 func (p *Processor) ProcessManifest(ctx context.Context, manifest *BatchManifest) ([]JobResult, error) {
+	startTime := time.Now()
+	results, err := p.run(ctx, manifest)
+	p.emit(&BatchFinishedEvent{Time: time.Now(), Results: results, Duration: time.Since(startTime), Err: err})
+	return results, err
+}
+
+// run processes the jobs of a manifest
+func (p *Processor) run(ctx context.Context, manifest *BatchManifest) ([]JobResult, error) {
 	// Use manifest's concurrency if set
 	concurrency := manifest.Concurrency
 	if concurrency < 1 {
 		concurrency = p.Concurrency
 	}
 
+	graph, err := newJobGraph(manifest.Jobs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	budget := &budgetTracker{budget: manifest.Budget, defaults: p.defaults}
+	for _, job := range manifest.Jobs {
+		dependsOn, _ := job.Dependencies()
+		p.emit(&JobQueuedEvent{Time: time.Now(), JobID: job.ID, Type: job.Type, DependsOn: dependsOn})
+	}
+
 	// Create cancellable context for stopping on error
 	workerCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	// Create channels for job distribution and result collection
-	jobChan := make(chan BatchJob, len(manifest.Jobs))
-	resultChan := make(chan JobResult, len(manifest.Jobs))
-	errorChan := make(chan error, 1)
-
-	// Fill job channel
-	for _, job := range manifest.Jobs {
-		jobChan <- job
-	}
-	close(jobChan)
-
-	// Start worker pool
-	var wg sync.WaitGroup
-	for i := 0; i < concurrency; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			p.worker(workerCtx, jobChan, resultChan, errorChan, manifest.ContinueOnError)
-		}()
+	type completion struct {
+		index  int
+		result *JobResult
+		err    error
 	}
+	done := make(chan completion, len(manifest.Jobs))
 
-	// Wait for all workers to complete
-	go func() {
-		wg.Wait()
-		close(resultChan)
-		close(errorChan)
-	}()
-
-	// Collect results
 	var results []JobResult
 	var firstError error
+	var exhausted string // the budget cap that stopped submissions
+	running := 0
 
 	for {
-		select {
-		case result, ok := <-resultChan:
+		// Start ready jobs up to the concurrency limit, unless stopping on
+		// error, cancelled or out of budget
+		for firstError == nil && ctx.Err() == nil && exhausted == "" && running < concurrency {
+			i, ok := graph.next()
 			if !ok {
-				// All results collected
-				if firstError != nil && !manifest.ContinueOnError {
-					return results, firstError
-				}
-				return results, nil
+				break
+			}
+			job, err := graph.resolve(i)
+			if err != nil {
+				result := JobResult{JobID: job.ID, Error: err.Error()}
+				skipped := graph.finish(i, result)
+				results = append(results, result)
+				results = append(results, skipped...)
+				p.finished(result)
+				p.finished(skipped...)
+				continue
 			}
-			results = append(results, result)
-
-		case err, ok := <-errorChan:
-			if ok && err != nil && firstError == nil {
-				firstError = err
-				if !manifest.ContinueOnError {
-					// Cancel context to stop other workers
-					cancel()
-					// Continue collecting results from workers that already started
-				}
+			if exhausted = budget.reserve(job); exhausted != "" {
+				break
 			}
 
-		case <-ctx.Done():
-			return results, fmt.Errorf("batch processing cancelled: %w", ctx.Err())
+			running++
+			go func(i int, job BatchJob) {
+				result, err := p.execute(workerCtx, job, manifest.RetryPolicy(job), budget, manifest.ContinueOnError)
+				done <- completion{index: i, result: result, err: err}
+			}(i, job)
 		}
+
+		// Once nothing is running, skip the jobs that will not start
+		if running == 0 {
+			reason := ""
+			switch {
+			case ctx.Err() != nil:
+				reason = "skipped: batch cancelled"
+				firstError = fmt.Errorf("batch processing cancelled: %w", ctx.Err())
+			case firstError != nil:
+				reason = "skipped: batch stopped after an error"
+			case exhausted != "":
+				reason = "skipped: " + exhausted
+			}
+			if reason != "" {
+				skipped := graph.skipRemaining(reason)
+				results = append(results, skipped...)
+				p.finished(skipped...)
+			}
+			return results, firstError
+		}
+
+		// Running jobs are always waited for, so every job gets its final
+		// result before the run ends; cancellation reaches them through
+		// workerCtx
+		c := <-done
+		running--
+		if c.err != nil && firstError == nil {
+			firstError = c.err
+			// Cancel context to stop other workers
+			cancel()
+		}
+		skipped := graph.finish(c.index, *c.result)
+		results = append(results, *c.result)
+		results = append(results, skipped...)
+		p.finished(*c.result)
+		p.finished(skipped...)
 	}
 }
 
-// worker processes jobs from the job channel
-func (p *Processor) worker(ctx context.Context, jobs <-chan BatchJob, results chan<- JobResult, errors chan<- error, continueOnError bool) {
-	for job := range jobs {
-		// Check context cancellation
-		select {
-		case <-ctx.Done():
-			return
-		default:
+// execute runs one job, retrying it as its policy allows, and times it.
+// Executor errors become failed results, which are also returned with the
+// error unless continuing on error.
+func (p *Processor) execute(ctx context.Context, job BatchJob, policy RetryPolicy, budget *budgetTracker, continueOnError bool) (*JobResult, error) {
+	startTime := time.Now()
+	var attempts []JobAttempt
+	for n := 1; ; n++ {
+		run := job
+		if n > 1 && policy.VarySeed {
+			run = job.withRetrySeed(n - 1)
 		}
+		p.emit(&JobStartedEvent{Time: time.Now(), JobID: job.ID, Type: job.Type, Attempt: n})
+		result, err := p.attempt(p.withReporter(ctx, job.ID, n), run)
+		attempts = append(attempts, JobAttempt{
+			Attempt:     n,
+			Success:     result.Success,
+			StartTime:   result.StartTime,
+			Duration:    result.Duration,
+			OperationID: result.OperationID,
+			Seed:        run.seed(),
+			Error:       result.Error,
+			ErrorClass:  result.ErrorClass,
+		})
 
-		startTime := time.Now()
-		result, err := p.executor.Execute(ctx, job)
-		duration := time.Since(startTime)
-
-		if err != nil {
-			// Send error to error channel
-			if !continueOnError {
-				select {
-				case errors <- err:
-				default:
-				}
-				return
+		retry := !result.Success && n < policy.attempts() && policy.retries(result.ErrorClass) && ctx.Err() == nil
+		if retry {
+			if reason := budget.reserveRetry(run); reason != "" {
+				result.Error += " (not retried: " + reason + ")"
+				retry = false
 			}
-
-			// Create failed result
-			result = &JobResult{
-				JobID:     job.ID,
-				Success:   false,
-				Error:     err.Error(),
-				StartTime: startTime,
-				EndTime:   time.Now(),
-				Duration:  duration,
+		}
+		if retry {
+			delay := policy.delay(n)
+			p.emit(&JobRetryingEvent{
+				Time:        time.Now(),
+				JobID:       job.ID,
+				Attempt:     attempts[n-1],
+				MaxAttempts: policy.attempts(),
+				Delay:       delay,
+			})
+			select {
+			case <-time.After(delay):
+				continue
+			case <-ctx.Done():
+				// Cancelled while waiting; report the last attempt
 			}
-		} else if result != nil {
-			// Create a copy to avoid modifying the original result
-			// This prevents data races when executors return shared pointers
-			resultCopy := *result
-			resultCopy.StartTime = startTime
-			resultCopy.EndTime = time.Now()
-			resultCopy.Duration = duration
-			result = &resultCopy
 		}
 
-		if result != nil {
-			results <- *result
+		result.StartTime = startTime
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(startTime)
+		if policy.attempts() > 1 {
+			result.Attempts = attempts
 		}
+		result.describe(run)
+
+		p.observer.BatchJobCompleted(ctx, veo3.BatchJobEvent{
+			JobID:    job.ID,
+			Type:     job.Type,
+			Success:  result.Success,
+			Start:    startTime,
+			Duration: result.Duration,
+			Err:      result.Error,
+		})
+		if err != nil && !continueOnError {
+			return result, err
+		}
+		return result, nil
+	}
+}
+
+// attempt runs a job once. Executor errors are returned alongside a failed
+// result, and failures are classified for the retry policy.
+func (p *Processor) attempt(ctx context.Context, job BatchJob) (*JobResult, error) {
+	startTime := time.Now()
+	result, err := p.executor.Execute(ctx, job)
+	duration := time.Since(startTime)
+
+	switch {
+	case err != nil:
+		result = &JobResult{
+			JobID:      job.ID,
+			Success:    false,
+			Error:      err.Error(),
+			ErrorClass: ClassifyError(err),
+		}
+	case result == nil:
+		result = &JobResult{
+			JobID: job.ID,
+			Error: "executor returned no result",
+		}
+	default:
+		// Create a copy to avoid modifying the original result
+		// This prevents data races when executors return shared pointers
+		resultCopy := *result
+		result = &resultCopy
+	}
+	if !result.Success && result.ErrorClass == "" {
+		result.ErrorClass = ClassifyError(errors.New(result.Error))
+	}
+	result.StartTime = startTime
+	result.EndTime = time.Now()
+	result.Duration = duration
+	return result, err
+}
+
+// describe records the job's type and options on its result, keeping any
+// the executor already set
+func (r *JobResult) describe(job BatchJob) {
+	if r.Type == "" {
+		r.Type = job.Type
+	}
+	if r.Options == nil {
+		r.Options = job.Options
+	}
+	if r.Model == "" {
+		r.Model = job.StringOption("model", "")
 	}
 }
 
@@ -180,9 +334,12 @@ func GenerateSummary(results []JobResult) BatchSummary {
 
 	var totalDuration time.Duration
 	for _, result := range results {
-		if result.Success {
+		switch {
+		case result.Success:
 			summary.SuccessfulJobs++
-		} else {
+		case result.Skipped:
+			summary.SkippedJobs++
+		default:
 			summary.FailedJobs++
 		}
 		totalDuration += result.Duration
@@ -200,16 +357,23 @@ func (s BatchSummary) FormatSummary() string {
 		successRate = float64(s.SuccessfulJobs) / float64(s.TotalJobs) * 100
 	}
 
+	skipped := ""
+	if s.SkippedJobs > 0 {
+		skipped = fmt.Sprintf("  Skipped: %d\n", s.SkippedJobs)
+	}
+
 	return fmt.Sprintf(
 		"Batch Processing Summary:\n"+
 			"  Total Jobs: %d\n"+
 			"  Successful: %d\n"+
 			"  Failed: %d\n"+
+			"%s"+
 			"  Success Rate: %.1f%%\n"+
 			"  Total Duration: %s",
 		s.TotalJobs,
 		s.SuccessfulJobs,
 		s.FailedJobs,
+		skipped,
 		successRate,
 		s.TotalDuration.Round(time.Second),
 	)
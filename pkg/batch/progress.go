@@ -0,0 +1,589 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+)
+
+// ProgressMode selects how a Progress reports a batch run
+type ProgressMode int
+
+const (
+	// ProgressTTY redraws one row per in-flight job plus overall counts
+	ProgressTTY ProgressMode = iota
+	// ProgressLines logs job transitions and periodic status lines
+	ProgressLines
+	// ProgressJSON writes one JSON event per line
+	ProgressJSON
+)
+
+// Job progress states
+const (
+	JobWaiting     = "waiting"
+	JobSubmitting  = "submitting"
+	JobGenerating  = "generating"
+	JobDownloading = "downloading"
+	JobRetrying    = "retrying"
+	JobSucceeded   = "done"
+	JobFailed      = "failed"
+	JobSkipped     = "skipped"
+)
+
+// JobProgress is the live state of one job
+type JobProgress struct {
+	JobID       string
+	Type        string
+	State       string
+	OperationID string
+	Started     time.Time
+	Polls       int
+	Percent     float64 // generation progress reported by the API, 0-100
+	Bytes       int64   // downloaded so far
+	TotalBytes  int64   // download size, 0 if unknown
+	Output      string
+	Error       string
+	Attempt     int // the attempt running or about to run, once a job is retried
+	MaxAttempts int
+}
+
+// ProgressEvent is a job transition or poll written in ProgressJSON mode
+type ProgressEvent struct {
+	Event       string          `json:"event"`
+	Time        time.Time       `json:"time"`
+	JobID       string          `json:"job_id,omitempty"`
+	Type        string          `json:"type,omitempty"`
+	State       string          `json:"state,omitempty"`
+	OperationID string          `json:"operation_id,omitempty"`
+	Polls       int             `json:"polls,omitempty"`
+	Percent     float64         `json:"percent,omitempty"`
+	Bytes       int64           `json:"bytes,omitempty"`
+	TotalBytes  int64           `json:"total_bytes,omitempty"`
+	Output      string          `json:"output,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	Attempt     int             `json:"attempt,omitempty"`
+	MaxAttempts int             `json:"max_attempts,omitempty"`
+	Elapsed     float64         `json:"elapsed_seconds,omitempty"`
+	Counts      *ProgressCounts `json:"counts,omitempty"`
+}
+
+// ProgressCounts are the number of jobs in each overall state
+type ProgressCounts struct {
+	Running int `json:"running"`
+	Done    int `json:"done"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+	Waiting int `json:"waiting"`
+}
+
+// Progress tracks every job of a batch run and reports it to a writer. Its
+// methods are safe for concurrent use and do nothing on a nil Progress.
+type Progress struct {
+	mu      sync.Mutex
+	out     io.Writer
+	mode    ProgressMode
+	jobs    map[string]*JobProgress
+	order   []string
+	started time.Time
+	idWidth int
+	drawn   int // lines of the live view currently on screen
+	stop    chan struct{}
+	stopped chan struct{}
+
+	// Width is the terminal width rows are truncated to; 0 disables truncation
+	Width int
+	// Now returns the current time; tests may replace it
+	Now func() time.Time
+}
+
+// NewProgress creates a progress tracker for the manifest's jobs
+func NewProgress(out io.Writer, mode ProgressMode, jobs []BatchJob) *Progress {
+	p := &Progress{
+		out:  out,
+		mode: mode,
+		jobs: make(map[string]*JobProgress, len(jobs)),
+		Now:  time.Now,
+	}
+	for _, job := range jobs {
+		p.jobs[job.ID] = &JobProgress{JobID: job.ID, Type: job.Type, State: JobWaiting}
+		p.order = append(p.order, job.ID)
+		p.idWidth = max(p.idWidth, min(len(job.ID), 24))
+	}
+	return p
+}
+
+// Start records the start of the run and refreshes the view every interval
+// (the live view) or logs status lines every interval (line mode) until Stop
+func (p *Progress) Start(interval time.Duration) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.started = p.Now()
+	p.stop = make(chan struct{})
+	p.stopped = make(chan struct{})
+	p.mu.Unlock()
+
+	go func() {
+		defer close(p.stopped)
+		if p.mode == ProgressJSON || interval <= 0 {
+			<-p.stop
+			return
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.mu.Lock()
+				switch p.mode {
+				case ProgressTTY:
+					p.redraw()
+				case ProgressLines:
+					p.logStatus()
+				}
+				p.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// Stop ends the refresh loop, records the jobs the results skipped and
+// reports the final counts
+func (p *Progress) Stop(results []JobResult) {
+	if p == nil {
+		return
+	}
+	if p.stop != nil {
+		close(p.stop)
+		<-p.stopped
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, result := range results {
+		if job := p.jobs[result.JobID]; job != nil && result.Skipped && job.State == JobWaiting {
+			job.State = JobSkipped
+			job.Error = result.Error
+			p.report(job, "job_skipped")
+		}
+	}
+
+	counts := p.counts()
+	switch p.mode {
+	case ProgressTTY:
+		p.clear()
+		_, _ = fmt.Fprintln(p.out, p.countsLine(counts))
+	case ProgressLines:
+		_, _ = fmt.Fprintf(p.out, "[%s] %s\n", p.clock(), p.countsLine(counts))
+	case ProgressJSON:
+		p.emit(ProgressEvent{Event: "batch_finished", Elapsed: p.elapsed().Seconds(), Counts: &counts})
+	}
+}
+
+// JobStarted marks a job as submitting. A retried job keeps the time its
+// first attempt started.
+func (p *Progress) JobStarted(jobID string) {
+	p.update(jobID, "job_started", func(job *JobProgress) {
+		job.State = JobSubmitting
+		if job.Started.IsZero() {
+			job.Started = p.Now()
+		}
+		job.OperationID, job.Polls, job.Percent, job.Bytes, job.TotalBytes = "", 0, 0, 0, 0
+	})
+}
+
+// JobRetrying records a failed attempt of a job that will be retried
+func (p *Progress) JobRetrying(retry *JobRetryingEvent) {
+	p.update(retry.JobID, "job_retrying", func(job *JobProgress) {
+		job.State = JobRetrying
+		job.Error = retry.Attempt.Error
+		job.Attempt, job.MaxAttempts = retry.Attempt.Attempt+1, retry.MaxAttempts
+		job.Percent = 0
+	})
+}
+
+// JobSubmitted records the operation a job is waiting for
+func (p *Progress) JobSubmitted(jobID, operationID string) {
+	p.update(jobID, "job_submitted", func(job *JobProgress) {
+		job.State = JobGenerating
+		job.OperationID = operationID
+	})
+}
+
+// JobPolled records a poll of the job's operation
+func (p *Progress) JobPolled(jobID string, operation *veo3.Operation) {
+	p.update(jobID, "job_polled", func(job *JobProgress) {
+		job.Polls++
+		if operation != nil && operation.Progress > job.Percent {
+			job.Percent = operation.Progress
+		}
+	})
+}
+
+// JobDownloading records download progress; total is 0 when the size is
+// unknown
+func (p *Progress) JobDownloading(jobID string, written, total int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	job := p.jobs[jobID]
+	if job == nil {
+		return
+	}
+
+	// Report the start of the download and then every 10%
+	step := func(n int64) int64 {
+		if total <= 0 {
+			return 0
+		}
+		return n * 10 / total
+	}
+	first := job.State != JobDownloading
+	advanced := step(written) != step(job.Bytes)
+
+	job.State = JobDownloading
+	job.Bytes, job.TotalBytes = written, total
+	switch {
+	case first:
+		p.report(job, "job_downloading")
+	case advanced:
+		p.report(job, "job_download_progress")
+	}
+}
+
+// JobFinished records a job's result
+func (p *Progress) JobFinished(result JobResult) {
+	event := "job_finished"
+	if result.Skipped {
+		event = "job_skipped"
+	}
+	p.update(result.JobID, event, func(job *JobProgress) {
+		job.Output, job.Error = result.Output, result.Error
+		switch {
+		case result.Success:
+			job.State = JobSucceeded
+		case result.Skipped:
+			job.State = JobSkipped
+		default:
+			job.State = JobFailed
+		}
+	})
+}
+
+// HandleEvent records a processor event; pass it to Processor.OnEvent
+func (p *Progress) HandleEvent(event Event) {
+	switch e := event.(type) {
+	case *JobStartedEvent:
+		p.JobStarted(e.JobID)
+	case *OperationSubmittedEvent:
+		p.JobSubmitted(e.JobID, e.OperationID)
+	case *JobProgressEvent:
+		if e.Stage == StageDownloading {
+			p.JobDownloading(e.JobID, e.Bytes, e.TotalBytes)
+		} else {
+			p.JobPolled(e.JobID, &veo3.Operation{Progress: e.Percent})
+		}
+	case *JobRetryingEvent:
+		p.JobRetrying(e)
+	case *JobFinishedEvent:
+		p.JobFinished(e.Result)
+	}
+}
+
+// Jobs returns a snapshot of every job's progress in manifest order
+func (p *Progress) Jobs() []JobProgress {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	jobs := make([]JobProgress, 0, len(p.order))
+	for _, id := range p.order {
+		jobs = append(jobs, *p.jobs[id])
+	}
+	return jobs
+}
+
+// Frame renders the live view: one row per in-flight job and the counts
+func (p *Progress) Frame() string {
+	if p == nil {
+		return ""
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return strings.Join(p.frameLines(), "\n") + "\n"
+}
+
+// update applies a change to a job and reports it
+func (p *Progress) update(jobID, event string, change func(job *JobProgress)) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	job := p.jobs[jobID]
+	if job == nil {
+		return
+	}
+	change(job)
+	p.report(job, event)
+}
+
+// report writes a job change in the current mode. The live view prints
+// finished jobs above the in-flight rows; line mode logs transitions but not
+// polls or download progress.
+func (p *Progress) report(job *JobProgress, event string) {
+	switch p.mode {
+	case ProgressJSON:
+		p.emit(ProgressEvent{
+			Event:       event,
+			JobID:       job.JobID,
+			Type:        job.Type,
+			State:       job.State,
+			OperationID: job.OperationID,
+			Polls:       job.Polls,
+			Percent:     job.Percent,
+			Bytes:       job.Bytes,
+			TotalBytes:  job.TotalBytes,
+			Output:      job.Output,
+			Error:       job.Error,
+			Attempt:     job.Attempt,
+			MaxAttempts: job.MaxAttempts,
+			Elapsed:     p.jobElapsed(job).Seconds(),
+		})
+	case ProgressLines:
+		if event == "job_polled" || event == "job_download_progress" {
+			return
+		}
+		_, _ = fmt.Fprintf(p.out, "[%s] %s\n", p.clock(), p.describe(job))
+	case ProgressTTY:
+		if finished(job.State) {
+			p.clear()
+			_, _ = fmt.Fprintln(p.out, p.describe(job))
+		}
+		p.redraw()
+	}
+}
+
+// describe summarizes a job transition in one line
+func (p *Progress) describe(job *JobProgress) string {
+	switch job.State {
+	case JobSubmitting:
+		return fmt.Sprintf("▶️  %s: submitting %s job", job.JobID, job.Type)
+	case JobGenerating:
+		return fmt.Sprintf("⏳ %s: generating (%s)", job.JobID, job.OperationID)
+	case JobDownloading:
+		return fmt.Sprintf("⬇️  %s: downloading %s", job.JobID, formatSize(job.TotalBytes))
+	case JobSucceeded:
+		return fmt.Sprintf("✅ %s: %s (%s)", job.JobID, job.Output, formatElapsed(p.jobElapsed(job)))
+	case JobRetrying:
+		return fmt.Sprintf("🔁 %s: retrying, attempt %d of %d", job.JobID, job.Attempt, job.MaxAttempts)
+	case JobSkipped:
+		return fmt.Sprintf("⏭️  %s: %s", job.JobID, job.Error)
+	case JobFailed:
+		return fmt.Sprintf("❌ %s: %s (%s)", job.JobID, job.Error, formatElapsed(p.jobElapsed(job)))
+	default:
+		return fmt.Sprintf("%s: %s", job.JobID, job.State)
+	}
+}
+
+// logStatus writes a status line for every in-flight job and the counts
+func (p *Progress) logStatus() {
+	for _, id := range p.order {
+		if job := p.jobs[id]; inFlight(job.State) {
+			_, _ = fmt.Fprintf(p.out, "[%s]   %s\n", p.clock(), strings.TrimSpace(p.row(job)))
+		}
+	}
+	_, _ = fmt.Fprintf(p.out, "[%s] %s\n", p.clock(), p.countsLine(p.counts()))
+}
+
+// redraw replaces the live view on screen
+func (p *Progress) redraw() {
+	if p.mode != ProgressTTY || p.stop == nil {
+		return
+	}
+	p.clear()
+	lines := p.frameLines()
+	for _, line := range lines {
+		_, _ = fmt.Fprintln(p.out, line)
+	}
+	p.drawn = len(lines)
+}
+
+// clear erases the live view so other output can be printed in its place
+func (p *Progress) clear() {
+	if p.drawn == 0 {
+		return
+	}
+	// Move to the first line of the view and clear to the end of the screen
+	_, _ = fmt.Fprintf(p.out, "\033[%dA\r\033[J", p.drawn)
+	p.drawn = 0
+}
+
+func (p *Progress) frameLines() []string {
+	var lines []string
+	for _, id := range p.order {
+		if job := p.jobs[id]; inFlight(job.State) {
+			lines = append(lines, p.row(job))
+		}
+	}
+	return append(lines, p.fit(p.countsLine(p.counts())))
+}
+
+// row renders one in-flight job: ID, state, operation, elapsed time and
+// poll or download progress
+func (p *Progress) row(job *JobProgress) string {
+	id := job.JobID
+	if len(id) > p.idWidth {
+		id = id[:p.idWidth-1] + "…"
+	}
+
+	var detail string
+	switch job.State {
+	case JobGenerating:
+		detail = fmt.Sprintf("poll %d", job.Polls)
+		if job.Percent > 0 {
+			detail += fmt.Sprintf(", %.0f%%", job.Percent)
+		}
+	case JobDownloading:
+		detail = formatSize(job.Bytes)
+		if job.TotalBytes > 0 {
+			detail = fmt.Sprintf("%s / %s (%.0f%%)", detail, formatSize(job.TotalBytes),
+				float64(job.Bytes)/float64(job.TotalBytes)*100)
+		}
+	case JobRetrying:
+		detail = fmt.Sprintf("attempt %d of %d next", job.Attempt, job.MaxAttempts)
+	}
+	if job.Attempt > 1 && job.State != JobRetrying {
+		detail = strings.TrimSpace(fmt.Sprintf("attempt %d/%d  %s", job.Attempt, job.MaxAttempts, detail))
+	}
+
+	row := strings.TrimRight(fmt.Sprintf("  %-*s  %-11s  %-16s  %6s  %s", p.idWidth, id, job.State,
+		shortOperationID(job.OperationID), formatElapsed(p.jobElapsed(job)), detail), " ")
+	return p.fit(row)
+}
+
+// fit truncates a line to the terminal width so redraws stay aligned
+func (p *Progress) fit(line string) string {
+	if p.Width <= 0 {
+		return line
+	}
+	runes := []rune(line)
+	if len(runes) < p.Width {
+		return line
+	}
+	return string(runes[:p.Width-2]) + "…"
+}
+
+func (p *Progress) counts() ProgressCounts {
+	var counts ProgressCounts
+	for _, job := range p.jobs {
+		switch job.State {
+		case JobWaiting:
+			counts.Waiting++
+		case JobSucceeded:
+			counts.Done++
+		case JobFailed:
+			counts.Failed++
+		case JobSkipped:
+			counts.Skipped++
+		default:
+			counts.Running++
+		}
+	}
+	return counts
+}
+
+func (p *Progress) countsLine(counts ProgressCounts) string {
+	line := fmt.Sprintf("Jobs: %d running, %d done, %d failed", counts.Running, counts.Done, counts.Failed)
+	if counts.Skipped > 0 {
+		line += fmt.Sprintf(", %d skipped", counts.Skipped)
+	}
+	if counts.Waiting > 0 {
+		line += fmt.Sprintf(", %d waiting", counts.Waiting)
+	}
+	return line + fmt.Sprintf(" (%s elapsed)", formatElapsed(p.elapsed()))
+}
+
+func (p *Progress) emit(event ProgressEvent) {
+	event.Time = p.Now()
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintln(p.out, string(data))
+}
+
+func (p *Progress) elapsed() time.Duration {
+	if p.started.IsZero() {
+		return 0
+	}
+	return p.Now().Sub(p.started)
+}
+
+func (p *Progress) jobElapsed(job *JobProgress) time.Duration {
+	if job.Started.IsZero() {
+		return 0
+	}
+	return p.Now().Sub(job.Started)
+}
+
+// clock formats the time since the run started, for line mode
+func (p *Progress) clock() string {
+	elapsed := p.elapsed().Round(time.Second)
+	return fmt.Sprintf("%02d:%02d", int(elapsed.Minutes()), int(elapsed.Seconds())%60)
+}
+
+func inFlight(state string) bool {
+	return state == JobSubmitting || state == JobGenerating || state == JobDownloading || state == JobRetrying
+}
+
+func finished(state string) bool {
+	return state == JobSucceeded || state == JobFailed || state == JobSkipped
+}
+
+// shortOperationID drops the resource path of an operation name
+func shortOperationID(id string) string {
+	id = id[strings.LastIndex(id, "/")+1:]
+	if len(id) > 16 {
+		id = id[:15] + "…"
+	}
+	return id
+}
+
+// formatElapsed formats a duration as 45s, 3m05s or 1h02m
+func formatElapsed(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+// formatSize formats a byte count for display
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
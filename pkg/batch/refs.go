@@ -0,0 +1,96 @@
+package batch
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// refPattern matches ${scope.name} references
+func refPattern(scope string) *regexp.Regexp {
+	return regexp.MustCompile(`\$\{` + regexp.QuoteMeta(scope) + `\.([^}]+)\}`)
+}
+
+// refs resolves ${scope.name} references in job fields, e.g. ${matrix.seed}
+// or ${row.sku}
+type refs struct {
+	scope   string
+	kind    string // what a name refers to, for error messages
+	pattern *regexp.Regexp
+	get     func(name string) (interface{}, bool)
+}
+
+// substitute replaces the references in s
+func (r refs) substitute(s string) (string, error) {
+	var missing string
+	result := r.pattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := r.pattern.FindStringSubmatch(ref)[1]
+		value, ok := r.get(name)
+		if !ok {
+			missing = name
+			return ref
+		}
+		return fmt.Sprint(value)
+	})
+	if missing != "" {
+		return "", fmt.Errorf("unknown %s %s in ${%s.%s}", r.scope, r.kind, r.scope, missing)
+	}
+	return result, nil
+}
+
+// substituteValue replaces the references in an option value. A string that
+// is exactly one reference takes the referenced value's type.
+func (r refs) substituteValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if match := r.pattern.FindStringSubmatch(v); match != nil && match[0] == v {
+			if resolved, ok := r.get(match[1]); ok {
+				return resolved, nil
+			}
+		}
+		return r.substitute(v)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			substituted, err := r.substituteValue(item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = substituted
+		}
+		return result, nil
+	default:
+		return value, nil
+	}
+}
+
+// substituteAll replaces the references in every value of a map
+func (r refs) substituteAll(values map[string]string) (map[string]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		substituted, err := r.substitute(value)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = substituted
+	}
+	return result, nil
+}
+
+// substituteList replaces the references in every item of a list
+func (r refs) substituteList(values []string) ([]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+	result := make([]string, len(values))
+	for i, value := range values {
+		substituted, err := r.substitute(value)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = substituted
+	}
+	return result, nil
+}
@@ -0,0 +1,400 @@
+package batch
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Report formats
+const (
+	ReportHTML     = "html"
+	ReportMarkdown = "md"
+	ReportJUnit    = "junit"
+)
+
+// ParseReportFormat normalizes a report format name
+func ParseReportFormat(name string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "html":
+		return ReportHTML, nil
+	case "md", "markdown":
+		return ReportMarkdown, nil
+	case "junit", "xml":
+		return ReportJUnit, nil
+	default:
+		return "", fmt.Errorf("unsupported report format %q (use html, md or junit)", name)
+	}
+}
+
+// ReportExtension returns the file extension for a report format
+func ReportExtension(format string) string {
+	switch format {
+	case ReportHTML:
+		return ".html"
+	case ReportJUnit:
+		return ".xml"
+	default:
+		return ".md"
+	}
+}
+
+// WriteReport writes a report of a batch run in the given format
+func WriteReport(w io.Writer, format string, summary BatchSummary) error {
+	switch format {
+	case ReportHTML:
+		return writeHTMLReport(w, summary)
+	case ReportMarkdown:
+		return writeMarkdownReport(w, summary)
+	case ReportJUnit:
+		return writeJUnitReport(w, summary)
+	default:
+		return fmt.Errorf("unsupported report format %q (use html, md or junit)", format)
+	}
+}
+
+// ModelStats are the timing statistics of the jobs run with one model
+type ModelStats struct {
+	Model     string
+	Jobs      int
+	Succeeded int
+	Failed    int
+	Total     time.Duration
+	Min       time.Duration
+	Max       time.Duration
+}
+
+// Mean returns the average job duration
+func (s ModelStats) Mean() time.Duration {
+	if s.Jobs == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Jobs)
+}
+
+// ModelStatistics groups the jobs that ran by model, sorted by model name.
+// Skipped jobs are not counted.
+func (s BatchSummary) ModelStatistics() []ModelStats {
+	byModel := make(map[string]*ModelStats)
+	for _, result := range s.Results {
+		if result.Skipped {
+			continue
+		}
+		model := result.Model
+		if model == "" {
+			model = "(default)"
+		}
+		stats := byModel[model]
+		if stats == nil {
+			stats = &ModelStats{Model: model, Min: result.Duration}
+			byModel[model] = stats
+		}
+		stats.Jobs++
+		if result.Success {
+			stats.Succeeded++
+		} else {
+			stats.Failed++
+		}
+		stats.Total += result.Duration
+		stats.Min = min(stats.Min, result.Duration)
+		stats.Max = max(stats.Max, result.Duration)
+	}
+
+	models := make([]ModelStats, 0, len(byModel))
+	for _, stats := range byModel {
+		models = append(models, *stats)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Model < models[j].Model })
+	return models
+}
+
+// Status returns "succeeded", "failed" or "skipped"
+func (r JobResult) Status() string {
+	switch {
+	case r.Success:
+		return "succeeded"
+	case r.Skipped:
+		return "skipped"
+	default:
+		return "failed"
+	}
+}
+
+// Prompt returns the prompt the job ran with
+func (r JobResult) Prompt() string {
+	if prompt, ok := r.Options["prompt"]; ok && prompt != nil {
+		return fmt.Sprint(prompt)
+	}
+	return ""
+}
+
+// Parameters returns the job's options other than the prompt as sorted
+// "key: value" pairs
+func (r JobResult) Parameters() []string {
+	var params []string
+	for _, key := range sortedKeys(r.Options) {
+		if key != "prompt" {
+			params = append(params, fmt.Sprintf("%s: %v", key, r.Options[key]))
+		}
+	}
+	return params
+}
+
+func successRate(s BatchSummary) float64 {
+	if s.TotalJobs == 0 {
+		return 0
+	}
+	return float64(s.SuccessfulJobs) / float64(s.TotalJobs) * 100
+}
+
+// overview summarizes the run in one line
+func overview(s BatchSummary) string {
+	line := fmt.Sprintf("%d jobs: %d succeeded, %d failed", s.TotalJobs, s.SuccessfulJobs, s.FailedJobs)
+	if s.SkippedJobs > 0 {
+		line += fmt.Sprintf(", %d skipped", s.SkippedJobs)
+	}
+	return line + fmt.Sprintf(" · success rate %.1f%% · total job time %s", successRate(s), formatElapsed(s.TotalDuration))
+}
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"elapsed": formatElapsed,
+	"src": func(path string) string {
+		return (&url.URL{Path: filepath.ToSlash(path)}).String()
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Batch Report</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem auto; max-width: 64rem; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5rem; }
+th, td { border: 1px solid #ddd; padding: 0.3rem 0.6rem; text-align: left; }
+th { background: #f4f4f4; }
+section.job { border: 1px solid #ddd; border-left-width: 6px; border-radius: 4px; padding: 0.5rem 1rem; margin-bottom: 1rem; }
+section.succeeded { border-left-color: #2e7d32; }
+section.failed { border-left-color: #c62828; }
+section.skipped { border-left-color: #9e9e9e; }
+.status { font-size: 0.8em; text-transform: uppercase; color: #666; }
+dl { display: grid; grid-template-columns: max-content auto; gap: 0.2rem 1rem; }
+dt { font-weight: bold; }
+dd { margin: 0; }
+pre.error { background: #fdecea; padding: 0.5rem; white-space: pre-wrap; }
+video { max-width: 100%; max-height: 24rem; }
+</style>
+</head>
+<body>
+<h1>Batch Report</h1>
+<p>{{.Overview}}</p>
+
+<h2>Models</h2>
+<table>
+<tr><th>Model</th><th>Jobs</th><th>Succeeded</th><th>Failed</th><th>Mean</th><th>Min</th><th>Max</th></tr>
+{{- range .Models}}
+<tr><td>{{.Model}}</td><td>{{.Jobs}}</td><td>{{.Succeeded}}</td><td>{{.Failed}}</td><td>{{elapsed .Mean}}</td><td>{{elapsed .Min}}</td><td>{{elapsed .Max}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Jobs</h2>
+{{- range .Results}}
+<section class="job {{.Status}}">
+<h3>{{.JobID}} <span class="status">{{.Status}}</span></h3>
+<dl>
+{{- with .Type}}<dt>Type</dt><dd>{{.}}</dd>{{end}}
+{{- with .Model}}<dt>Model</dt><dd>{{.}}</dd>{{end}}
+{{- with .Template}}<dt>Template</dt><dd>{{.}}</dd>{{end}}
+{{- with .Prompt}}<dt>Prompt</dt><dd>{{.}}</dd>{{end}}
+{{- with .Parameters}}<dt>Parameters</dt><dd>{{range $i, $p := .}}{{if $i}}<br>{{end}}<code>{{$p}}</code>{{end}}</dd>{{end}}
+{{- with .OperationID}}<dt>Operation</dt><dd><code>{{.}}</code></dd>{{end}}
+{{- if not .Skipped}}<dt>Duration</dt><dd>{{elapsed .Duration}}</dd>{{end}}
+{{- with .Attempts}}<dt>Attempts</dt><dd>{{range $i, $a := .}}{{if $i}}<br>{{end}}{{$a}}{{end}}</dd>{{end}}
+{{- with .Output}}<dt>Output</dt><dd><a href="{{src .}}">{{.}}</a></dd>{{end}}
+</dl>
+{{- with .Error}}
+<pre class="error">{{.}}</pre>
+{{- end}}
+{{- if and .Success .Output}}
+<video src="{{src .Output}}" controls preload="metadata"></video>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+func writeHTMLReport(w io.Writer, summary BatchSummary) error {
+	return htmlReport.Execute(w, struct {
+		BatchSummary
+		Overview string
+		Models   []ModelStats
+	}{summary, overview(summary), summary.ModelStatistics()})
+}
+
+// mdCell escapes text for a Markdown table cell
+func mdCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func writeMarkdownReport(w io.Writer, summary BatchSummary) error {
+	var b strings.Builder
+	b.WriteString("# Batch Report\n\n")
+	b.WriteString(overview(summary) + "\n\n")
+
+	b.WriteString("## Models\n\n")
+	b.WriteString("| Model | Jobs | Succeeded | Failed | Mean | Min | Max |\n")
+	b.WriteString("|-------|-----:|----------:|-------:|-----:|----:|----:|\n")
+	for _, stats := range summary.ModelStatistics() {
+		fmt.Fprintf(&b, "| %s | %d | %d | %d | %s | %s | %s |\n", mdCell(stats.Model), stats.Jobs,
+			stats.Succeeded, stats.Failed, formatElapsed(stats.Mean()), formatElapsed(stats.Min), formatElapsed(stats.Max))
+	}
+
+	b.WriteString("\n## Jobs\n\n")
+	b.WriteString("| Job | Status | Type | Model | Duration | Operation | Output |\n")
+	b.WriteString("|-----|--------|------|-------|---------:|-----------|--------|\n")
+	for _, result := range summary.Results {
+		duration := ""
+		if !result.Skipped {
+			duration = formatElapsed(result.Duration)
+		}
+		output := ""
+		if result.Output != "" {
+			output = fmt.Sprintf("[%s](%s)", mdCell(result.Output), (&url.URL{Path: filepath.ToSlash(result.Output)}).String())
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n", mdCell(result.JobID), result.Status(),
+			mdCell(result.Type), mdCell(result.Model), duration, mdCell(result.OperationID), output)
+	}
+
+	for _, result := range summary.Results {
+		fmt.Fprintf(&b, "\n### %s (%s)\n\n", result.JobID, result.Status())
+		if prompt := result.Prompt(); prompt != "" {
+			fmt.Fprintf(&b, "- **Prompt:** %s\n", strings.Join(strings.Fields(prompt), " "))
+		}
+		if params := result.Parameters(); len(params) > 0 {
+			fmt.Fprintf(&b, "- **Parameters:** `%s`\n", strings.Join(params, "`, `"))
+		}
+		if result.Template != "" {
+			fmt.Fprintf(&b, "- **Template:** %s\n", result.Template)
+		}
+		if result.Error != "" {
+			fmt.Fprintf(&b, "- **Error:** %s\n", strings.Join(strings.Fields(result.Error), " "))
+		}
+		if len(result.Attempts) > 0 {
+			b.WriteString("- **Attempts:**\n")
+			for _, attempt := range result.Attempts {
+				fmt.Fprintf(&b, "  - %s\n", attempt)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut *junitText    `xml:"system-out,omitempty"`
+}
+
+type junitText struct {
+	Text string `xml:",cdata"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",cdata"`
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// writeJUnitReport writes one test case per job so CI systems treat failed
+// generations as failed tests
+func writeJUnitReport(w io.Writer, summary BatchSummary) error {
+	suite := junitTestSuite{
+		Name:     "veo3 batch",
+		Tests:    summary.TotalJobs,
+		Failures: summary.FailedJobs,
+		Skipped:  summary.SkippedJobs,
+		Time:     junitSeconds(summary.TotalDuration),
+	}
+	for _, result := range summary.Results {
+		class := "veo3.batch"
+		if result.Type != "" {
+			class += "." + result.Type
+		}
+		testCase := junitTestCase{
+			Name:      result.JobID,
+			ClassName: class,
+			Time:      junitSeconds(result.Duration),
+		}
+
+		var details []string
+		for _, field := range [][2]string{
+			{"prompt", result.Prompt()},
+			{"model", result.Model},
+			{"operation", result.OperationID},
+			{"output", result.Output},
+		} {
+			if field[1] != "" {
+				details = append(details, field[0]+": "+field[1])
+			}
+		}
+		for _, attempt := range result.Attempts {
+			details = append(details, attempt.String())
+		}
+		if len(details) > 0 {
+			testCase.SystemOut = &junitText{Text: strings.Join(details, "\n")}
+		}
+
+		switch result.Status() {
+		case "failed":
+			testCase.Failure = &junitMessage{Message: result.Error, Text: result.Error}
+		case "skipped":
+			testCase.Skipped = &junitMessage{Message: result.Error}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	data, err := xml.MarshalIndent(junitTestSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, data)
+	return err
+}
@@ -0,0 +1,221 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+)
+
+// Error classes a retry policy can retry
+const (
+	// ErrorTransient covers rate limits, server errors, timeouts and network
+	// failures
+	ErrorTransient = "transient"
+	// ErrorSafety covers requests or videos rejected by a safety filter
+	ErrorSafety = "safety"
+	// ErrorOther covers every other failure, such as an invalid request
+	ErrorOther = "other"
+)
+
+const (
+	defaultRetryBackoff    = 10 * time.Second
+	defaultRetryMaxBackoff = 5 * time.Minute
+)
+
+// RetryPolicy controls how often a failed job is run again. Each retry waits
+// Backoff, doubling after every attempt up to MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts int           `yaml:"max_attempts,omitempty" json:"max_attempts,omitempty"` // Including the first; 0 or 1 never retries
+	Backoff     time.Duration `yaml:"backoff,omitempty" json:"backoff,omitempty"`           // Default 10s
+	MaxBackoff  time.Duration `yaml:"max_backoff,omitempty" json:"max_backoff,omitempty"`   // Default 5m
+	RetryOn     []string      `yaml:"retry_on,omitempty" json:"retry_on,omitempty"`         // Error classes; default transient
+	// VarySeed adds the retry number to the job's seed option on each retry,
+	// so a rejected video is not generated again as it was
+	VarySeed bool `yaml:"vary_seed,omitempty" json:"vary_seed,omitempty"`
+}
+
+// Validate checks the policy's limits and error classes
+func (p RetryPolicy) Validate() error {
+	switch {
+	case p.MaxAttempts < 0:
+		return fmt.Errorf("max_attempts must not be negative")
+	case p.Backoff < 0:
+		return fmt.Errorf("backoff must not be negative")
+	case p.MaxBackoff < 0:
+		return fmt.Errorf("max_backoff must not be negative")
+	}
+	for _, class := range p.RetryOn {
+		switch class {
+		case ErrorTransient, ErrorSafety, ErrorOther:
+		default:
+			return fmt.Errorf("unknown retry_on error class %s (must be transient, safety, or other)", class)
+		}
+	}
+	return nil
+}
+
+// attempts returns the most times a job is run
+func (p RetryPolicy) attempts() int {
+	return max(p.MaxAttempts, 1)
+}
+
+// retries reports whether the policy retries an error class
+func (p RetryPolicy) retries(class string) bool {
+	if len(p.RetryOn) == 0 {
+		return class == ErrorTransient
+	}
+	for _, retryable := range p.RetryOn {
+		if retryable == class {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns how long to wait after the given attempt failed
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	backoff, limit := p.Backoff, p.MaxBackoff
+	if backoff == 0 {
+		backoff = defaultRetryBackoff
+	}
+	if limit == 0 {
+		limit = max(defaultRetryMaxBackoff, backoff)
+	}
+	for i := 1; i < attempt && backoff < limit; i++ {
+		backoff *= 2
+	}
+	return min(backoff, limit)
+}
+
+// RetryPolicy returns the policy a job runs with: its own, or else the
+// manifest's
+func (m *BatchManifest) RetryPolicy(job BatchJob) RetryPolicy {
+	switch {
+	case job.Retry != nil:
+		return *job.Retry
+	case m.Retry != nil:
+		return *m.Retry
+	}
+	return RetryPolicy{}
+}
+
+// JobAttempt records one run of a job
+type JobAttempt struct {
+	Attempt     int           `json:"attempt"`
+	Success     bool          `json:"success"`
+	StartTime   time.Time     `json:"start_time"`
+	Duration    time.Duration `json:"duration"`
+	OperationID string        `json:"operation_id,omitempty"`
+	Seed        *int          `json:"seed,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	ErrorClass  string        `json:"error_class,omitempty"`
+}
+
+// String describes the attempt in one line
+func (a JobAttempt) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "attempt %d ", a.Attempt)
+	if a.Success {
+		b.WriteString("succeeded")
+	} else {
+		b.WriteString("failed")
+		if a.ErrorClass != "" {
+			fmt.Fprintf(&b, " (%s)", a.ErrorClass)
+		}
+	}
+	fmt.Fprintf(&b, " after %s", a.Duration.Round(time.Second))
+	if a.Seed != nil {
+		fmt.Fprintf(&b, ", seed %d", *a.Seed)
+	}
+	if a.OperationID != "" {
+		fmt.Fprintf(&b, ", operation %s", a.OperationID)
+	}
+	if a.Error != "" {
+		fmt.Fprintf(&b, ": %s", strings.Join(strings.Fields(a.Error), " "))
+	}
+	return b.String()
+}
+
+// withRetrySeed returns the job to run for a retry with the seed option
+// advanced by the retry number. Jobs without a seed are returned as they
+// are, since the API already picks a new seed for each of their runs.
+func (j BatchJob) withRetrySeed(retry int) BatchJob {
+	if _, ok := j.Options["seed"]; !ok {
+		return j
+	}
+	options := make(map[string]interface{}, len(j.Options))
+	for key, value := range j.Options {
+		options[key] = value
+	}
+	options["seed"] = j.IntOption("seed", 0) + retry
+	j.Options = options
+	return j
+}
+
+// seed returns the job's seed option, or nil if it has none
+func (j BatchJob) seed() *int {
+	if _, ok := j.Options["seed"]; !ok {
+		return nil
+	}
+	seed := j.IntOption("seed", 0)
+	return &seed
+}
+
+// transientCodes are API status codes worth retrying: gRPC names, their
+// numeric codes and HTTP statuses
+var transientCodes = map[string]bool{
+	"RESOURCE_EXHAUSTED": true, "UNAVAILABLE": true, "INTERNAL": true, "DEADLINE_EXCEEDED": true, "ABORTED": true,
+	"4": true, "8": true, "10": true, "13": true, "14": true,
+	"429": true, "500": true, "502": true, "503": true, "504": true,
+}
+
+// ClassifyError returns the error class of a job failure: ErrorTransient,
+// ErrorSafety or ErrorOther
+func ClassifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, marker := range []string{"safety filter", "raimediafiltered"} {
+		if strings.Contains(message, marker) {
+			return ErrorSafety
+		}
+	}
+
+	var opErr *veo3.OperationError
+	if errors.As(err, &opErr) {
+		if opErr.Code == veo3.SafetyFilteredCode {
+			return ErrorSafety
+		}
+		if transientCodes[opErr.Code] {
+			return ErrorTransient
+		}
+		if status, ok := opErr.Details["http_code"].(int); ok && (status == 429 || status >= 500) {
+			return ErrorTransient
+		}
+		return ErrorOther
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return ErrorTransient
+	}
+
+	// Errors that reached the result as text
+	for _, marker := range []string{
+		"http 429", "http 500", "http 502", "http 503", "http 504",
+		"resource_exhausted", "unavailable", "deadline exceeded", "timeout",
+		"connection refused", "connection reset",
+	} {
+		if strings.Contains(message, marker) {
+			return ErrorTransient
+		}
+	}
+	return ErrorOther
+}
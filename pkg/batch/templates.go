@@ -0,0 +1,59 @@
+package batch
+
+import (
+	"fmt"
+
+	"github.com/jasongoecke/go-veo3/pkg/templates"
+)
+
+// TemplateLookup returns a saved template by reference ("name" or "name@version")
+type TemplateLookup func(name string) (*templates.Template, error)
+
+// ApplyTemplates expands jobs that name a template. The template's preset
+// supplies the job type and any option the job does not set, then the
+// manifest defaults fill in the rest. Its prompt is rendered with the job's
+// vars unless the job sets a prompt. Expanded jobs
+// record the template version in TemplateRef and are validated like any other
+// job.
+func ApplyTemplates(manifest *BatchManifest, lookup TemplateLookup) error {
+	for i := range manifest.Jobs {
+		job := &manifest.Jobs[i]
+		if job.Template == "" {
+			continue
+		}
+
+		template, err := lookup(job.Template)
+		if err != nil {
+			return fmt.Errorf("job %s: %w", job.ID, err)
+		}
+
+		if job.Type == "" {
+			job.Type = template.Preset.JobType()
+		}
+
+		options := template.Preset.Options(job.Type)
+		if _, ok := job.Options["prompt"]; !ok {
+			prompt, err := template.Render(job.Vars)
+			if err != nil {
+				return fmt.Errorf("job %s: template %s: %w", job.ID, job.Template, err)
+			}
+			if prompt != "" {
+				options["prompt"] = prompt
+			}
+		}
+		for key, value := range job.Options {
+			options[key] = value
+		}
+		for _, defaults := range job.pendingDefaults {
+			mergeOptions(options, defaults.ForType(job.Type))
+		}
+		job.Options = options
+		job.Template, job.Vars, job.pendingDefaults = "", nil, nil
+		job.TemplateRef = template.Ref()
+
+		if err := ValidateJob(*job); err != nil {
+			return fmt.Errorf("%w (after applying template %s)", err, template.Name)
+		}
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/jasongoecke/go-veo3/pkg/config"
+	"github.com/jasongoecke/go-veo3/pkg/hooks"
+	"github.com/jasongoecke/go-veo3/pkg/templates"
 	"github.com/jasongoecke/go-veo3/pkg/veo3"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -33,13 +35,17 @@ Maximum image size: 20MB`,
   veo3 animate image.jpg --resolution 1080p --duration 8
 
   # Save to specific directory
-  veo3 animate image.jpg --output ./videos/`,
-		Args: cobra.ExactArgs(1),
+  veo3 animate image.jpg --output ./videos/
+
+  # Use a template's prompt and preset (the image can come from the preset)
+  veo3 animate -t product-spin --vars product=sneaker`,
+		Args: cobra.RangeArgs(0, 1),
 		RunE: runAnimate,
 	}
 
 	// Add flags
 	animateCmd.Flags().StringP("prompt", "p", "", "Optional prompt to enhance the animation")
+	addTemplateFlags(animateCmd)
 	animateCmd.Flags().StringP("resolution", "r", "", "Resolution (720p or 1080p)")
 	animateCmd.Flags().IntP("duration", "d", 0, "Duration in seconds (4, 6, or 8)")
 	animateCmd.Flags().StringP("aspect-ratio", "a", "", "Aspect ratio (16:9 or 9:16)")
@@ -63,11 +69,12 @@ Maximum image size: 20MB`,
 
 // runAnimate handles image-to-video generation
 func runAnimate(cmd *cobra.Command, args []string) error {
-	imagePath := args[0]
-
 	// Load configuration using manager
-	manager := config.NewManager("")
+	manager := newConfigManager()
 	cfg, err := manager.Load()
+	if fatalConfigError(err) {
+		return err
+	}
 	if err != nil {
 		// Use defaults if config load fails
 		cfg = &config.Configuration{
@@ -80,13 +87,26 @@ func runAnimate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Get flag values with config fallbacks
-	prompt, _ := cmd.Flags().GetString("prompt")
-	resolution := getStringWithDefault(cmd, "resolution", cfg.DefaultResolution)
-	duration := getIntWithDefault(cmd, "duration", cfg.DefaultDuration)
-	aspectRatio := getStringWithDefault(cmd, "aspect-ratio", cfg.DefaultAspectRatio)
-	model := getStringWithDefault(cmd, "model", cfg.DefaultModel)
-	negativePrompt, _ := cmd.Flags().GetString("negative-prompt")
+	prompt, template, err := resolveTemplate(cmd, templates.TypeAnimate)
+	if err != nil {
+		return handleError(err, viper.GetBool("json"), false)
+	}
+	preset := templatePreset(template)
+
+	imagePath := preset.Image
+	if len(args) > 0 {
+		imagePath = args[0]
+	}
+	if imagePath == "" {
+		return handleError(fmt.Errorf("an image path is required (argument or template preset)"), viper.GetBool("json"), false)
+	}
+
+	// Get flag values with template preset and config fallbacks
+	resolution := getStringWithDefault(cmd, "resolution", valueOr(preset.Resolution, cfg.DefaultResolution))
+	duration := getIntWithDefault(cmd, "duration", intOr(preset.Duration, cfg.DefaultDuration))
+	aspectRatio := getStringWithDefault(cmd, "aspect-ratio", valueOr(preset.AspectRatio, cfg.DefaultAspectRatio))
+	model := getStringWithDefault(cmd, "model", valueOr(preset.Model, cfg.DefaultModel))
+	negativePrompt := getStringWithDefault(cmd, "negative-prompt", preset.NegativePrompt)
 	outputDir := getStringWithDefault(cmd, "output", cfg.OutputDirectory)
 	filename, _ := cmd.Flags().GetString("filename")
 	noWait, _ := cmd.Flags().GetBool("no-wait")
@@ -114,12 +134,7 @@ func runAnimate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiKey := viper.GetString("api-key")
-	if apiKey == "" {
-		apiKey = cfg.APIKey
-	}
-
-	client, err := veo3.NewClient(context.Background(), apiKey)
+	client, err := createVeo3Client(cfg)
 	if err != nil {
 		return handleError(err, jsonFormat, pretty)
 	}
@@ -144,6 +159,8 @@ func runAnimate(cmd *cobra.Command, args []string) error {
 		return handleError(err, jsonFormat, pretty)
 	}
 
+	recordTemplate(template, operation, nil)
+
 	// Handle async mode
 	if noWait {
 		return outputOperation(operation, jsonFormat, pretty)
@@ -158,18 +175,28 @@ func runAnimate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return handleError(err, jsonFormat, pretty)
 	}
+	recordTemplate(template, operation, nil)
 
 	// Download video if completed and not disabled
+	var outputPath string
 	if operation.Status == veo3.StatusDone && !noDownload {
 		if !jsonFormat {
 			fmt.Println("✓ Animation completed!")
 		}
 
-		err = downloadVideo(ctx, operation, outputDir, filename, jsonFormat)
+		video, err := downloadVideo(ctx, client, operation, outputDir, filename, jsonFormat)
 		if err != nil {
 			return handleError(err, jsonFormat, pretty)
 		}
+		outputPath = video.FilePath
+		describeVideo(video, &request.GenerationRequest)
+		recordTemplate(template, nil, video)
+		if _, err := runPostDownloadHooks(ctx, hooks.NewRunner(cfg.PostDownloadHooks), video, jsonFormat); err != nil {
+			return handleError(err, jsonFormat, pretty)
+		}
 	}
 
+	notifyWebhooks(ctx, newWebhookNotifier(cmd, cfg), operation, outputPath, "", jsonFormat)
+
 	return outputOperation(operation, jsonFormat, pretty)
 }
@@ -1,16 +1,24 @@
 package cli
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/jasongoecke/go-veo3/internal/logger"
 	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/jasongoecke/go-veo3/pkg/config"
+	"github.com/jasongoecke/go-veo3/pkg/hooks"
+	"github.com/jasongoecke/go-veo3/pkg/operations"
 	"github.com/jasongoecke/go-veo3/pkg/veo3"
+	"github.com/jasongoecke/go-veo3/pkg/webhooks"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // newBatchCmd creates the batch command group
@@ -45,6 +53,7 @@ Example manifest:
 	cmd.AddCommand(newBatchProcessCmd())
 	cmd.AddCommand(newBatchTemplateCmd())
 	cmd.AddCommand(newBatchRetryCmd())
+	cmd.AddCommand(newBatchFromDataCmd())
 
 	return cmd
 }
@@ -55,6 +64,7 @@ func newBatchProcessCmd() *cobra.Command {
 		concurrency   int
 		continueOnErr bool
 		outputDir     string
+		reports       []string
 	)
 
 	cmd := &cobra.Command{
@@ -68,20 +78,74 @@ configurable concurrency limits and error handling.
 Example:
   veo3 batch process manifest.yaml
   veo3 batch process jobs.yaml --concurrency 5
-  veo3 batch process batch.yaml --output-dir ./videos`,
+  veo3 batch process batch.yaml --output-dir ./videos
+  veo3 batch process batch.yaml --report html,junit
+  veo3 batch process batch.yaml --dry-run`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runBatchProcess(cmd, args, concurrency, continueOnErr, outputDir)
+			return runBatchProcess(cmd, args, concurrency, continueOnErr, outputDir, reports)
 		},
 	}
 
 	cmd.Flags().IntVar(&concurrency, "concurrency", 0, "Number of concurrent jobs (overrides manifest)")
 	cmd.Flags().BoolVar(&continueOnErr, "stop-on-error", false, "Stop processing on first error")
 	cmd.Flags().StringVar(&outputDir, "output-dir", "", "Output directory for all videos (overrides manifest)")
+	cmd.Flags().StringSliceVar(&reports, "report", nil, "Also write a report: html, md or junit (repeatable)")
+	addBudgetFlags(cmd, "overrides manifest")
+	cmd.Flags().Bool("dry-run", false, "Resolve and check every job and print the plan without calling the API")
 
 	return cmd
 }
 
+// addBudgetFlags registers the flags that cap a batch run
+func addBudgetFlags(cmd *cobra.Command, note string) {
+	cmd.Flags().Int("max-jobs", 0, "Maximum number of jobs to submit ("+note+")")
+	cmd.Flags().Int("max-video-seconds", 0, "Maximum seconds of video to generate ("+note+")")
+	cmd.Flags().Float64("max-cost", 0, "Maximum estimated cost in USD ("+note+")")
+}
+
+// applyBudgetFlags overrides a manifest's budget with the budget flags that
+// were set
+func applyBudgetFlags(cmd *cobra.Command, budget *batch.Budget) error {
+	flags := cmd.Flags()
+	if flags.Changed("max-jobs") {
+		budget.MaxJobs, _ = flags.GetInt("max-jobs")
+	}
+	if flags.Changed("max-video-seconds") {
+		budget.MaxVideoSeconds, _ = flags.GetInt("max-video-seconds")
+	}
+	if flags.Changed("max-cost") {
+		budget.MaxCost, _ = flags.GetFloat64("max-cost")
+	}
+	return budget.Validate()
+}
+
+// formatEstimate describes the jobs, seconds and cost of a budget estimate
+func formatEstimate(estimate batch.BudgetEstimate) string {
+	line := fmt.Sprintf("%d jobs, ~%ds of video", estimate.Jobs, estimate.VideoSeconds)
+	if len(estimate.Unpriced) == 0 {
+		line += fmt.Sprintf(", ~$%.2f", estimate.Cost)
+	}
+	return line
+}
+
+// formatBudget describes a budget estimate and the caps it is checked against
+func formatBudget(estimate batch.BudgetEstimate, budget batch.Budget) string {
+	line := formatEstimate(estimate)
+
+	var caps []string
+	if budget.MaxJobs > 0 {
+		caps = append(caps, fmt.Sprintf("max_jobs %d", budget.MaxJobs))
+	}
+	if budget.MaxVideoSeconds > 0 {
+		caps = append(caps, fmt.Sprintf("max_video_seconds %d", budget.MaxVideoSeconds))
+	}
+	if budget.MaxCost > 0 {
+		caps = append(caps, fmt.Sprintf("max_cost $%.2f", budget.MaxCost))
+	}
+	return fmt.Sprintf("%s (%s)", line, strings.Join(caps, ", "))
+}
+
 // newBatchTemplateCmd creates the batch template command
 func newBatchTemplateCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -134,7 +198,7 @@ Example:
 }
 
 // runBatchProcess processes a batch manifest file
-func runBatchProcess(cmd *cobra.Command, args []string, concurrency int, stopOnError bool, outputDir string) error {
+func runBatchProcess(cmd *cobra.Command, args []string, concurrency int, stopOnError bool, outputDir string, reports []string) error {
 	manifestPath := args[0]
 
 	// Load manifest
@@ -143,6 +207,26 @@ func runBatchProcess(cmd *cobra.Command, args []string, concurrency int, stopOnE
 		return fmt.Errorf("failed to parse manifest: %w", err)
 	}
 
+	return processBatch(cmd, manifest, manifestPath, concurrency, stopOnError, outputDir, reports)
+}
+
+// processBatch applies templates to a parsed manifest and processes its jobs.
+// source names the manifest in the output; reports lists the report formats
+// to write alongside the results file.
+func processBatch(cmd *cobra.Command, manifest *batch.BatchManifest, source string, concurrency int, stopOnError bool, outputDir string, reports []string) error {
+	formats := make([]string, 0, len(reports))
+	for _, name := range reports {
+		format, err := batch.ParseReportFormat(name)
+		if err != nil {
+			return err
+		}
+		formats = append(formats, format)
+	}
+
+	if err := batch.ApplyTemplates(manifest, lookupTemplate); err != nil {
+		return fmt.Errorf("failed to apply templates: %w", err)
+	}
+
 	// Override manifest settings with flags
 	if concurrency > 0 {
 		manifest.Concurrency = concurrency
@@ -153,32 +237,97 @@ func runBatchProcess(cmd *cobra.Command, args []string, concurrency int, stopOnE
 	if outputDir != "" {
 		manifest.OutputDirectory = outputDir
 	}
+	if err := applyBudgetFlags(cmd, &manifest.Budget); err != nil {
+		return err
+	}
+
+	jsonFormat := viper.GetBool("json")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if !jsonFormat && !dryRun {
+		fmt.Printf("Processing batch manifest: %s\n", source)
+		fmt.Printf("  Jobs: %d\n", len(manifest.Jobs))
+		fmt.Printf("  Concurrency: %d\n", manifest.Concurrency)
+		fmt.Printf("  Continue on error: %v\n", manifest.ContinueOnError)
+	}
+
+	// Load configuration for job defaults
+	manager := newConfigManager()
+	cfg, err := manager.Load()
+	if fatalConfigError(err) {
+		return err
+	}
+	if err != nil {
+		// Use defaults if config load fails
+		cfg = &config.Configuration{
+			DefaultModel:        config.DefaultModel,
+			DefaultResolution:   config.DefaultResolution,
+			DefaultAspectRatio:  config.DefaultAspectRatio,
+			DefaultDuration:     config.DefaultDuration,
+			OutputDirectory:     ".",
+			PollIntervalSeconds: config.DefaultPollInterval,
+		}
+	}
 
-	fmt.Printf("Processing batch manifest: %s\n", manifestPath)
-	fmt.Printf("  Jobs: %d\n", len(manifest.Jobs))
-	fmt.Printf("  Concurrency: %d\n", manifest.Concurrency)
-	fmt.Printf("  Continue on error: %v\n\n", manifest.ContinueOnError)
+	defaults := batch.JobDefaults{Model: cfg.DefaultModel, Duration: cfg.DefaultDuration}
+	if dryRun {
+		plan := planBatch(manifest, source, &RealJobExecutor{cfg: cfg, outputDir: manifest.OutputDirectory}, defaults)
+		if jsonFormat {
+			if err := outputJSON(plan); err != nil {
+				return err
+			}
+		} else {
+			printPlan(plan)
+		}
+		if count := plan.problemCount(); count > 0 {
+			return fmt.Errorf("dry run found %d problem(s)", count)
+		}
+		return nil
+	}
+
+	// Check the budget before anything is submitted
+	if !manifest.Budget.IsZero() {
+		estimate, err := manifest.Budget.Check(manifest.Jobs, defaults)
+		if err != nil {
+			return fmt.Errorf("budget exceeded: %w", err)
+		}
+		if !jsonFormat {
+			fmt.Printf("  Budget: %s\n", formatBudget(estimate, manifest.Budget))
+		}
+	}
+	if !jsonFormat {
+		fmt.Println()
+	}
 
 	// Create API client
-	client, err := createVeo3Client()
+	client, err := createVeo3Client(cfg)
 	if err != nil {
 		return err
 	}
 
+	progress, refresh := newBatchProgress(manifest.Jobs)
+
 	// Create executor
 	executor := &RealJobExecutor{
 		client:    client,
+		cfg:       cfg,
 		outputDir: manifest.OutputDirectory,
+		notifier:  newWebhookNotifier(cmd, cfg),
+		hooks:     hooks.NewRunner(cfg.PostDownloadHooks),
 	}
 
 	// Create processor
 	processor := batch.NewProcessor(executor, manifest.Concurrency)
+	processor.SetObserver(client.Observer())
+	processor.SetJobDefaults(defaults)
+	processor.OnEvent(progress.HandleEvent)
 
 	// Process manifest
 	ctx := cmd.Context()
 	startTime := time.Now()
 
+	progress.Start(refresh)
 	results, err := processor.ProcessManifest(ctx, manifest)
+	progress.Stop(results)
 
 	duration := time.Since(startTime)
 
@@ -186,13 +335,48 @@ func runBatchProcess(cmd *cobra.Command, args []string, concurrency int, stopOnE
 	summary := batch.GenerateSummary(results)
 
 	// Save results to JSON file
-	resultsFile := fmt.Sprintf("batch_results_%s.json", time.Now().Format("20060102_150405"))
+	timestamp := time.Now().Format("20060102_150405")
+	resultsFile := fmt.Sprintf("batch_results_%s.json", timestamp)
 	if err := saveResults(resultsFile, summary); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Failed to save results: %v\n", err)
-	} else {
+		resultsFile = ""
+	} else if !jsonFormat {
 		fmt.Printf("\n📊 Results saved to: %s\n", resultsFile)
 	}
 
+	reportFiles := make(map[string]string, len(formats))
+	for _, format := range formats {
+		reportFile := fmt.Sprintf("batch_report_%s%s", timestamp, batch.ReportExtension(format))
+		if err := saveReport(reportFile, format, summary); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to save %s report: %v\n", format, err)
+			continue
+		}
+		reportFiles[format] = reportFile
+		if !jsonFormat {
+			fmt.Printf("📄 Report saved to: %s\n", reportFile)
+		}
+	}
+
+	if jsonFormat {
+		event := map[string]interface{}{
+			"event":        "batch_summary",
+			"results_file": resultsFile,
+			"summary":      summary,
+		}
+		if len(reportFiles) > 0 {
+			event["report_files"] = reportFiles
+		}
+		line, _ := json.Marshal(event)
+		fmt.Println(string(line))
+		if err != nil {
+			return fmt.Errorf("batch processing completed with errors: %w", err)
+		}
+		if summary.FailedJobs+summary.SkippedJobs > 0 {
+			return fmt.Errorf("batch completed with %d failures and %d skipped jobs", summary.FailedJobs, summary.SkippedJobs)
+		}
+		return nil
+	}
+
 	// Display summary
 	fmt.Println("\n" + summary.FormatSummary())
 	fmt.Printf("  Elapsed Time: %s\n", duration.Round(time.Second))
@@ -200,15 +384,18 @@ func runBatchProcess(cmd *cobra.Command, args []string, concurrency int, stopOnE
 	// Display individual results
 	fmt.Println("\nJob Results:")
 	for _, result := range results {
-		status := "✅"
-		if !result.Success {
-			status = "❌"
+		switch {
+		case result.Success:
+			fmt.Printf("  ✅ %s: %s (%.1fs)\n", result.JobID, result.Output, result.Duration.Seconds())
+		case result.Skipped:
+			fmt.Printf("  ⏭️  %s: SKIPPED - %s\n", result.JobID, strings.TrimPrefix(result.Error, "skipped: "))
+		default:
+			fmt.Printf("  ❌ %s: FAILED - %s\n", result.JobID, result.Error)
 		}
-		fmt.Printf("  %s %s: ", status, result.JobID)
-		if result.Success {
-			fmt.Printf("%s (%.1fs)\n", result.Output, result.Duration.Seconds())
-		} else {
-			fmt.Printf("FAILED - %s\n", result.Error)
+		for _, hook := range result.Hooks {
+			if !hook.Success && !hook.Skipped {
+				fmt.Printf("      ⚠️  hook %s: %s\n", hook.Name, hook.Error)
+			}
 		}
 	}
 
@@ -216,9 +403,9 @@ func runBatchProcess(cmd *cobra.Command, args []string, concurrency int, stopOnE
 		return fmt.Errorf("\nBatch processing completed with errors: %w", err)
 	}
 
-	if summary.FailedJobs > 0 {
-		fmt.Printf("\n⚠️  %d job(s) failed. Use 'veo3 batch retry %s' to retry failed jobs.\n", summary.FailedJobs, resultsFile)
-		return fmt.Errorf("batch completed with %d failures", summary.FailedJobs)
+	if unfinished := summary.FailedJobs + summary.SkippedJobs; unfinished > 0 {
+		fmt.Printf("\n⚠️  %d job(s) failed or were skipped. Use 'veo3 batch retry %s' to retry them.\n", unfinished, resultsFile)
+		return fmt.Errorf("batch completed with %d failures and %d skipped jobs", summary.FailedJobs, summary.SkippedJobs)
 	}
 
 	fmt.Println("\n✅ All jobs completed successfully!")
@@ -275,64 +462,184 @@ func runBatchRetry(_ *cobra.Command, args []string, _ string, _ int) error {
 // RealJobExecutor implements JobExecutor interface for actual API calls
 type RealJobExecutor struct {
 	client    *veo3.Client
+	cfg       *config.Configuration
 	outputDir string
+	notifier  *webhooks.Notifier
+	hooks     *hooks.Runner
 }
 
 // Execute executes a batch job
 func (e *RealJobExecutor) Execute(ctx context.Context, job batch.BatchJob) (*batch.JobResult, error) {
 	result := &batch.JobResult{
-		JobID: job.ID,
+		JobID:    job.ID,
+		Template: job.TemplateRef,
+		Model:    job.StringOption("model", e.cfg.DefaultModel),
 	}
 
-	// Determine output path
-	outputPath := job.Output
-	if e.outputDir != "" {
-		outputPath = filepath.Join(e.outputDir, filepath.Base(job.Output))
+	outputPath := e.outputPath(job)
+
+	var operation *veo3.Operation
+	request, err := e.jobRequest(job)
+	if err == nil {
+		operation, err = e.submit(ctx, request)
 	}
 
-	// Execute based on job type
-	var err error
-	switch job.Type {
-	case "generate":
-		err = e.executeGenerate(ctx, job, outputPath)
-	case "animate":
-		err = e.executeAnimate(ctx, job, outputPath)
-	case "interpolate":
-		err = e.executeInterpolate(ctx, job, outputPath)
-	case "extend":
-		err = e.executeExtend(ctx, job, outputPath)
-	default:
-		return nil, fmt.Errorf("unknown job type: %s", job.Type)
+	log := e.client.Logger().With(logger.JobID(job.ID), "type", job.Type)
+	if err == nil {
+		batch.Reporter(ctx).OperationSubmitted(operation.ID)
+		result.OperationID = operation.ID
+		log = log.With(logger.OperationID(operation.ID), logger.Model(result.Model))
+		log.DebugContext(ctx, "job submitted")
+		err = e.waitAndDownload(ctx, job, operation, outputPath, result)
 	}
 
 	if err != nil {
+		log.DebugContext(ctx, "job failed", "error", err)
 		result.Success = false
 		result.Error = err.Error()
+		result.ErrorClass = batch.ClassifyError(err)
 		return result, nil // Return result with error info, not error
 	}
 
+	log.DebugContext(ctx, "job finished", "output", outputPath)
 	result.Success = true
 	result.Output = outputPath
 	return result, nil
 }
 
-// Helper methods for executing different job types
-func (e *RealJobExecutor) executeGenerate(ctx context.Context, job batch.BatchJob, output string) error {
-	// Implementation would call veo3 client generate method
-	// For now, return placeholder
-	return fmt.Errorf("generate execution not yet implemented")
+// waitAndDownload polls a submitted operation, downloads its video, runs
+// post-download hooks and notifies webhooks once it reaches a terminal state
+func (e *RealJobExecutor) waitAndDownload(ctx context.Context, job batch.BatchJob, operation *veo3.Operation, outputPath string, result *batch.JobResult) error {
+	operation, err := watchOperation(ctx, e.client, operation.ID, func(operation *veo3.Operation) {
+		batch.Reporter(ctx).Polled(operation)
+	})
+	if err != nil {
+		return err
+	}
+
+	if operation.Status != veo3.StatusDone {
+		notifyWebhooks(ctx, e.notifier, operation, "", job.ID, true)
+		if operation.Error != nil {
+			return operation.Error
+		}
+		return fmt.Errorf("operation %s finished with status %s", operation.ID, operation.Status)
+	}
+
+	downloader := operations.NewDownloaderForClient(e.client, false)
+	downloader.SetProgressFunc(func(written, total int64) {
+		batch.Reporter(ctx).Downloading(written, total)
+	})
+	video, err := downloader.DownloadVideo(ctx, operation, outputPath)
+	if err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+
+	video.Prompt = job.StringOption("prompt", "")
+	video.Model = job.StringOption("model", e.cfg.DefaultModel)
+	video.Template = job.TemplateRef
+	result.Hooks, err = e.hooks.Run(ctx, video)
+	if err != nil {
+		return err
+	}
+
+	notifyWebhooks(ctx, e.notifier, operation, outputPath, job.ID, true)
+	return nil
 }
 
-func (e *RealJobExecutor) executeAnimate(ctx context.Context, job batch.BatchJob, output string) error {
-	return fmt.Errorf("animate execution not yet implemented")
+// generationRequest builds the shared generation parameters from job options
+func (e *RealJobExecutor) generationRequest(job batch.BatchJob) veo3.GenerationRequest {
+	request := veo3.GenerationRequest{
+		Prompt:           job.StringOption("prompt", ""),
+		NegativePrompt:   job.StringOption("negative_prompt", ""),
+		Model:            job.StringOption("model", e.cfg.DefaultModel),
+		AspectRatio:      job.StringOption("aspect_ratio", e.cfg.DefaultAspectRatio),
+		Resolution:       job.StringOption("resolution", e.cfg.DefaultResolution),
+		DurationSeconds:  job.IntOption("duration", e.cfg.DefaultDuration),
+		PersonGeneration: job.StringOption("person_generation", ""),
+	}
+
+	if _, ok := job.Options["seed"]; ok {
+		seed := job.IntOption("seed", 0)
+		request.Seed = &seed
+	}
+
+	return request
 }
 
-func (e *RealJobExecutor) executeInterpolate(ctx context.Context, job batch.BatchJob, output string) error {
-	return fmt.Errorf("interpolate execution not yet implemented")
+// outputPath returns where a job's video is saved
+func (e *RealJobExecutor) outputPath(job batch.BatchJob) string {
+	if e.outputDir != "" {
+		return filepath.Join(e.outputDir, filepath.Base(job.Output))
+	}
+	return job.Output
 }
 
-func (e *RealJobExecutor) executeExtend(ctx context.Context, job batch.BatchJob, output string) error {
-	return fmt.Errorf("extend execution not yet implemented")
+// jobRequest builds the API request a job submits: a
+// *veo3.GenerationRequest, *veo3.ReferenceImageRequest, *veo3.ImageRequest,
+// *veo3.InterpolationRequest or *veo3.ExtensionRequest
+func (e *RealJobExecutor) jobRequest(job batch.BatchJob) (interface{}, error) {
+	switch job.Type {
+	case "generate":
+		request := e.generationRequest(job)
+		if references := job.StringSliceOption("reference_images"); len(references) > 0 {
+			return &veo3.ReferenceImageRequest{
+				GenerationRequest:   request,
+				ReferenceImagePaths: references,
+			}, nil
+		}
+		return &request, nil
+
+	case "animate":
+		return &veo3.ImageRequest{
+			GenerationRequest: e.generationRequest(job),
+			ImagePath:         job.StringOption("image", ""),
+		}, nil
+
+	case "interpolate":
+		frameFit, err := veo3.ParseFrameFitMode(job.StringOption("match_dimensions", ""))
+		if err != nil {
+			return nil, err
+		}
+
+		request := e.generationRequest(job)
+		request.AspectRatio = "16:9" // Fixed for interpolation
+		request.DurationSeconds = 8  // Fixed for interpolation
+
+		return &veo3.InterpolationRequest{
+			GenerationRequest: request,
+			FirstFramePath:    job.StringOption("first_frame", ""),
+			LastFramePath:     job.StringOption("last_frame", ""),
+			FrameFit:          frameFit,
+		}, nil
+
+	case "extend":
+		return &veo3.ExtensionRequest{
+			VideoPath:       job.StringOption("video", ""),
+			ExtensionPrompt: job.StringOption("prompt", ""),
+			Model:           job.StringOption("model", e.cfg.DefaultModel),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown job type: %s", job.Type)
+	}
+}
+
+// submit sends a request built by jobRequest to the API
+func (e *RealJobExecutor) submit(ctx context.Context, request interface{}) (*veo3.Operation, error) {
+	switch request := request.(type) {
+	case *veo3.GenerationRequest:
+		return e.client.GenerateVideo(ctx, request)
+	case *veo3.ReferenceImageRequest:
+		return e.client.GenerateWithReferenceImages(ctx, request)
+	case *veo3.ImageRequest:
+		return e.client.AnimateImage(ctx, request)
+	case *veo3.InterpolationRequest:
+		return e.client.InterpolateFrames(ctx, request)
+	case *veo3.ExtensionRequest:
+		return e.client.ExtendVideo(ctx, request)
+	default:
+		return nil, fmt.Errorf("unsupported request type %T", request)
+	}
 }
 
 // saveResults saves batch results to a JSON file
@@ -345,9 +652,27 @@ func saveResults(filename string, summary batch.BatchSummary) error {
 	return os.WriteFile(filename, data, 0600)
 }
 
-// createVeo3Client creates a Veo3 API client (placeholder)
-func createVeo3Client() (*veo3.Client, error) {
-	// This would use the actual client creation logic
-	// For now, return nil as placeholder
-	return nil, fmt.Errorf("client creation not yet implemented in batch context")
+// saveReport writes a batch report in the given format to a file
+func saveReport(filename, format string, summary batch.BatchSummary) error {
+	var buf bytes.Buffer
+	if err := batch.WriteReport(&buf, format, summary); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, buf.Bytes(), 0600)
+}
+
+// createVeo3Client creates a Veo3 API client from the configuration
+func createVeo3Client(cfg *config.Configuration, extra ...veo3.ClientOption) (*veo3.Client, error) {
+	apiKey, err := resolveAPIKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// The configured endpoint comes first so VEO3_API_ENDPOINT can override it
+	var opts []veo3.ClientOption
+	if cfg.BaseURL != "" {
+		opts = append(opts, veo3.WithBaseURL(cfg.BaseURL))
+	}
+	opts = append(opts, clientOptions()...)
+	return veo3.NewClient(context.Background(), apiKey, append(opts, extra...)...)
 }
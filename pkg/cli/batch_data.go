@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// newBatchFromDataCmd creates the 'batch from-data' command
+func newBatchFromDataCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "from-data <file.csv|file.jsonl>",
+		Short: "Build a batch manifest from CSV or JSONL rows",
+		Long: `Build one batch job per row of a CSV or JSONL file.
+
+Every column becomes a template variable, and columns named after job options
+(prompt, model, duration, aspect_ratio, image, ...) also set that option. A
+"type" column sets the job type. Options and the output may reference columns
+as ${row.column}.
+
+Job IDs are derived from --id-column (default: an "id" column, otherwise the
+row number) and outputs from --output-column (default: an "output" column,
+otherwise the job ID).
+
+The manifest is printed, written with --write, or processed right away with
+--run. A manifest can also read rows itself with a job-level 'data:' block.`,
+		Example: `  # Print a manifest rendering the promo template for each product
+  veo3 batch from-data products.csv --template promo --id-column sku
+
+  # Save it for review, then process it
+  veo3 batch from-data products.csv -t promo --write promo-batch.yaml
+  veo3 batch process promo-batch.yaml
+
+  # Process directly with a shared option and an output pattern
+  veo3 batch from-data shots.jsonl --type generate -O duration=6 \
+    --output "videos/${row.sku}.mp4" --run`,
+		Args: cobra.ExactArgs(1),
+		RunE: runBatchFromData,
+	}
+
+	cmd.Flags().StringP("template", "t", "", "Template (name or name@version) to render for each row")
+	cmd.Flags().String("type", "", "Job type (default: from the template preset or a type column)")
+	cmd.Flags().String("id", "", "Prefix for job IDs (default: the data file name, unless IDs come from a column)")
+	cmd.Flags().String("id-column", "", "Column job IDs are derived from")
+	cmd.Flags().String("output", "", "Output path for each job; the row's ID is added unless it references ${row.column}")
+	cmd.Flags().String("output-column", "", "Column holding each job's output path")
+	cmd.Flags().String("format", "", "Data format: csv or jsonl (default: from the file extension)")
+	cmd.Flags().StringArrayP("option", "O", nil, "Job option for every row as key=value (repeatable)")
+	cmd.Flags().StringP("write", "w", "", "Write the manifest to a file instead of printing it")
+	cmd.Flags().Bool("run", false, "Process the jobs instead of printing a manifest")
+	cmd.Flags().Int("concurrency", 0, "Number of concurrent jobs with --run")
+	cmd.Flags().Bool("stop-on-error", false, "Stop processing on first error with --run")
+	cmd.Flags().String("output-dir", "", "Output directory for all videos with --run")
+	cmd.Flags().StringSlice("report", nil, "Also write a report with --run: html, md or junit (repeatable)")
+	addBudgetFlags(cmd, "with --run")
+
+	return cmd
+}
+
+// runBatchFromData expands a data file into batch jobs and prints, writes or
+// processes the resulting manifest
+func runBatchFromData(cmd *cobra.Command, args []string) error {
+	dataPath := args[0]
+	flags := cmd.Flags()
+
+	job := batch.BatchJob{Options: make(map[string]interface{}), Data: &batch.DataSource{File: dataPath}}
+	job.ID, _ = flags.GetString("id")
+	job.Type, _ = flags.GetString("type")
+	job.Template, _ = flags.GetString("template")
+	job.Output, _ = flags.GetString("output")
+	job.Data.Format, _ = flags.GetString("format")
+	job.Data.IDColumn, _ = flags.GetString("id-column")
+	job.Data.OutputColumn, _ = flags.GetString("output-column")
+	if job.ID == "" && job.Data.IDColumn == "" {
+		job.ID = strings.TrimSuffix(filepath.Base(dataPath), filepath.Ext(dataPath))
+	}
+
+	options, _ := flags.GetStringArray("option")
+	for _, option := range options {
+		key, value, ok := strings.Cut(option, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid option %q (expected key=value)", option)
+		}
+		job.Options[key] = value
+	}
+	if references, ok := job.Options["reference_images"].(string); ok {
+		job.Options["reference_images"] = splitList(references)
+	}
+
+	manifest := &batch.BatchManifest{Jobs: []batch.BatchJob{job}}
+	if err := batch.ExpandData(manifest, ""); err != nil {
+		return err
+	}
+	batch.ApplyDefaults(manifest)
+	if err := batch.ValidateManifest(manifest); err != nil {
+		return fmt.Errorf("invalid jobs: %w", err)
+	}
+
+	if run, _ := flags.GetBool("run"); run {
+		concurrency, _ := flags.GetInt("concurrency")
+		stopOnError, _ := flags.GetBool("stop-on-error")
+		outputDir, _ := flags.GetString("output-dir")
+		reports, _ := flags.GetStringSlice("report")
+		return processBatch(cmd, manifest, dataPath, concurrency, stopOnError, outputDir, reports)
+	}
+
+	// Render every job's template now so a bad row fails here rather than
+	// when the manifest is processed
+	check := &batch.BatchManifest{Jobs: append([]batch.BatchJob(nil), manifest.Jobs...)}
+	if err := batch.ApplyTemplates(check, lookupTemplate); err != nil {
+		return fmt.Errorf("failed to apply templates: %w", err)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated by 'veo3 batch from-data %s'\n", dataPath)
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(manifest); err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	_ = encoder.Close()
+	data := buf.Bytes()
+
+	path, _ := flags.GetString("write")
+	if path == "" {
+		fmt.Print(string(data))
+		return nil
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	fmt.Printf("✓ Wrote %d job(s) to %s\n", len(manifest.Jobs), path)
+	return nil
+}
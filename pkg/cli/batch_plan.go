@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jasongoecke/go-veo3/internal/validation"
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+)
+
+// batchPlan is what a dry run of a batch would submit
+type batchPlan struct {
+	Source       string        `json:"source"`
+	Jobs         []plannedJob  `json:"jobs"`
+	VideoSeconds int           `json:"estimated_video_seconds"`
+	Cost         float64       `json:"estimated_cost,omitempty"`
+	Unpriced     []string      `json:"unpriced_models,omitempty"`
+	Budget       *batch.Budget `json:"budget,omitempty"`
+	Problems     []string      `json:"problems,omitempty"` // Problems that are not specific to one job
+}
+
+// plannedJob is one job of a dry run
+type plannedJob struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Template  string      `json:"template,omitempty"`
+	DependsOn []string    `json:"depends_on,omitempty"`
+	Output    string      `json:"output"`
+	Seconds   int         `json:"estimated_seconds"`
+	Cost      float64     `json:"estimated_cost,omitempty"`
+	Request   interface{} `json:"request,omitempty"`
+	Problems  []string    `json:"problems,omitempty"`
+}
+
+// problemCount returns the number of problems in the plan
+func (p batchPlan) problemCount() int {
+	count := len(p.Problems)
+	for _, job := range p.Jobs {
+		count += len(job.Problems)
+	}
+	return count
+}
+
+// jobFileOptions are the options of each job type that name input files,
+// with the check each file must pass
+var jobFileOptions = map[string][]struct {
+	key      string
+	validate func(path string) error
+}{
+	"generate":    {{"reference_images", validation.ValidateImageFile}},
+	"animate":     {{"image", validation.ValidateImageFile}},
+	"interpolate": {{"first_frame", validation.ValidateImageFile}, {"last_frame", validation.ValidateImageFile}},
+	"extend":      {{"video", validation.ValidateVideoFileForExtension}},
+}
+
+// planBatch resolves every job of a manifest into the request it would
+// submit and checks the jobs without calling the API
+func planBatch(manifest *batch.BatchManifest, source string, executor *RealJobExecutor, defaults batch.JobDefaults) batchPlan {
+	plan := batchPlan{Source: source}
+
+	// Jobs that reference another job's output read the file that job will
+	// save
+	outputs := make(map[string]string, len(manifest.Jobs))
+	writers := make(map[string][]string)
+	for _, job := range manifest.Jobs {
+		output := executor.outputPath(job)
+		outputs[job.ID] = output
+		writers[filepath.Clean(output)] = append(writers[filepath.Clean(output)], job.ID)
+	}
+
+	for _, job := range manifest.Jobs {
+		planned := plannedJob{
+			ID:       job.ID,
+			Type:     job.Type,
+			Template: job.TemplateRef,
+			Output:   outputs[job.ID],
+		}
+		planned.DependsOn, _ = job.Dependencies()
+		estimate := batch.EstimateJob(job, defaults)
+		planned.Seconds, planned.Cost = estimate.Seconds, estimate.Cost
+
+		resolved, err := batch.ResolveJobOutputs(job, outputs)
+		if err != nil {
+			planned.Problems = append(planned.Problems, err.Error())
+		} else {
+			planned.Request, planned.Problems = planRequest(executor, job, resolved)
+		}
+		plan.Jobs = append(plan.Jobs, planned)
+	}
+
+	paths := make([]string, 0, len(writers))
+	for path := range writers {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if ids := writers[path]; len(ids) > 1 {
+			plan.Problems = append(plan.Problems, fmt.Sprintf("output %s is written by jobs %s", path, strings.Join(ids, ", ")))
+		}
+	}
+
+	total := batch.EstimateJobs(manifest.Jobs, defaults)
+	plan.VideoSeconds, plan.Cost, plan.Unpriced = total.VideoSeconds, total.Cost, total.Unpriced
+	if !manifest.Budget.IsZero() {
+		plan.Budget = &manifest.Budget
+		if _, err := manifest.Budget.Check(manifest.Jobs, defaults); err != nil {
+			plan.Problems = append(plan.Problems, "budget exceeded: "+err.Error())
+		}
+	}
+	return plan
+}
+
+// planRequest builds a job's request and validates it and its input files.
+// Files another job will write are not checked, since they do not exist yet.
+func planRequest(executor *RealJobExecutor, job, resolved batch.BatchJob) (interface{}, []string) {
+	var problems []string
+	pending := false
+	for _, option := range jobFileOptions[job.Type] {
+		if job.UsesJobOutput(option.key) {
+			pending = true
+			continue
+		}
+		for _, path := range resolved.StringSliceOption(option.key) {
+			if err := option.validate(path); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", option.key, err))
+			}
+		}
+	}
+
+	request, err := executor.jobRequest(resolved)
+	if err != nil {
+		return nil, append(problems, err.Error())
+	}
+	if len(problems) > 0 {
+		return request, problems
+	}
+
+	if !pending {
+		if validator, ok := request.(interface{ Validate() error }); ok {
+			if err := validator.Validate(); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+		return request, problems
+	}
+
+	// The request cannot be validated as a whole until its input exists;
+	// check the parameters that do not depend on it
+	model := resolved.StringOption("model", executor.cfg.DefaultModel)
+	if err := validation.ValidateModel(model); err != nil {
+		problems = append(problems, err.Error())
+	} else if job.Type == "extend" {
+		if err := veo3.ValidateModelForExtension(model); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if prompt := resolved.StringOption("prompt", ""); prompt != "" {
+		if err := validation.ValidatePrompt(prompt); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	return request, problems
+}
+
+// printPlan prints a dry-run plan for people to review
+func printPlan(plan batchPlan) {
+	fmt.Println("Dry run: nothing will be submitted")
+
+	for i, job := range plan.Jobs {
+		fmt.Printf("\n[%d/%d] %s (%s)\n", i+1, len(plan.Jobs), job.ID, job.Type)
+		fmt.Printf("  Output:     %s\n", job.Output)
+		if job.Template != "" {
+			fmt.Printf("  Template:   %s\n", job.Template)
+		}
+		if len(job.DependsOn) > 0 {
+			fmt.Printf("  Depends on: %s\n", strings.Join(job.DependsOn, ", "))
+		}
+		estimate := fmt.Sprintf("%ds", job.Seconds)
+		if job.Cost > 0 {
+			estimate += fmt.Sprintf(", ~$%.2f", job.Cost)
+		}
+		fmt.Printf("  Estimate:   %s\n", estimate)
+		if job.Request != nil {
+			data, _ := json.MarshalIndent(job.Request, "    ", "  ")
+			fmt.Printf("  Request:\n    %s\n", data)
+		}
+		for _, problem := range job.Problems {
+			fmt.Printf("  ❌ %s\n", problem)
+		}
+	}
+
+	estimate := batch.BudgetEstimate{Jobs: len(plan.Jobs), VideoSeconds: plan.VideoSeconds, Cost: plan.Cost, Unpriced: plan.Unpriced}
+	if plan.Budget != nil {
+		fmt.Printf("\nPlan: %s\n", formatBudget(estimate, *plan.Budget))
+	} else {
+		fmt.Printf("\nPlan: %s\n", formatEstimate(estimate))
+	}
+	for _, problem := range plan.Problems {
+		fmt.Printf("❌ %s\n", problem)
+	}
+
+	if count := plan.problemCount(); count > 0 {
+		fmt.Printf("\n❌ Found %d problem(s)\n", count)
+	} else {
+		fmt.Println("\n✅ No problems found")
+	}
+}
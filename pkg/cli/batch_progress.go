@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"os"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// newBatchProgress returns the progress view for a batch run and how often it
+// refreshes: a live view on a terminal, status lines every 30 seconds
+// otherwise and JSON events under --json. It returns nil under --quiet.
+func newBatchProgress(jobs []batch.BatchJob) (*batch.Progress, time.Duration) {
+	switch {
+	case viper.GetBool("quiet"):
+		return nil, 0
+	case viper.GetBool("json"):
+		return batch.NewProgress(os.Stdout, batch.ProgressJSON, jobs), 0
+	}
+
+	fd := int(os.Stdout.Fd()) // #nosec G115 -- file descriptors fit in an int
+	if !term.IsTerminal(fd) || os.Getenv("TERM") == "dumb" {
+		return batch.NewProgress(os.Stdout, batch.ProgressLines, jobs), 30 * time.Second
+	}
+
+	progress := batch.NewProgress(os.Stdout, batch.ProgressTTY, jobs)
+	if width, _, err := term.GetSize(fd); err == nil {
+		progress.Width = width
+	}
+	return progress, 500 * time.Millisecond
+}
@@ -1,7 +1,10 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
 	"strconv"
 	"strings"
 
@@ -42,6 +45,9 @@ The config file location follows XDG Base Directory specification:
 	configCmd.AddCommand(newConfigGetCmd())
 	configCmd.AddCommand(newConfigShowCmd())
 	configCmd.AddCommand(newConfigResetCmd())
+	configCmd.AddCommand(newConfigValidateCmd())
+	configCmd.AddCommand(newConfigMigrateCmd())
+	configCmd.AddCommand(newConfigProfilesCmd())
 
 	return configCmd
 }
@@ -81,15 +87,24 @@ func newConfigSetCmd() *cobra.Command {
 
 Available configuration keys:
 - api-key: Google Gemini API key
+- api-key-command: Command that prints the API key (e.g. "pass show veo3")
+- api-key-env: Environment variable holding the API key
+- base-url: API endpoint (e.g. a staging fake)
 - default-model: Default model for generations
 - default-resolution: Default resolution (720p or 1080p)
 - default-duration: Default duration (4, 6, or 8)
 - default-aspect-ratio: Default aspect ratio (16:9 or 9:16)
 - output-directory: Default output directory for videos
-- poll-interval: Status polling interval in seconds`,
+- poll-interval: Status polling interval in seconds
+- queue-concurrency: Jobs run at once by "veo3 queue run"
+- webhooks: Comma-separated URLs notified when operations complete
+- webhook-secret: Shared secret used to sign webhook payloads`,
 		Example: `  # Set API key
   veo3 config set api-key YOUR_API_KEY
 
+  # Fetch the API key from a password manager instead of storing it
+  veo3 config set api-key-command "pass show veo3/api-key"
+
   # Set default resolution
   veo3 config set default-resolution 1080p
 
@@ -135,6 +150,11 @@ func newConfigShowCmd() *cobra.Command {
 		Short: "Show current configuration",
 		Long: `Show the current configuration with all keys and values.
 
+Settings are layered: built-in defaults, the user config, the nearest
+project .veo3.yaml (searched from the working directory upwards), the
+selected profile, then VEO3_* environment variables. Use --origin to see
+which layer each value comes from.
+
 Sensitive values like API keys are masked by default. Use --show-sensitive
 to display full values (use with caution in shared environments).`,
 		Example: `  # Show configuration (sensitive data masked)
@@ -143,6 +163,9 @@ to display full values (use with caution in shared environments).`,
   # Show configuration in JSON format
   veo3 config show --json
 
+  # Show where each value comes from
+  veo3 config show --origin
+
   # Show with sensitive data (use with caution)
   veo3 config show --show-sensitive`,
 		RunE: runConfigShow,
@@ -150,6 +173,7 @@ to display full values (use with caution in shared environments).`,
 
 	cmd.Flags().Bool("show-sensitive", false, "Show full sensitive values (use with caution)")
 	cmd.Flags().Bool("pretty", false, "Pretty-print JSON output (with --json)")
+	cmd.Flags().Bool("origin", false, "Show which layer (default, user, project, profile, env) each value comes from")
 
 	return cmd
 }
@@ -193,7 +217,7 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 	// Check if config already exists
 	manager := config.NewManager(configPath)
 	if !force {
-		if _, err := manager.Load(); err == nil {
+		if _, err := os.Stat(manager.ConfigFile()); err == nil {
 			return fmt.Errorf("configuration already exists (use --force to overwrite)")
 		}
 	}
@@ -279,8 +303,11 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 
 	// Load existing configuration
 	manager := config.NewManager(configPath)
-	cfg, err := manager.Load()
+	cfg, err := manager.LoadBase()
 	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
 		// Create new config if none exists
 		cfg = &config.Configuration{
 			DefaultModel:        config.DefaultModel,
@@ -293,10 +320,19 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Problems already in the file do not block fixing them one at a time
+	existing := validationProblems(cfg)
+
 	// Set the value
 	switch strings.ToLower(key) {
 	case "api-key", "api_key":
 		cfg.APIKey = value
+	case "api-key-command", "api_key_command":
+		cfg.APIKeyCommand = value
+	case "api-key-env", "api_key_env":
+		cfg.APIKeyEnv = value
+	case "base-url", "base_url":
+		cfg.BaseURL = value
 	case "default-model", "default_model":
 		cfg.DefaultModel = value
 	case "default-resolution", "default_resolution":
@@ -317,10 +353,28 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		} else {
 			return fmt.Errorf("invalid poll interval value: %s (must be a number)", value)
 		}
+	case "queue-concurrency", "queue_concurrency":
+		if concurrency, err := strconv.Atoi(value); err == nil {
+			cfg.QueueConcurrency = concurrency
+		} else {
+			return fmt.Errorf("invalid queue concurrency value: %s (must be a number)", value)
+		}
+	case "webhooks":
+		cfg.Webhooks = splitList(value)
+	case "webhook-secret", "webhook_secret":
+		cfg.WebhookSecret = value
 	default:
 		return fmt.Errorf("unknown configuration key: %s", key)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		for _, problem := range splitJoinedErrors(err) {
+			if !existing[problem] {
+				return fmt.Errorf("invalid value for %s: %s", key, problem)
+			}
+		}
+	}
+
 	// Save configuration
 	err = manager.Save(cfg)
 	if err != nil {
@@ -357,9 +411,10 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 
 	// Load configuration
 	manager := config.NewManager(configPath)
+	manager.SetProfile(viper.GetString("profile"))
 	cfg, err := manager.Load()
 	if err != nil {
-		return fmt.Errorf("no configuration found (run 'veo3 config init' first)")
+		return configLoadError(err)
 	}
 
 	if len(args) == 0 {
@@ -376,6 +431,14 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 		if !showSensitive && value != "" {
 			value = maskSensitiveValue(value)
 		}
+	case "api-key-command", "api_key_command":
+		value = cfg.APIKeyCommand
+	case "api-key-env", "api_key_env":
+		value = cfg.APIKeyEnv
+	case "base-url", "base_url":
+		value = cfg.BaseURL
+	case "profile":
+		value = cfg.Profile
 	case "default-model", "default_model":
 		value = cfg.DefaultModel
 	case "default-resolution", "default_resolution":
@@ -388,6 +451,15 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 		value = cfg.OutputDirectory
 	case "poll-interval", "poll_interval":
 		value = fmt.Sprintf("%d", cfg.PollIntervalSeconds)
+	case "queue-concurrency", "queue_concurrency":
+		value = fmt.Sprintf("%d", cfg.QueueConcurrency)
+	case "webhooks":
+		value = strings.Join(cfg.Webhooks, ",")
+	case "webhook-secret", "webhook_secret":
+		value = cfg.WebhookSecret
+		if !showSensitive && value != "" {
+			value = maskSensitiveValue(value)
+		}
 	default:
 		return fmt.Errorf("unknown configuration key: %s", key)
 	}
@@ -411,6 +483,7 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 
 func runConfigShow(cmd *cobra.Command, args []string) error {
 	showSensitive, _ := cmd.Flags().GetBool("show-sensitive")
+	showOrigin, _ := cmd.Flags().GetBool("origin")
 	jsonFormat := viper.GetBool("json")
 	out := cmd.OutOrStdout()
 	// pretty, _ := cmd.Flags().GetBool("pretty") // TODO: Use for formatted output
@@ -423,10 +496,12 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 
 	// Load configuration
 	manager := config.NewManager(configPath)
+	manager.SetProfile(viper.GetString("profile"))
 	cfg, err := manager.Load()
 	if err != nil {
-		return fmt.Errorf("no configuration found (run 'veo3 config init' first)")
+		return configLoadError(err)
 	}
+	origins := manager.Origins()
 
 	if jsonFormat {
 		// Create config copy for output (mask sensitive if needed)
@@ -435,28 +510,80 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 			outputCfg.APIKey = maskSensitiveValue(outputCfg.APIKey)
 		}
 
+		if showOrigin {
+			jsonOutput, _ := format.FormatGenericJSON(map[string]interface{}{
+				"success": true,
+				"data": map[string]interface{}{
+					"config":         outputCfg,
+					"origins":        origins,
+					"project_config": manager.ProjectConfigFile(),
+				},
+			})
+			_, _ = fmt.Fprintln(out, jsonOutput)
+			return nil
+		}
+
 		jsonOutput, err := format.FormatConfigJSON(outputCfg)
 		if err != nil {
 			return err
 		}
 		_, _ = fmt.Fprintln(out, jsonOutput)
 	} else {
+		// line prints a setting, followed by its origin with --origin
+		line := func(label, key, value string) {
+			if showOrigin {
+				_, _ = fmt.Fprintf(out, "%s: %s  [%s]\n", label, value, origins[key])
+				return
+			}
+			_, _ = fmt.Fprintf(out, "%s: %s\n", label, value)
+		}
+
 		// Human-readable format
-		_, _ = fmt.Fprintf(out, "Configuration File: %s\n\n", manager.ConfigFile())
+		_, _ = fmt.Fprintf(out, "Configuration File: %s\n", manager.ConfigFile())
+		if project := manager.ProjectConfigFile(); project != "" {
+			_, _ = fmt.Fprintf(out, "Project Config: %s\n", project)
+		}
+		if cfg.Profile != "" {
+			_, _ = fmt.Fprintf(out, "Profile: %s\n", cfg.Profile)
+		}
+		_, _ = fmt.Fprintln(out)
 
 		apiKey := cfg.APIKey
 		if !showSensitive && apiKey != "" {
 			apiKey = maskSensitiveValue(apiKey)
 		}
 
-		_, _ = fmt.Fprintf(out, "API Key: %s\n", apiKey)
-		_, _ = fmt.Fprintf(out, "Default Model: %s\n", cfg.DefaultModel)
-		_, _ = fmt.Fprintf(out, "Default Resolution: %s\n", cfg.DefaultResolution)
-		_, _ = fmt.Fprintf(out, "Default Duration: %ds\n", cfg.DefaultDuration)
-		_, _ = fmt.Fprintf(out, "Default Aspect Ratio: %s\n", cfg.DefaultAspectRatio)
-		_, _ = fmt.Fprintf(out, "Output Directory: %s\n", cfg.OutputDirectory)
-		_, _ = fmt.Fprintf(out, "Poll Interval: %ds\n", cfg.PollIntervalSeconds)
-		_, _ = fmt.Fprintf(out, "Config Version: %s\n", cfg.ConfigVersion)
+		line("API Key", "api_key", apiKey)
+		if cfg.APIKeyCommand != "" {
+			line("API Key Command", "api_key_command", cfg.APIKeyCommand)
+		}
+		if cfg.APIKeyEnv != "" {
+			line("API Key Env", "api_key_env", cfg.APIKeyEnv)
+		}
+		if cfg.BaseURL != "" {
+			line("Base URL", "base_url", cfg.BaseURL)
+		}
+		line("Default Model", "default_model", cfg.DefaultModel)
+		line("Default Resolution", "default_resolution", cfg.DefaultResolution)
+		line("Default Duration", "default_duration", fmt.Sprintf("%ds", cfg.DefaultDuration))
+		line("Default Aspect Ratio", "default_aspect_ratio", cfg.DefaultAspectRatio)
+		line("Output Directory", "output_directory", cfg.OutputDirectory)
+		line("Poll Interval", "poll_interval_seconds", fmt.Sprintf("%ds", cfg.PollIntervalSeconds))
+		line("Queue Concurrency", "queue_concurrency", strconv.Itoa(cfg.QueueConcurrency))
+		if len(cfg.Webhooks) > 0 {
+			line("Webhooks", "webhooks", strings.Join(cfg.Webhooks, ", "))
+		}
+		if cfg.WebhookSecret != "" {
+			secret := cfg.WebhookSecret
+			if !showSensitive {
+				secret = maskSensitiveValue(secret)
+			}
+			line("Webhook Secret", "webhook_secret", secret)
+		}
+		if len(cfg.Profiles) > 0 {
+			line("Profiles", "profiles", strings.Join(cfg.ProfileNames(), ", "))
+		}
+		line("Config Version", "version", cfg.ConfigVersion)
 	}
 
 	return nil
@@ -522,9 +649,29 @@ func runConfigReset(cmd *cobra.Command, args []string) error {
 
 // Helper functions
 
+// configLoadError explains a configuration load failure, pointing at
+// "config init" when the file does not exist
+func configLoadError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("no configuration found (run 'veo3 config init' first)")
+	}
+	return err
+}
+
 func maskSensitiveValue(value string) string {
 	if len(value) <= 4 {
 		return "****"
 	}
 	return "****" + value[len(value)-4:]
 }
+
+// splitList splits a comma-separated value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jasongoecke/go-veo3/internal/format"
+	"github.com/jasongoecke/go-veo3/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// newConfigValidateCmd creates the 'config validate' command
+func newConfigValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Check the configuration file for errors",
+		Long: `Check every setting in the configuration file, including all profiles.
+
+Reports unsupported models, resolutions, aspect ratios and durations, an output
+directory that is not writable, polling intervals outside 1-` + fmt.Sprint(config.MaxPollInterval) + ` seconds and
+malformed URLs. Keys the schema does not know and files written with an older
+schema version are reported as warnings. Exits non-zero when the file is invalid.`,
+		Example: `  # Check the default config file
+  veo3 config validate
+
+  # Check another file
+  veo3 config validate --config ./ci/veo3.yaml`,
+		Args: cobra.NoArgs,
+		RunE: runConfigValidate,
+	}
+}
+
+// newConfigMigrateCmd creates the 'config migrate' command
+func newConfigMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade the configuration file to the current schema",
+		Long: `Upgrade the configuration file to schema version ` + config.DefaultConfigVersion + `.
+
+Files without a version field are treated as version ` + config.LegacyConfigVersion + `. The original file is
+copied to <config>.v<version>.bak before it is rewritten. Comments and key
+order are kept.`,
+		Example: `  # Show what would change
+  veo3 config migrate --dry-run
+
+  # Upgrade the config file
+  veo3 config migrate`,
+		Args: cobra.NoArgs,
+		RunE: runConfigMigrate,
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Show the changes without writing the file")
+
+	return cmd
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	jsonFormat := viper.GetBool("json")
+	out := cmd.OutOrStdout()
+
+	manager := profilesManager(cmd)
+	cfg, err := manager.LoadBase()
+	if err != nil {
+		return configLoadError(err)
+	}
+
+	var problems []string
+	if err := cfg.Validate(); err != nil {
+		problems = splitJoinedErrors(err)
+	}
+	if err := cfg.CheckOutputDirectories(); err != nil {
+		problems = append(problems, splitJoinedErrors(err)...)
+	}
+
+	var warnings []string
+	if config.IsOutdatedConfigVersion(cfg.ConfigVersion) {
+		warnings = append(warnings, fmt.Sprintf("schema version %s is older than %s (run 'veo3 config migrate')",
+			valueOr(cfg.ConfigVersion, config.LegacyConfigVersion), config.DefaultConfigVersion))
+	}
+	unknown, err := manager.UnknownKeys()
+	if err != nil {
+		return err
+	}
+	for _, key := range unknown {
+		warnings = append(warnings, fmt.Sprintf("unknown key '%s' is ignored", key))
+	}
+
+	if jsonFormat {
+		jsonOutput, _ := format.FormatGenericJSON(map[string]interface{}{
+			"success": len(problems) == 0,
+			"data": map[string]interface{}{
+				"config_file": manager.ConfigFile(),
+				"version":     cfg.ConfigVersion,
+				"valid":       len(problems) == 0,
+				"errors":      problems,
+				"warnings":    warnings,
+			},
+		})
+		_, _ = fmt.Fprintln(out, jsonOutput)
+	} else {
+		for _, problem := range problems {
+			_, _ = fmt.Fprintf(out, "✗ %s\n", problem)
+		}
+		for _, warning := range warnings {
+			_, _ = fmt.Fprintf(out, "! %s\n", warning)
+		}
+		if len(problems) == 0 {
+			_, _ = fmt.Fprintf(out, "✓ Configuration is valid: %s\n", manager.ConfigFile())
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("configuration has %d problem(s): %s", len(problems), manager.ConfigFile())
+	}
+	return nil
+}
+
+func runConfigMigrate(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	jsonFormat := viper.GetBool("json")
+	out := cmd.OutOrStdout()
+
+	manager := profilesManager(cmd)
+	result, err := manager.Migrate(dryRun)
+	if err != nil {
+		return configLoadError(err)
+	}
+
+	if jsonFormat {
+		jsonOutput, _ := format.FormatGenericJSON(map[string]interface{}{
+			"success": true,
+			"data": map[string]interface{}{
+				"migrated": result.Migrated() && !dryRun,
+				"dry_run":  dryRun,
+				"result":   result,
+			},
+		})
+		_, _ = fmt.Fprintln(out, jsonOutput)
+		return nil
+	}
+
+	if !result.Migrated() {
+		_, _ = fmt.Fprintf(out, "✓ Configuration is already at schema version %s\n", result.ToVersion)
+		return nil
+	}
+
+	for _, change := range result.Changes {
+		_, _ = fmt.Fprintf(out, "  - %s\n", change)
+	}
+	if dryRun {
+		_, _ = fmt.Fprintf(out, "Dry run: %s would be migrated from %s to %s\n", result.ConfigFile, result.FromVersion, result.ToVersion)
+		return nil
+	}
+	_, _ = fmt.Fprintf(out, "✓ Migrated %s from %s to %s\n", result.ConfigFile, result.FromVersion, result.ToVersion)
+	_, _ = fmt.Fprintf(out, "Backup: %s\n", result.BackupFile)
+	return nil
+}
+
+// validationProblems returns the set of problems reported by cfg.Validate
+func validationProblems(cfg *config.Configuration) map[string]bool {
+	problems := make(map[string]bool)
+	if err := cfg.Validate(); err != nil {
+		for _, problem := range splitJoinedErrors(err) {
+			problems[problem] = true
+		}
+	}
+	return problems
+}
+
+// splitJoinedErrors returns one message per error combined with errors.Join
+func splitJoinedErrors(err error) []string {
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		var messages []string
+		for _, e := range joined.Unwrap() {
+			messages = append(messages, e.Error())
+		}
+		return messages
+	}
+	return strings.Split(err.Error(), "\n")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/jasongoecke/go-veo3/pkg/config"
+	"github.com/jasongoecke/go-veo3/pkg/hooks"
 	"github.com/jasongoecke/go-veo3/pkg/veo3"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -66,8 +67,11 @@ func runExtend(cmd *cobra.Command, args []string) error {
 	videoPath := args[0]
 
 	// Load configuration using manager
-	manager := config.NewManager("")
+	manager := newConfigManager()
 	cfg, err := manager.Load()
+	if fatalConfigError(err) {
+		return err
+	}
 	if err != nil {
 		// Use defaults if config load fails
 		cfg = &config.Configuration{
@@ -100,12 +104,7 @@ func runExtend(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiKey := viper.GetString("api-key")
-	if apiKey == "" {
-		apiKey = cfg.APIKey
-	}
-
-	client, err := veo3.NewClient(context.Background(), apiKey)
+	client, err := createVeo3Client(cfg)
 	if err != nil {
 		return handleError(err, jsonFormat, pretty)
 	}
@@ -145,16 +144,24 @@ func runExtend(cmd *cobra.Command, args []string) error {
 	}
 
 	// Download video if completed and not disabled
+	var outputPath string
 	if operation.Status == veo3.StatusDone && !noDownload {
 		if !jsonFormat {
 			fmt.Println("✓ Video extension completed!")
 		}
 
-		err = downloadVideo(ctx, operation, outputDir, filename, jsonFormat)
+		video, err := downloadVideo(ctx, client, operation, outputDir, filename, jsonFormat)
 		if err != nil {
 			return handleError(err, jsonFormat, pretty)
 		}
+		outputPath = video.FilePath
+		video.Prompt, video.Model = request.ExtensionPrompt, request.Model
+		if _, err := runPostDownloadHooks(ctx, hooks.NewRunner(cfg.PostDownloadHooks), video, jsonFormat); err != nil {
+			return handleError(err, jsonFormat, pretty)
+		}
 	}
 
+	notifyWebhooks(ctx, newWebhookNotifier(cmd, cfg), operation, outputPath, "", jsonFormat)
+
 	return outputOperation(operation, jsonFormat, pretty)
 }
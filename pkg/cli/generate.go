@@ -9,9 +9,13 @@ import (
 	"time"
 
 	"github.com/jasongoecke/go-veo3/internal/format"
+	"github.com/jasongoecke/go-veo3/internal/logger"
 	"github.com/jasongoecke/go-veo3/pkg/config"
+	"github.com/jasongoecke/go-veo3/pkg/hooks"
 	"github.com/jasongoecke/go-veo3/pkg/operations"
+	"github.com/jasongoecke/go-veo3/pkg/templates"
 	"github.com/jasongoecke/go-veo3/pkg/veo3"
+	"github.com/jasongoecke/go-veo3/pkg/webhooks"
 	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -83,8 +87,7 @@ Generation typically takes 2-5 minutes depending on parameters.`,
 
 	// Flags for the main generate command (backwards compatibility)
 	generateCmd.Flags().StringP("prompt", "p", "", "Text prompt (required unless --template is used)")
-	generateCmd.Flags().StringP("template", "t", "", "Use a saved template by name")
-	generateCmd.Flags().StringToString("vars", map[string]string{}, "Template variables (key=value format)")
+	addTemplateFlags(generateCmd)
 	generateCmd.Flags().StringP("resolution", "r", "", "Resolution (720p or 1080p)")
 	generateCmd.Flags().IntP("duration", "d", 0, "Duration in seconds (4, 6, or 8)")
 	generateCmd.Flags().StringP("aspect-ratio", "a", "", "Aspect ratio (16:9 or 9:16)")
@@ -99,8 +102,7 @@ Generation typically takes 2-5 minutes depending on parameters.`,
 
 	// Flags for the text subcommand
 	generateTextCmd.Flags().StringP("prompt", "p", "", "Text prompt (required unless --template is used)")
-	generateTextCmd.Flags().StringP("template", "t", "", "Use a saved template by name")
-	generateTextCmd.Flags().StringToString("vars", map[string]string{}, "Template variables (key=value format)")
+	addTemplateFlags(generateTextCmd)
 	generateTextCmd.Flags().StringP("resolution", "r", "", "Resolution (720p or 1080p)")
 	generateTextCmd.Flags().IntP("duration", "d", 0, "Duration in seconds (4, 6, or 8)")
 	generateTextCmd.Flags().StringP("aspect-ratio", "a", "", "Aspect ratio (16:9 or 9:16)")
@@ -137,8 +139,11 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 // runGenerateText handles text-to-video generation
 func runGenerateText(cmd *cobra.Command, args []string) error {
 	// Load configuration using manager
-	manager := config.NewManager("")
+	manager := newConfigManager()
 	cfg, err := manager.Load()
+	if fatalConfigError(err) {
+		return err
+	}
 	if err != nil {
 		// Use defaults if config load fails
 		cfg = &config.Configuration{
@@ -151,56 +156,28 @@ func runGenerateText(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Get template and variables
-	templateName, _ := cmd.Flags().GetString("template")
-	templateVars, _ := cmd.Flags().GetStringToString("vars")
-
 	// Get prompt - either directly or from template
-	prompt, _ := cmd.Flags().GetString("prompt")
-
-	// If template is specified, load and render it
-	if templateName != "" {
-		manager, err := getTemplateManager()
-		if err != nil {
-			return handleError(fmt.Errorf("failed to get template manager: %w", err), viper.GetBool("json"), false)
-		}
-
-		template, err := manager.Get(templateName)
-		if err != nil {
-			return handleError(fmt.Errorf("failed to load template: %w", err), viper.GetBool("json"), false)
-		}
-
-		renderedPrompt, err := template.Render(templateVars)
-		if err != nil {
-			return handleError(fmt.Errorf("failed to render template: %w", err), viper.GetBool("json"), false)
-		}
-
-		prompt = renderedPrompt
-
-		if !viper.GetBool("json") {
-			fmt.Printf("📝 Using template '%s'\n", templateName)
-			if len(templateVars) > 0 {
-				fmt.Println("   Variables:")
-				for k, v := range templateVars {
-					fmt.Printf("     %s: %s\n", k, v)
-				}
-			}
-			fmt.Printf("   Rendered: %s\n\n", prompt)
-		}
+	prompt, template, err := resolveTemplate(cmd, templates.TypeGenerate)
+	if err != nil {
+		return handleError(err, viper.GetBool("json"), false)
 	}
+	preset := templatePreset(template)
 
 	// Validate that we have a prompt (either direct or from template)
 	if prompt == "" {
 		return handleError(fmt.Errorf("either --prompt or --template must be specified"), viper.GetBool("json"), false)
 	}
 
-	// Get flag values with config fallbacks
-	resolution := getStringWithDefault(cmd, "resolution", cfg.DefaultResolution)
-	duration := getIntWithDefault(cmd, "duration", cfg.DefaultDuration)
-	aspectRatio := getStringWithDefault(cmd, "aspect-ratio", cfg.DefaultAspectRatio)
-	model := getStringWithDefault(cmd, "model", cfg.DefaultModel)
-	negativePrompt, _ := cmd.Flags().GetString("negative-prompt")
+	// Get flag values with template preset and config fallbacks
+	resolution := getStringWithDefault(cmd, "resolution", valueOr(preset.Resolution, cfg.DefaultResolution))
+	duration := getIntWithDefault(cmd, "duration", intOr(preset.Duration, cfg.DefaultDuration))
+	aspectRatio := getStringWithDefault(cmd, "aspect-ratio", valueOr(preset.AspectRatio, cfg.DefaultAspectRatio))
+	model := getStringWithDefault(cmd, "model", valueOr(preset.Model, cfg.DefaultModel))
+	negativePrompt := getStringWithDefault(cmd, "negative-prompt", preset.NegativePrompt)
 	referenceImages, _ := cmd.Flags().GetStringSlice("reference")
+	if !cmd.Flags().Changed("reference") {
+		referenceImages = preset.ReferenceImages
+	}
 	outputDir := getStringWithDefault(cmd, "output", cfg.OutputDirectory)
 	filename, _ := cmd.Flags().GetString("filename")
 	noWait, _ := cmd.Flags().GetBool("no-wait")
@@ -211,33 +188,18 @@ func runGenerateText(cmd *cobra.Command, args []string) error {
 	// Create context
 	ctx := context.Background()
 
-	// Create API client - check multiple sources in priority order
-	apiKey := viper.GetString("api-key")
-	if apiKey == "" {
-		// Check environment variables directly (viper's flag binding can override env vars)
-		if envKey := os.Getenv("VEO3_API_KEY"); envKey != "" {
-			apiKey = envKey
-		} else if envKey := os.Getenv("GEMINI_API_KEY"); envKey != "" {
-			apiKey = envKey
-		} else {
-			apiKey = cfg.APIKey
-		}
-	}
-
-	// Check for custom API endpoint (for testing)
-	opts := []veo3.ClientOption{}
-	if apiEndpoint := os.Getenv("VEO3_API_ENDPOINT"); apiEndpoint != "" {
-		opts = append(opts, veo3.WithBaseURL(apiEndpoint))
-	}
-
-	client, err := veo3.NewClient(context.Background(), apiKey, opts...)
+	// Create API client
+	client, err := createVeo3Client(cfg)
 	if err != nil {
 		return handleError(err, jsonFormat, pretty)
 	}
 
+	notifier := newWebhookNotifier(cmd, cfg)
+	hookRunner := hooks.NewRunner(cfg.PostDownloadHooks)
+
 	// Check if reference images are provided
 	if len(referenceImages) > 0 {
-		return handleReferenceImageGeneration(ctx, client, prompt, negativePrompt, model,
+		return handleReferenceImageGeneration(ctx, client, notifier, hookRunner, template, prompt, negativePrompt, model,
 			resolution, duration, aspectRatio, referenceImages, outputDir, filename,
 			noWait, noDownload, jsonFormat, pretty)
 	}
@@ -264,6 +226,8 @@ func runGenerateText(cmd *cobra.Command, args []string) error {
 		return handleError(err, jsonFormat, pretty)
 	}
 
+	recordTemplate(template, operation, nil)
+
 	// Handle async mode
 	if noWait {
 		return outputOperation(operation, jsonFormat, pretty)
@@ -278,19 +242,29 @@ func runGenerateText(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return handleError(err, jsonFormat, pretty)
 	}
+	recordTemplate(template, operation, nil)
 
 	// Download video if completed and not disabled
+	var outputPath string
 	if operation.Status == veo3.StatusDone && !noDownload {
 		if !jsonFormat {
 			fmt.Println("✓ Generation completed!")
 		}
 
-		err = downloadVideo(ctx, operation, outputDir, filename, jsonFormat)
+		video, err := downloadVideo(ctx, client, operation, outputDir, filename, jsonFormat)
 		if err != nil {
 			return handleError(err, jsonFormat, pretty)
 		}
+		outputPath = video.FilePath
+		describeVideo(video, request)
+		recordTemplate(template, nil, video)
+		if _, err := runPostDownloadHooks(ctx, hookRunner, video, jsonFormat); err != nil {
+			return handleError(err, jsonFormat, pretty)
+		}
 	}
 
+	notifyWebhooks(ctx, notifier, operation, outputPath, "", jsonFormat)
+
 	return outputOperation(operation, jsonFormat, pretty)
 }
 
@@ -307,6 +281,14 @@ func getStringWithDefault(cmd *cobra.Command, flag string, defaultValue string)
 	return value
 }
 
+// intOr returns value, or fallback when value is zero
+func intOr(value, fallback int) int {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
 func getIntWithDefault(cmd *cobra.Command, flag string, defaultValue int) int {
 	value, _ := cmd.Flags().GetInt(flag)
 	if value == 0 {
@@ -318,6 +300,31 @@ func getIntWithDefault(cmd *cobra.Command, flag string, defaultValue int) int {
 	return value
 }
 
+// resolveAPIKey returns the API key from the --api-key flag, or else as the
+// configuration resolves it
+func resolveAPIKey(cfg *config.Configuration) (string, error) {
+	if apiKey := viper.GetString("api-key"); apiKey != "" {
+		return apiKey, nil
+	}
+	return cfg.ResolveAPIKey(context.Background())
+}
+
+// clientOptions returns client options derived from the environment
+func clientOptions() []veo3.ClientOption {
+	opts := []veo3.ClientOption{veo3.WithLogger(logger.Slog())}
+
+	if wireDebug() {
+		opts = append(opts, veo3.WithMiddleware(veo3.WireLogger(logger.Slog())))
+	}
+
+	// Check for custom API endpoint (for testing)
+	if apiEndpoint := os.Getenv("VEO3_API_ENDPOINT"); apiEndpoint != "" {
+		opts = append(opts, veo3.WithBaseURL(apiEndpoint))
+	}
+
+	return opts
+}
+
 func handleError(err error, jsonFormat bool, _ bool) error {
 	if jsonFormat {
 		jsonOutput, _ := format.FormatErrorJSON("ERROR", err.Error(), nil)
@@ -356,6 +363,21 @@ func pollOperation(ctx context.Context, client *veo3.Client, operationID string,
 		)
 	}
 
+	return watchOperation(ctx, client, operationID, func(operation *veo3.Operation) {
+		if bar == nil {
+			return
+		}
+		_ = bar.Add(1)
+		switch operation.Status {
+		case veo3.StatusDone, veo3.StatusFailed, veo3.StatusCancelled:
+			_ = bar.Finish()
+		}
+	})
+}
+
+// watchOperation polls an operation until it reaches a terminal state,
+// calling onPoll with every polled state
+func watchOperation(ctx context.Context, client *veo3.Client, operationID string, onPoll func(*veo3.Operation)) (*veo3.Operation, error) {
 	ticker := time.NewTicker(5 * time.Second) // Poll every 5 seconds
 	defer ticker.Stop()
 
@@ -369,34 +391,32 @@ func pollOperation(ctx context.Context, client *veo3.Client, operationID string,
 				return nil, err
 			}
 
-			if !jsonFormat && bar != nil {
-				_ = bar.Add(1)
+			if onPoll != nil {
+				onPoll(operation)
 			}
 
 			switch operation.Status {
 			case veo3.StatusDone, veo3.StatusFailed, veo3.StatusCancelled:
-				if !jsonFormat && bar != nil {
-					_ = bar.Finish()
-				}
 				return operation, nil
 			}
 		}
 	}
 }
 
-func downloadVideo(ctx context.Context, operation *veo3.Operation, outputDir string, filename string, jsonFormat bool) error {
+// downloadVideo downloads the operation's video and returns the output path
+func downloadVideo(ctx context.Context, client *veo3.Client, operation *veo3.Operation, outputDir string, filename string, jsonFormat bool) (*veo3.GeneratedVideo, error) {
 	if operation.VideoURI == "" {
 		// Provide detailed error message with debugging hints
 		if operation.Status == veo3.StatusDone {
-			return fmt.Errorf("no video URI in completed operation\n\n"+
+			return nil, fmt.Errorf("no video URI in completed operation\n\n"+
 				"The operation completed successfully but the video URI was not extracted from the API response.\n"+
 				"This could be due to an unexpected API response format.\n\n"+
 				"To debug this issue:\n"+
-				"1. Set VEO3_DEBUG=1 environment variable to see the raw API response\n"+
+				"1. Re-run with --wire-debug (or VEO3_DEBUG=1) to log the API responses\n"+
 				"2. Check if the operation actually generated a video: veo3 operations get %s\n"+
 				"3. Review the API response format in the debug logs", operation.ID)
 		}
-		return fmt.Errorf("no video URI in completed operation")
+		return nil, fmt.Errorf("no video URI in completed operation")
 	}
 
 	// Use default output directory if not specified
@@ -414,22 +434,23 @@ func downloadVideo(ctx context.Context, operation *veo3.Operation, outputDir str
 	outputPath := filepath.Join(outputDir, filename)
 
 	// Create downloader with progress display (opposite of jsonFormat)
-	downloader := operations.NewDownloader(!jsonFormat)
+	downloader := operations.NewDownloaderForClient(client, !jsonFormat)
 
 	if !jsonFormat {
 		fmt.Printf("⬇ Downloading video to %s...\n", outputPath)
 	}
 
-	_, err := downloader.DownloadVideo(ctx, operation, outputPath)
+	video, err := downloader.DownloadVideo(ctx, operation, outputPath)
 	if err != nil {
-		return fmt.Errorf("download failed: %w", err)
+		return nil, fmt.Errorf("download failed: %w", err)
 	}
 
-	return nil
+	return video, nil
 }
 
 // handleReferenceImageGeneration handles generation with reference images
-func handleReferenceImageGeneration(ctx context.Context, client *veo3.Client, prompt, negativePrompt, model,
+func handleReferenceImageGeneration(ctx context.Context, client *veo3.Client, notifier *webhooks.Notifier, hookRunner *hooks.Runner,
+	template *templates.Template, prompt, negativePrompt, model,
 	resolution string, duration int, aspectRatio string, referenceImages []string, outputDir, filename string,
 	noWait, noDownload, jsonFormat, pretty bool) error {
 
@@ -475,6 +496,8 @@ func handleReferenceImageGeneration(ctx context.Context, client *veo3.Client, pr
 		return handleError(err, jsonFormat, pretty)
 	}
 
+	recordTemplate(template, operation, nil)
+
 	// Handle async mode
 	if noWait {
 		return outputOperation(operation, jsonFormat, pretty)
@@ -489,18 +512,38 @@ func handleReferenceImageGeneration(ctx context.Context, client *veo3.Client, pr
 	if err != nil {
 		return handleError(err, jsonFormat, pretty)
 	}
+	recordTemplate(template, operation, nil)
 
 	// Download video if completed and not disabled
+	var outputPath string
 	if operation.Status == veo3.StatusDone && !noDownload {
 		if !jsonFormat {
 			fmt.Println("✓ Reference-guided generation completed!")
 		}
 
-		err = downloadVideo(ctx, operation, outputDir, filename, jsonFormat)
+		video, err := downloadVideo(ctx, client, operation, outputDir, filename, jsonFormat)
 		if err != nil {
 			return handleError(err, jsonFormat, pretty)
 		}
+		outputPath = video.FilePath
+		describeVideo(video, &request.GenerationRequest)
+		recordTemplate(template, nil, video)
+		if _, err := runPostDownloadHooks(ctx, hookRunner, video, jsonFormat); err != nil {
+			return handleError(err, jsonFormat, pretty)
+		}
 	}
 
+	notifyWebhooks(ctx, notifier, operation, outputPath, "", jsonFormat)
+
 	return outputOperation(operation, jsonFormat, pretty)
 }
+
+// describeVideo fills video metadata from the request that produced it, since
+// polled operations do not carry the original request parameters
+func describeVideo(video *veo3.GeneratedVideo, request *veo3.GenerationRequest) {
+	video.Prompt = request.Prompt
+	video.Model = request.Model
+	video.Resolution = request.Resolution
+	video.AspectRatio = request.AspectRatio
+	video.DurationSeconds = request.DurationSeconds
+}
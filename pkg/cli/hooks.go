@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jasongoecke/go-veo3/pkg/hooks"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+)
+
+// runPostDownloadHooks runs the configured hooks for a downloaded video and
+// prints a line per hook unless JSON output is requested
+func runPostDownloadHooks(ctx context.Context, runner *hooks.Runner, video *veo3.GeneratedVideo, jsonFormat bool) ([]hooks.Result, error) {
+	if !runner.Enabled() {
+		return nil, nil
+	}
+
+	results, err := runner.Run(ctx, video)
+
+	if !jsonFormat {
+		for _, result := range results {
+			switch {
+			case result.Skipped:
+				fmt.Printf("⏭  Hook %s skipped\n", result.Name)
+			case result.Success:
+				fmt.Printf("🔗 Hook %s completed (%.1fs)\n", result.Name, result.Duration.Seconds())
+			default:
+				fmt.Printf("❌ Hook %s failed: %s\n", result.Name, result.Error)
+			}
+		}
+	}
+
+	return results, err
+}
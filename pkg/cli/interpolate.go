@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/jasongoecke/go-veo3/pkg/config"
+	"github.com/jasongoecke/go-veo3/pkg/hooks"
+	"github.com/jasongoecke/go-veo3/pkg/templates"
 	"github.com/jasongoecke/go-veo3/pkg/veo3"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -24,7 +26,9 @@ to 8 seconds duration and 16:9 aspect ratio as per API requirements.
 
 Supported image formats: JPEG, PNG, WebP
 Maximum image size: 20MB each
-Images must have identical dimensions.`,
+Images must have identical dimensions unless --match-dimensions is set, in
+which case the last frame is rescaled (scale) or scaled and center-cropped
+(crop) to match the first frame.`,
 		Example: `  # Interpolate between two frames
   veo3 interpolate start.jpg end.jpg
 
@@ -35,16 +39,29 @@ Images must have identical dimensions.`,
   veo3 interpolate start.jpg end.jpg --resolution 1080p
 
   # Save to specific directory
-  veo3 interpolate frame1.jpg frame2.jpg --output ./videos/`,
-		Args: cobra.ExactArgs(2),
+  veo3 interpolate frame1.jpg frame2.jpg --output ./videos/
+
+  # Crop a screenshot last frame to match the first frame's size
+  veo3 interpolate start.jpg screenshot.png --match-dimensions crop
+
+  # Use a template's prompt and preset (frames can come from the preset)
+  veo3 interpolate -t day-to-night`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 && len(args) != 2 {
+				return fmt.Errorf("accepts a first and last frame, or none with a template preset, received %d", len(args))
+			}
+			return nil
+		},
 		RunE: runInterpolate,
 	}
 
 	// Add flags (note: duration and aspect-ratio are fixed for interpolation)
 	interpolateCmd.Flags().StringP("prompt", "p", "", "Optional prompt to guide the transition")
+	addTemplateFlags(interpolateCmd)
 	interpolateCmd.Flags().StringP("resolution", "r", "", "Resolution (720p or 1080p)")
 	interpolateCmd.Flags().StringP("model", "m", "", "Model to use (must support interpolation)")
 	interpolateCmd.Flags().String("negative-prompt", "", "Negative prompt (elements to exclude)")
+	interpolateCmd.Flags().String("match-dimensions", "", "Reconcile last frame with first frame dimensions (scale or crop)")
 	interpolateCmd.Flags().String("output", "", "Output directory for downloaded video")
 	interpolateCmd.Flags().String("filename", "", "Custom filename for output video")
 	interpolateCmd.Flags().Bool("no-wait", false, "Start generation and return immediately")
@@ -64,12 +81,12 @@ Images must have identical dimensions.`,
 
 // runInterpolate handles frame interpolation
 func runInterpolate(cmd *cobra.Command, args []string) error {
-	firstFramePath := args[0]
-	lastFramePath := args[1]
-
 	// Load configuration using manager
-	manager := config.NewManager("")
+	manager := newConfigManager()
 	cfg, err := manager.Load()
+	if fatalConfigError(err) {
+		return err
+	}
 	if err != nil {
 		// Use defaults if config load fails
 		cfg = &config.Configuration{
@@ -82,11 +99,25 @@ func runInterpolate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Get flag values with config fallbacks
-	prompt, _ := cmd.Flags().GetString("prompt")
-	resolution := getStringWithDefault(cmd, "resolution", cfg.DefaultResolution)
-	model := getStringWithDefault(cmd, "model", cfg.DefaultModel)
-	negativePrompt, _ := cmd.Flags().GetString("negative-prompt")
+	prompt, template, err := resolveTemplate(cmd, templates.TypeInterpolate)
+	if err != nil {
+		return handleError(err, viper.GetBool("json"), false)
+	}
+	preset := templatePreset(template)
+
+	firstFramePath, lastFramePath := preset.Image, preset.LastFrame
+	if len(args) == 2 {
+		firstFramePath, lastFramePath = args[0], args[1]
+	}
+	if firstFramePath == "" || lastFramePath == "" {
+		return handleError(fmt.Errorf("first and last frames are required (arguments or template preset)"), viper.GetBool("json"), false)
+	}
+
+	// Get flag values with template preset and config fallbacks
+	resolution := getStringWithDefault(cmd, "resolution", valueOr(preset.Resolution, cfg.DefaultResolution))
+	model := getStringWithDefault(cmd, "model", valueOr(preset.Model, cfg.DefaultModel))
+	negativePrompt := getStringWithDefault(cmd, "negative-prompt", preset.NegativePrompt)
+	matchDimensions, _ := cmd.Flags().GetString("match-dimensions")
 	outputDir := getStringWithDefault(cmd, "output", cfg.OutputDirectory)
 	filename, _ := cmd.Flags().GetString("filename")
 	noWait, _ := cmd.Flags().GetBool("no-wait")
@@ -94,6 +125,11 @@ func runInterpolate(cmd *cobra.Command, args []string) error {
 	jsonFormat := viper.GetBool("json")
 	pretty, _ := cmd.Flags().GetBool("pretty")
 
+	frameFit, err := veo3.ParseFrameFitMode(matchDimensions)
+	if err != nil {
+		return handleError(err, jsonFormat, pretty)
+	}
+
 	// Create interpolation request with fixed constraints
 	request := &veo3.InterpolationRequest{
 		GenerationRequest: veo3.GenerationRequest{
@@ -107,6 +143,7 @@ func runInterpolate(cmd *cobra.Command, args []string) error {
 		},
 		FirstFramePath: firstFramePath,
 		LastFramePath:  lastFramePath,
+		FrameFit:       frameFit,
 	}
 
 	// Validate request
@@ -115,12 +152,7 @@ func runInterpolate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiKey := viper.GetString("api-key")
-	if apiKey == "" {
-		apiKey = cfg.APIKey
-	}
-
-	client, err := veo3.NewClient(context.Background(), apiKey)
+	client, err := createVeo3Client(cfg)
 	if err != nil {
 		return handleError(err, jsonFormat, pretty)
 	}
@@ -146,6 +178,14 @@ func runInterpolate(cmd *cobra.Command, args []string) error {
 			fmt.Printf("📸 %dx%d %s (%.1f MB)\n",
 				lastInfo.Width, lastInfo.Height, lastInfo.Format, sizeMB)
 		}
+
+		// Report how the last frame will be reconciled
+		if frameFit != veo3.FrameFitNone {
+			plan, err := veo3.PlanFrameReconciliation(firstFramePath, lastFramePath, frameFit)
+			if err == nil && plan.Applied() {
+				fmt.Printf("🔧 %s\n", plan)
+			}
+		}
 	}
 
 	// Submit interpolation request
@@ -155,6 +195,8 @@ func runInterpolate(cmd *cobra.Command, args []string) error {
 		return handleError(err, jsonFormat, pretty)
 	}
 
+	recordTemplate(template, operation, nil)
+
 	// Handle async mode
 	if noWait {
 		return outputOperation(operation, jsonFormat, pretty)
@@ -169,18 +211,28 @@ func runInterpolate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return handleError(err, jsonFormat, pretty)
 	}
+	recordTemplate(template, operation, nil)
 
 	// Download video if completed and not disabled
+	var outputPath string
 	if operation.Status == veo3.StatusDone && !noDownload {
 		if !jsonFormat {
 			fmt.Println("✓ Interpolation completed!")
 		}
 
-		err = downloadVideo(ctx, operation, outputDir, filename, jsonFormat)
+		video, err := downloadVideo(ctx, client, operation, outputDir, filename, jsonFormat)
 		if err != nil {
 			return handleError(err, jsonFormat, pretty)
 		}
+		outputPath = video.FilePath
+		describeVideo(video, &request.GenerationRequest)
+		recordTemplate(template, nil, video)
+		if _, err := runPostDownloadHooks(ctx, hooks.NewRunner(cfg.PostDownloadHooks), video, jsonFormat); err != nil {
+			return handleError(err, jsonFormat, pretty)
+		}
 	}
 
+	notifyWebhooks(ctx, newWebhookNotifier(cmd, cfg), operation, outputPath, "", jsonFormat)
+
 	return outputOperation(operation, jsonFormat, pretty)
 }
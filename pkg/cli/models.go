@@ -171,6 +171,9 @@ func runModelsInfo(cmd *cobra.Command, modelID string, jsonFormat bool) error {
 	cmd.Printf("ID: %s\n", model.ID)
 	cmd.Printf("Version: %s\n", model.Version)
 	cmd.Printf("Tier: %s\n", model.Tier)
+	if model.PricePerSecond > 0 {
+		cmd.Printf("Estimated Price: $%.2f per second\n", model.PricePerSecond)
+	}
 	cmd.Println()
 
 	// Capabilities
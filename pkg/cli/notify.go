@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jasongoecke/go-veo3/pkg/config"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+	"github.com/jasongoecke/go-veo3/pkg/webhooks"
+	"github.com/spf13/cobra"
+)
+
+// newWebhookNotifier builds a notifier from configured webhooks plus any --webhook flags
+func newWebhookNotifier(cmd *cobra.Command, cfg *config.Configuration) *webhooks.Notifier {
+	urls := append([]string{}, cfg.Webhooks...)
+	if flagURLs, err := cmd.Flags().GetStringSlice("webhook"); err == nil {
+		urls = append(urls, flagURLs...)
+	}
+
+	secret := cfg.WebhookSecret
+	if secret == "" {
+		secret = os.Getenv("VEO3_WEBHOOK_SECRET")
+	}
+
+	return webhooks.NewNotifier(urls, secret)
+}
+
+// notifyWebhooks delivers a completion notification. Delivery failures are
+// reported as warnings and never fail the command.
+func notifyWebhooks(ctx context.Context, notifier *webhooks.Notifier, operation *veo3.Operation, filePath, jobID string, jsonFormat bool) {
+	if !notifier.Enabled() {
+		return
+	}
+
+	err := notifier.Notify(ctx, webhooks.Notification{
+		Operation: operation,
+		FilePath:  filePath,
+		JobID:     jobID,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Webhook delivery failed: %v\n", err)
+		return
+	}
+
+	if !jsonFormat && jobID == "" {
+		fmt.Println("📨 Webhook notification sent")
+	}
+}
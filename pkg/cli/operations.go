@@ -253,8 +253,11 @@ func runOperationsDownload(cmd *cobra.Command, args []string) error {
 	operationID := args[0]
 
 	// Load configuration
-	manager := config.NewManager("")
-	cfg, _ := manager.Load()
+	manager := newConfigManager()
+	cfg, err := manager.Load()
+	if fatalConfigError(err) {
+		return err
+	}
 	if cfg == nil {
 		cfg = &config.Configuration{OutputDirectory: "."}
 	}
@@ -292,7 +295,11 @@ func runOperationsDownload(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create downloader
-	downloader := operations.NewDownloader(!jsonFormat)
+	client, err := createVeo3Client(cfg)
+	if err != nil {
+		return handleError(err, jsonFormat, false)
+	}
+	downloader := operations.NewDownloaderForClient(client, !jsonFormat)
 
 	// Generate output path
 	if filename == "" {
@@ -341,8 +348,14 @@ func runOperationsCancel(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiKey := viper.GetString("api-key")
-	client, err := veo3.NewClient(context.Background(), apiKey)
+	cfg, err := newConfigManager().Load()
+	if fatalConfigError(err) {
+		return handleError(err, jsonFormat, false)
+	}
+	if cfg == nil {
+		cfg = &config.Configuration{}
+	}
+	client, err := createVeo3Client(cfg)
 	if err != nil {
 		return handleError(err, jsonFormat, false)
 	}
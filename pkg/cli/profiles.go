@@ -0,0 +1,365 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jasongoecke/go-veo3/internal/format"
+	"github.com/jasongoecke/go-veo3/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// newConfigProfilesCmd creates the 'config profiles' command group
+func newConfigProfilesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "Manage named configuration profiles",
+		Long: `Manage named configuration profiles.
+
+A profile overrides the API key source, base URL, generation defaults, output
+directory and limits of the top-level configuration. Unset fields inherit the
+top-level values. The profile in use is chosen by --profile, then the
+VEO3_PROFILE environment variable, then the active profile set with
+"veo3 config profiles use".`,
+		Example: `  # Create a profile for a team quota project
+  veo3 config profiles create team --api-key-command "pass show veo3/team" --resolution 1080p
+
+  # Create a profile for a local fake endpoint
+  veo3 config profiles create staging --base-url http://localhost:8089 --api-key fake
+
+  # Use a profile for one command
+  veo3 generate -p "A lighthouse at dusk" --profile staging
+
+  # Make a profile the default
+  veo3 config profiles use team`,
+	}
+
+	cmd.AddCommand(
+		newConfigProfilesListCmd(),
+		newConfigProfilesUseCmd(),
+		newConfigProfilesCreateCmd(),
+		newConfigProfilesDeleteCmd(),
+	)
+
+	return cmd
+}
+
+func newConfigProfilesListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List configuration profiles",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonFormat := viper.GetBool("json")
+			out := cmd.OutOrStdout()
+
+			cfg, err := profilesManager(cmd).LoadBase()
+			if err != nil {
+				return configLoadError(err)
+			}
+			selected := selectedProfileName(cfg)
+
+			if jsonFormat {
+				profiles := make([]map[string]interface{}, 0, len(cfg.Profiles))
+				for _, name := range cfg.ProfileNames() {
+					profiles = append(profiles, map[string]interface{}{
+						"name":     name,
+						"selected": name == selected,
+						"settings": cfg.Profiles[name],
+					})
+				}
+				jsonOutput, _ := format.FormatGenericJSON(map[string]interface{}{
+					"success": true,
+					"data": map[string]interface{}{
+						"active_profile":   cfg.ActiveProfile,
+						"selected_profile": selected,
+						"profiles":         profiles,
+					},
+				})
+				_, _ = fmt.Fprintln(out, jsonOutput)
+				return nil
+			}
+
+			if len(cfg.Profiles) == 0 {
+				_, _ = fmt.Fprintln(out, "No profiles configured")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "\tNAME\tAPI KEY\tBASE URL\tMODEL")
+			for _, name := range cfg.ProfileNames() {
+				profile := cfg.Profiles[name]
+				marker := ""
+				if name == selected {
+					marker = "*"
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", marker, name,
+					profileKeySource(profile), valueOr(profile.BaseURL, "-"), valueOr(profile.DefaultModel, "-"))
+			}
+			_ = w.Flush()
+			return nil
+		},
+	}
+}
+
+func newConfigProfilesUseCmd() *cobra.Command {
+	var none bool
+
+	cmd := &cobra.Command{
+		Use:   "use [name]",
+		Short: "Set the active profile",
+		Long: `Set the profile applied when neither --profile nor VEO3_PROFILE is given.
+Use --none to go back to the top-level configuration.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if none == (len(args) == 1) {
+				return fmt.Errorf("specify a profile name or --none")
+			}
+
+			manager := profilesManager(cmd)
+			cfg, err := manager.LoadBase()
+			if err != nil {
+				return configLoadError(err)
+			}
+
+			name := ""
+			if !none {
+				name = args[0]
+				if _, ok := cfg.Profiles[name]; !ok {
+					return fmt.Errorf("profile %q not found", name)
+				}
+			}
+
+			cfg.ActiveProfile = name
+			if err := manager.Save(cfg); err != nil {
+				return fmt.Errorf("failed to save configuration: %w", err)
+			}
+
+			if name == "" {
+				return profilesResult(cmd, "Active profile cleared", "")
+			}
+			return profilesResult(cmd, fmt.Sprintf("Active profile set to '%s'", name), name)
+		},
+	}
+
+	cmd.Flags().BoolVar(&none, "none", false, "Clear the active profile")
+
+	return cmd
+}
+
+func newConfigProfilesCreateCmd() *cobra.Command {
+	var (
+		profile config.Profile
+		from    string
+		use     bool
+		force   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create or replace a profile",
+		Long: `Create a profile. Fields that are not set inherit the top-level configuration.
+Setting any API key source (--api-key, --api-key-env or --api-key-command)
+replaces all top-level key sources while the profile is in use.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if err := config.ValidateProfileName(name); err != nil {
+				return err
+			}
+
+			manager := profilesManager(cmd)
+			cfg, err := manager.LoadBase()
+			if err != nil {
+				if !errors.Is(err, fs.ErrNotExist) {
+					return err
+				}
+				cfg = &config.Configuration{
+					DefaultModel:        config.DefaultModel,
+					DefaultResolution:   config.DefaultResolution,
+					DefaultAspectRatio:  config.DefaultAspectRatio,
+					DefaultDuration:     config.DefaultDuration,
+					OutputDirectory:     ".",
+					PollIntervalSeconds: config.DefaultPollInterval,
+					ConfigVersion:       config.DefaultConfigVersion,
+				}
+			}
+
+			if _, exists := cfg.Profiles[name]; exists && !force {
+				return fmt.Errorf("profile %q already exists (use --force to replace it)", name)
+			}
+
+			newProfile := profile
+			if from != "" {
+				base, ok := cfg.Profiles[from]
+				if !ok {
+					return fmt.Errorf("profile %q not found", from)
+				}
+				newProfile = mergeProfileFlags(cmd, base, profile)
+			}
+
+			if cfg.Profiles == nil {
+				cfg.Profiles = make(map[string]config.Profile)
+			}
+			cfg.Profiles[name] = newProfile
+			if use {
+				cfg.ActiveProfile = name
+			}
+
+			if err := cfg.Validate(); err != nil {
+				return fmt.Errorf("invalid profile: %w", err)
+			}
+			if err := manager.Save(cfg); err != nil {
+				return fmt.Errorf("failed to save configuration: %w", err)
+			}
+
+			return profilesResult(cmd, fmt.Sprintf("Profile '%s' saved", name), name)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile.APIKey, "api-key", "", "API key stored in the profile")
+	cmd.Flags().StringVar(&profile.APIKeyEnv, "api-key-env", "", "Environment variable holding the API key")
+	cmd.Flags().StringVar(&profile.APIKeyCommand, "api-key-command", "", "Command that prints the API key")
+	cmd.Flags().StringVar(&profile.BaseURL, "base-url", "", "API endpoint")
+	cmd.Flags().StringVar(&profile.DefaultModel, "model", "", "Default model")
+	cmd.Flags().StringVar(&profile.DefaultResolution, "resolution", "", "Default resolution")
+	cmd.Flags().StringVar(&profile.DefaultAspectRatio, "aspect-ratio", "", "Default aspect ratio")
+	cmd.Flags().IntVar(&profile.DefaultDuration, "duration", 0, "Default duration in seconds")
+	cmd.Flags().StringVar(&profile.OutputDirectory, "output", "", "Default output directory")
+	cmd.Flags().IntVar(&profile.PollIntervalSeconds, "poll-interval", 0, "Status polling interval in seconds")
+	cmd.Flags().IntVar(&profile.QueueConcurrency, "queue-concurrency", 0, "Jobs run at once by \"veo3 queue run\"")
+	cmd.Flags().StringVar(&from, "from", "", "Copy settings from an existing profile")
+	cmd.Flags().BoolVar(&use, "use", false, "Make the new profile active")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing profile")
+
+	return cmd
+}
+
+func newConfigProfilesDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a profile",
+		Long:  "Delete a profile. Deleting the active profile clears the active profile.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			manager := profilesManager(cmd)
+			cfg, err := manager.LoadBase()
+			if err != nil {
+				return configLoadError(err)
+			}
+			if _, ok := cfg.Profiles[name]; !ok {
+				return fmt.Errorf("profile %q not found", name)
+			}
+
+			delete(cfg.Profiles, name)
+			if cfg.ActiveProfile == name {
+				cfg.ActiveProfile = ""
+			}
+			if err := manager.Save(cfg); err != nil {
+				return fmt.Errorf("failed to save configuration: %w", err)
+			}
+
+			return profilesResult(cmd, fmt.Sprintf("Profile '%s' deleted", name), name)
+		},
+	}
+}
+
+// profilesManager returns a manager for the config file in use (respects --config)
+func profilesManager(cmd *cobra.Command) *config.Manager {
+	configPath := viper.ConfigFileUsed()
+	if configPath == "" {
+		configPath, _ = cmd.Root().PersistentFlags().GetString("config")
+	}
+	return config.NewManager(configPath)
+}
+
+// selectedProfileName returns the profile that commands would use
+func selectedProfileName(cfg *config.Configuration) string {
+	if profile := viper.GetString("profile"); profile != "" {
+		return profile
+	}
+	if profile := os.Getenv("VEO3_PROFILE"); profile != "" {
+		return profile
+	}
+	return cfg.ActiveProfile
+}
+
+// mergeProfileFlags overlays the flags set on the command onto a copied profile
+func mergeProfileFlags(cmd *cobra.Command, base, flags config.Profile) config.Profile {
+	changed := cmd.Flags().Changed
+	if changed("api-key") || changed("api-key-env") || changed("api-key-command") {
+		base.APIKey, base.APIKeyEnv, base.APIKeyCommand = flags.APIKey, flags.APIKeyEnv, flags.APIKeyCommand
+	}
+	if changed("base-url") {
+		base.BaseURL = flags.BaseURL
+	}
+	if changed("model") {
+		base.DefaultModel = flags.DefaultModel
+	}
+	if changed("resolution") {
+		base.DefaultResolution = flags.DefaultResolution
+	}
+	if changed("aspect-ratio") {
+		base.DefaultAspectRatio = flags.DefaultAspectRatio
+	}
+	if changed("duration") {
+		base.DefaultDuration = flags.DefaultDuration
+	}
+	if changed("output") {
+		base.OutputDirectory = flags.OutputDirectory
+	}
+	if changed("poll-interval") {
+		base.PollIntervalSeconds = flags.PollIntervalSeconds
+	}
+	if changed("queue-concurrency") {
+		base.QueueConcurrency = flags.QueueConcurrency
+	}
+	return base
+}
+
+// profileKeySource describes where a profile gets its API key without revealing it
+func profileKeySource(profile config.Profile) string {
+	switch {
+	case profile.APIKeyCommand != "":
+		return "command"
+	case profile.APIKeyEnv != "":
+		return "$" + profile.APIKeyEnv
+	case profile.APIKey != "":
+		return maskSensitiveValue(profile.APIKey)
+	default:
+		return "inherited"
+	}
+}
+
+// profilesResult prints the outcome of a profile change
+func profilesResult(cmd *cobra.Command, message, name string) error {
+	out := cmd.OutOrStdout()
+	if viper.GetBool("json") {
+		jsonOutput, _ := format.FormatGenericJSON(map[string]interface{}{
+			"success": true,
+			"data": map[string]interface{}{
+				"message": message,
+				"profile": name,
+			},
+		})
+		_, _ = fmt.Fprintln(out, jsonOutput)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "✓ %s\n", message)
+	return nil
+}
+
+// valueOr returns value, or fallback when it is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
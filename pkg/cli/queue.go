@@ -0,0 +1,482 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/internal/format"
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/jasongoecke/go-veo3/pkg/config"
+	"github.com/jasongoecke/go-veo3/pkg/hooks"
+	"github.com/jasongoecke/go-veo3/pkg/queue"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// jobPathOptions are job options holding local file paths
+var jobPathOptions = []string{"image", "first_frame", "last_frame", "video"}
+
+// newQueueCmd creates the queue command group
+func newQueueCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Queue jobs for later submission",
+		Long: `Manage a durable on-disk job queue.
+
+Jobs use the same shape as batch manifest jobs (generate, animate, interpolate,
+extend) with an optional priority and not-before time. Anyone can add jobs during
+the day; a long-lived 'veo3 queue run' process submits them under the configured
+concurrency (queue-concurrency, default 3).
+
+The queue is stored in queue.yaml in the veo3 data directory (~/.veo3 by default).`,
+		Example: `  # Queue a generation
+  veo3 queue add generate -p "A sunset over mountains" -o sunset.mp4
+
+  # Queue an urgent animation that must not start before 22:00
+  veo3 queue add animate -O image=photo.png -p "The person waves" -o wave.mp4 \
+    --priority 10 --not-before 22:00
+
+  # Queue every job in a batch manifest
+  veo3 queue add --manifest jobs.yaml
+
+  # Drain the queue overnight
+  veo3 queue run`,
+	}
+
+	cmd.AddCommand(newQueueAddCmd())
+	cmd.AddCommand(newQueueListCmd())
+	cmd.AddCommand(newQueueRemoveCmd())
+	cmd.AddCommand(newQueueRunCmd())
+
+	return cmd
+}
+
+// newQueueAddCmd creates the 'queue add' command
+func newQueueAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add [type]",
+		Short: "Add a job to the queue",
+		Long: `Add a job to the queue.
+
+The job type is one of generate, animate, interpolate or extend. Options use the
+same keys as batch manifest jobs (prompt, model, duration, image, first_frame,
+last_frame, video, ...). Relative file paths are resolved against the current
+directory so the runner can be started from anywhere.
+
+--not-before accepts RFC 3339 ("2025-01-02T22:00:00Z"), a local date and time
+("2025-01-02 22:00") or a local time of day ("22:00", the next occurrence).`,
+		Example: `  veo3 queue add generate -p "A cat playing piano" -o cat.mp4
+  veo3 queue add generate -p "Ocean waves" -O duration=6 -O resolution=1080p -o waves.mp4
+  veo3 queue add --manifest jobs.yaml --priority 5`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runQueueAdd,
+	}
+
+	cmd.Flags().String("id", "", "Job ID (default: generated)")
+	cmd.Flags().StringP("prompt", "p", "", "Prompt for the job")
+	cmd.Flags().StringArrayP("option", "O", nil, "Job option as key=value (repeatable)")
+	cmd.Flags().StringP("output", "o", "", "Output video path")
+	cmd.Flags().Int("priority", 0, "Priority; higher runs first")
+	cmd.Flags().String("not-before", "", "Earliest time the job may start")
+	cmd.Flags().String("manifest", "", "Queue every job in a batch manifest")
+
+	return cmd
+}
+
+// newQueueListCmd creates the 'queue list' command
+func newQueueListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List queued, running and finished jobs",
+		Example: `  veo3 queue list
+  veo3 queue list --status queued --json`,
+		RunE: runQueueList,
+	}
+
+	cmd.Flags().String("status", "", "Filter by status (queued, running, done, failed)")
+
+	return cmd
+}
+
+// newQueueRemoveCmd creates the 'queue remove' command
+func newQueueRemoveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove [id...]",
+		Short: "Remove jobs from the queue",
+		Long:  "Remove queued or finished jobs by ID. Running jobs cannot be removed.",
+		Example: `  veo3 queue remove q3 q4
+  veo3 queue remove --finished`,
+		RunE: runQueueRemove,
+	}
+
+	cmd.Flags().Bool("finished", false, "Remove all done and failed jobs")
+
+	return cmd
+}
+
+// newQueueRunCmd creates the 'queue run' command
+func newQueueRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run queued jobs",
+		Long: `Submit queued jobs in priority order, waiting for not-before times.
+
+The runner keeps watching the queue for new jobs until interrupted, or exits once
+the queue is empty with --exit-when-empty. Jobs interrupted by a previous runner
+are requeued on start.`,
+		Example: `  veo3 queue run
+  veo3 queue run --concurrency 2 --exit-when-empty`,
+		RunE: runQueueRun,
+	}
+
+	cmd.Flags().IntP("concurrency", "c", 0, "Jobs to run at once (default from config)")
+	cmd.Flags().Bool("exit-when-empty", false, "Exit when no queued jobs remain")
+	cmd.Flags().Duration("poll-interval", queue.DefaultPollInterval, "How often to check for new jobs")
+	cmd.Flags().String("output-dir", "", "Output directory for all videos (overrides job paths)")
+
+	return cmd
+}
+
+func runQueueAdd(cmd *cobra.Command, args []string) error {
+	jsonFormat := viper.GetBool("json")
+
+	priority, _ := cmd.Flags().GetInt("priority")
+	var notBefore *time.Time
+	if value, _ := cmd.Flags().GetString("not-before"); value != "" {
+		parsed, err := parseNotBefore(value, time.Now())
+		if err != nil {
+			return handleError(err, jsonFormat, false)
+		}
+		notBefore = &parsed
+	}
+
+	jobs, err := queueJobsFromFlags(cmd, args)
+	if err != nil {
+		return handleError(err, jsonFormat, false)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return handleError(fmt.Errorf("failed to get working directory: %w", err), jsonFormat, false)
+	}
+
+	entries := make([]queue.Entry, 0, len(jobs))
+	for _, job := range jobs {
+		resolveJobPaths(&job, cwd)
+		entries = append(entries, queue.Entry{BatchJob: job, Priority: priority, NotBefore: notBefore})
+	}
+
+	store, err := getQueueStore()
+	if err != nil {
+		return handleError(err, jsonFormat, false)
+	}
+
+	added, err := store.Add(entries...)
+	if err != nil {
+		return handleError(err, jsonFormat, false)
+	}
+
+	if jsonFormat {
+		jsonOutput, _ := format.FormatGenericJSON(map[string]interface{}{
+			"success": true,
+			"data":    map[string]interface{}{"jobs": added, "count": len(added)},
+		})
+		fmt.Println(jsonOutput)
+		return nil
+	}
+
+	for _, entry := range added {
+		fmt.Printf("✓ Queued %s (%s, priority %d)", entry.ID, entry.Type, entry.Priority)
+		if entry.NotBefore != nil {
+			fmt.Printf(", not before %s", entry.NotBefore.Format(time.RFC3339))
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// queueJobsFromFlags builds jobs from a manifest or from the type and option flags
+func queueJobsFromFlags(cmd *cobra.Command, args []string) ([]batch.BatchJob, error) {
+	if manifestPath, _ := cmd.Flags().GetString("manifest"); manifestPath != "" {
+		if len(args) > 0 {
+			return nil, fmt.Errorf("a job type cannot be combined with --manifest")
+		}
+
+		manifest, err := batch.ParseManifestFile(manifestPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse manifest: %w", err)
+		}
+		if err := batch.ApplyTemplates(manifest, lookupTemplate); err != nil {
+			return nil, fmt.Errorf("failed to apply templates: %w", err)
+		}
+
+		jobs := manifest.Jobs
+		for _, job := range jobs {
+			if job.HasDependencies() {
+				return nil, fmt.Errorf("job %s depends on other jobs, which the queue cannot schedule; use 'veo3 batch process' instead", job.ID)
+			}
+		}
+		if manifest.OutputDirectory != "" {
+			for i := range jobs {
+				jobs[i].Output = filepath.Join(manifest.OutputDirectory, filepath.Base(jobs[i].Output))
+			}
+		}
+		return jobs, nil
+	}
+
+	if len(args) == 0 {
+		return nil, fmt.Errorf("a job type (generate, animate, interpolate, extend) or --manifest is required")
+	}
+
+	job := batch.BatchJob{Type: args[0], Options: make(map[string]interface{})}
+	job.ID, _ = cmd.Flags().GetString("id")
+	job.Output, _ = cmd.Flags().GetString("output")
+
+	options, _ := cmd.Flags().GetStringArray("option")
+	for _, option := range options {
+		key, value, ok := strings.Cut(option, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid option %q (expected key=value)", option)
+		}
+		job.Options[key] = value
+	}
+	if prompt, _ := cmd.Flags().GetString("prompt"); prompt != "" {
+		job.Options["prompt"] = prompt
+	}
+	if references, ok := job.Options["reference_images"].(string); ok {
+		job.Options["reference_images"] = splitList(references)
+	}
+
+	return []batch.BatchJob{job}, nil
+}
+
+// resolveJobPaths makes the output and input file paths of a job absolute
+func resolveJobPaths(job *batch.BatchJob, baseDir string) {
+	absolute := func(path string) string {
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(baseDir, path)
+	}
+
+	job.Output = absolute(job.Output)
+	for _, key := range jobPathOptions {
+		if path := job.StringOption(key, ""); path != "" {
+			job.Options[key] = absolute(path)
+		}
+	}
+	if references := job.StringSliceOption("reference_images"); len(references) > 0 {
+		resolved := make([]string, len(references))
+		for i, path := range references {
+			resolved[i] = absolute(path)
+		}
+		job.Options["reference_images"] = resolved
+	}
+}
+
+// parseNotBefore parses an RFC 3339 timestamp, a local date and time, or a
+// local time of day meaning its next occurrence after now
+func parseNotBefore(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	if t, err := time.ParseInLocation("15:04", value, now.Location()); err == nil {
+		next := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid --not-before %q (use RFC 3339, \"YYYY-MM-DD HH:MM\" or \"HH:MM\")", value)
+}
+
+func runQueueList(cmd *cobra.Command, args []string) error {
+	jsonFormat := viper.GetBool("json")
+	statusFilter, _ := cmd.Flags().GetString("status")
+
+	store, err := getQueueStore()
+	if err != nil {
+		return handleError(err, jsonFormat, false)
+	}
+
+	entries, err := store.List()
+	if err != nil {
+		return handleError(err, jsonFormat, false)
+	}
+
+	if statusFilter != "" {
+		filtered := entries[:0]
+		for _, entry := range entries {
+			if strings.EqualFold(string(entry.Status), statusFilter) {
+				filtered = append(filtered, entry)
+			}
+		}
+		entries = filtered
+	}
+
+	if jsonFormat {
+		jsonOutput, _ := format.FormatGenericJSON(map[string]interface{}{
+			"success": true,
+			"data":    map[string]interface{}{"jobs": entries, "count": len(entries)},
+		})
+		fmt.Println(jsonOutput)
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("Queue is empty")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tTYPE\tSTATUS\tPRIORITY\tNOT BEFORE\tOUTPUT")
+	_, _ = fmt.Fprintln(w, "--\t----\t------\t--------\t----------\t------")
+	for _, entry := range entries {
+		notBefore := "-"
+		if entry.NotBefore != nil {
+			notBefore = entry.NotBefore.Local().Format("2006-01-02 15:04")
+		}
+		output := entry.Output
+		if entry.Status == queue.StatusFailed && entry.Error != "" {
+			output = "error: " + entry.Error
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n",
+			entry.ID, entry.Type, entry.Status, entry.Priority, notBefore, output)
+	}
+	return w.Flush()
+}
+
+func runQueueRemove(cmd *cobra.Command, args []string) error {
+	jsonFormat := viper.GetBool("json")
+	finished, _ := cmd.Flags().GetBool("finished")
+
+	if len(args) == 0 && !finished {
+		return handleError(fmt.Errorf("specify job IDs or --finished"), jsonFormat, false)
+	}
+
+	store, err := getQueueStore()
+	if err != nil {
+		return handleError(err, jsonFormat, false)
+	}
+
+	removed := 0
+	if len(args) > 0 {
+		if err := store.Remove(args...); err != nil {
+			return handleError(err, jsonFormat, false)
+		}
+		removed += len(args)
+	}
+	if finished {
+		pruned, err := store.Prune()
+		if err != nil {
+			return handleError(err, jsonFormat, false)
+		}
+		removed += pruned
+	}
+
+	if jsonFormat {
+		jsonOutput, _ := format.FormatGenericJSON(map[string]interface{}{
+			"success": true,
+			"data":    map[string]interface{}{"removed": removed},
+		})
+		fmt.Println(jsonOutput)
+		return nil
+	}
+
+	fmt.Printf("✓ Removed %d job(s)\n", removed)
+	return nil
+}
+
+func runQueueRun(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	manager := newConfigManager()
+	cfg, err := manager.Load()
+	if fatalConfigError(err) {
+		return err
+	}
+	if err != nil {
+		// Use defaults if config load fails
+		cfg = &config.Configuration{
+			DefaultModel:        config.DefaultModel,
+			DefaultResolution:   config.DefaultResolution,
+			DefaultAspectRatio:  config.DefaultAspectRatio,
+			DefaultDuration:     config.DefaultDuration,
+			OutputDirectory:     ".",
+			PollIntervalSeconds: config.DefaultPollInterval,
+			QueueConcurrency:    config.DefaultConcurrency,
+		}
+	}
+
+	concurrency := getIntWithDefault(cmd, "concurrency", cfg.QueueConcurrency)
+	exitWhenEmpty, _ := cmd.Flags().GetBool("exit-when-empty")
+	pollInterval, _ := cmd.Flags().GetDuration("poll-interval")
+	outputDir, _ := cmd.Flags().GetString("output-dir")
+
+	store, err := getQueueStore()
+	if err != nil {
+		return err
+	}
+
+	client, err := createVeo3Client(cfg)
+	if err != nil {
+		return err
+	}
+
+	executor := &RealJobExecutor{
+		client:    client,
+		cfg:       cfg,
+		outputDir: outputDir,
+		notifier:  newWebhookNotifier(cmd, cfg),
+		hooks:     hooks.NewRunner(cfg.PostDownloadHooks),
+	}
+
+	runner := queue.NewRunner(store, executor, concurrency)
+	runner.SetPollInterval(pollInterval)
+	runner.SetObserver(client.Observer())
+	runner.OnStart(func(entry queue.Entry) {
+		fmt.Printf("▶️  %s: started (%s)\n", entry.ID, entry.Type)
+	})
+	runner.OnFinish(func(entry queue.Entry, result *batch.JobResult) {
+		if result.Success {
+			fmt.Printf("✅ %s: %s (%.1fs)\n", entry.ID, result.Output, result.Duration.Seconds())
+		} else {
+			fmt.Printf("❌ %s: FAILED - %s\n", entry.ID, result.Error)
+		}
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Running queue %s (concurrency %d)\n", store.Path(), concurrency)
+	if err := runner.Run(ctx, exitWhenEmpty); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	fmt.Println("👋 Queue runner stopped")
+	return nil
+}
+
+// getQueueStore opens the job queue in the veo3 data directory
+func getQueueStore() (*queue.Store, error) {
+	dataDir, err := getDataDir()
+	if err != nil {
+		return nil, err
+	}
+	return queue.NewStore(dataDir), nil
+}
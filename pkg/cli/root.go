@@ -1,10 +1,12 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/jasongoecke/go-veo3/internal/logger"
+	"github.com/jasongoecke/go-veo3/pkg/config"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -30,14 +32,23 @@ extend existing videos, use reference images, and perform frame interpolation fr
 
 	cmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.config/veo3/config.yaml)")
 	cmd.PersistentFlags().String("api-key", "", "Google Gemini API key")
+	cmd.PersistentFlags().String("profile", "", "Configuration profile to use (default from VEO3_PROFILE or active_profile)")
 	cmd.PersistentFlags().Bool("json", false, "Output in JSON format")
 	cmd.PersistentFlags().Bool("verbose", false, "Enable debug logging")
 	cmd.PersistentFlags().Bool("quiet", false, "Suppress progress output")
+	cmd.PersistentFlags().String("log-format", "text", "Log format: text or json")
+	cmd.PersistentFlags().String("log-file", "", "Append logs to this file instead of stderr")
+	cmd.PersistentFlags().Bool("wire-debug", false, "Log API requests and responses (API keys redacted, base64 truncated)")
+	cmd.PersistentFlags().StringSlice("webhook", []string{}, "Webhook URL to notify when an operation finishes (repeatable)")
 
 	_ = viper.BindPFlag("api-key", cmd.PersistentFlags().Lookup("api-key"))
+	_ = viper.BindPFlag("profile", cmd.PersistentFlags().Lookup("profile"))
 	_ = viper.BindPFlag("json", cmd.PersistentFlags().Lookup("json"))
 	_ = viper.BindPFlag("verbose", cmd.PersistentFlags().Lookup("verbose"))
 	_ = viper.BindPFlag("quiet", cmd.PersistentFlags().Lookup("quiet"))
+	_ = viper.BindPFlag("log-format", cmd.PersistentFlags().Lookup("log-format"))
+	_ = viper.BindPFlag("log-file", cmd.PersistentFlags().Lookup("log-file"))
+	_ = viper.BindPFlag("wire-debug", cmd.PersistentFlags().Lookup("wire-debug"))
 
 	// Add subcommands
 	cmd.AddCommand(newGenerateCmd())
@@ -48,6 +59,8 @@ extend existing videos, use reference images, and perform frame interpolation fr
 	cmd.AddCommand(newModelsCmd())
 	cmd.AddCommand(newConfigCmd())
 	cmd.AddCommand(newBatchCmd())
+	cmd.AddCommand(newServeCmd())
+	cmd.AddCommand(newQueueCmd())
 	cmd.AddCommand(newTemplatesCmd())
 	cmd.AddCommand(newCompletionCmd())
 	cmd.AddCommand(newDocsCmd())
@@ -88,9 +101,24 @@ func initConfig() {
 }
 
 func initLogger() {
-	verbose := viper.GetBool("verbose")
+	verbose := viper.GetBool("verbose") || wireDebug()
 	quiet := viper.GetBool("quiet")
-	logger.Init(verbose, quiet)
+
+	format, err := logger.ParseFormat(viper.GetString("log-format"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := logger.Configure(logger.Options{
+		Verbose: verbose,
+		Quiet:   quiet,
+		Format:  format,
+		File:    viper.GetString("log-file"),
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
 	if verbose {
 		logger.Debug("Verbose logging enabled")
@@ -99,3 +127,23 @@ func initLogger() {
 		}
 	}
 }
+
+// wireDebug reports whether API traffic should be logged, via --wire-debug or VEO3_DEBUG
+func wireDebug() bool {
+	return viper.GetBool("wire-debug") || os.Getenv("VEO3_DEBUG") != ""
+}
+
+// fatalConfigError reports whether a configuration load error must stop a
+// command instead of falling back to the built-in defaults
+func fatalConfigError(err error) bool {
+	return errors.Is(err, config.ErrProfileNotFound) || errors.Is(err, config.ErrInvalidProjectConfig) ||
+		errors.Is(err, config.ErrInvalidConfig)
+}
+
+// newConfigManager returns a configuration manager for the --config file
+// that applies the profile selected with --profile or VEO3_PROFILE
+func newConfigManager() *config.Manager {
+	manager := config.NewManager(cfgFile)
+	manager.SetProfile(viper.GetString("profile"))
+	return manager
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/jasongoecke/go-veo3/pkg/config"
+	"github.com/jasongoecke/go-veo3/pkg/hooks"
+	"github.com/jasongoecke/go-veo3/pkg/metrics"
+	"github.com/jasongoecke/go-veo3/pkg/server"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+	"github.com/spf13/cobra"
+)
+
+// newServeCmd creates the serve command
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run a local REST API server",
+		Long: `Run a local HTTP JSON API for submitting and tracking video generations.
+
+The server exposes submit endpoints for generate, animate, interpolate and extend,
+operation status/list/cancel, video downloads and batch manifest submission.
+A background poller tracks submitted operations and downloads completed videos
+to the output directory, running post-download hooks and webhooks.
+
+Endpoints:
+  POST /v1/generate | /v1/animate | /v1/interpolate | /v1/extend
+  GET  /v1/operations[?status=RUNNING]
+  GET  /v1/operations/{name}
+  POST /v1/operations/{name}:cancel
+  GET  /v1/operations/{name}:download
+  POST /v1/batches  (YAML or JSON manifest)
+  GET  /v1/batches[/{id}]
+  GET  /healthz, /openapi.json
+  GET  /metrics, /debug/vars  (with --metrics)
+
+Image and video paths in requests and batch manifests are read from the
+input directory (--input, default the current directory); absolute paths and
+paths leaving it are rejected. Submitted batches run at most --max-concurrency
+jobs at once, whatever their manifest asks for.
+
+When --token (or VEO3_SERVER_TOKEN) is set, API requests must send
+"Authorization: Bearer <token>".`,
+		Example: `  # Serve on the default address
+  veo3 serve
+
+  # Require a bearer token and store videos in ./videos
+  veo3 serve --addr 0.0.0.0:8080 --token s3cret --output ./videos
+
+  # Submit a generation
+  curl -X POST localhost:8080/v1/generate -d '{"prompt": "A cat playing piano"}'`,
+		RunE: runServe,
+	}
+
+	cmd.Flags().String("addr", "127.0.0.1:8080", "Address to listen on")
+	cmd.Flags().String("token", "", "Bearer token required on API requests (or VEO3_SERVER_TOKEN)")
+	cmd.Flags().StringP("output", "o", "", "Directory for downloaded videos (default from config)")
+	cmd.Flags().String("input", ".", "Directory request image and video paths are read from")
+	cmd.Flags().Int("max-concurrency", config.DefaultConcurrency, "Most jobs of a submitted batch run at once")
+	cmd.Flags().Duration("poll-interval", 0, "Interval between status polls (default from config)")
+	cmd.Flags().Bool("metrics", false, "Serve Prometheus metrics at /metrics and expvar at /debug/vars")
+
+	return cmd
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	manager := newConfigManager()
+	cfg, err := manager.Load()
+	if fatalConfigError(err) {
+		return err
+	}
+	if err != nil {
+		// Use defaults if config load fails
+		cfg = &config.Configuration{
+			DefaultModel:        config.DefaultModel,
+			DefaultResolution:   config.DefaultResolution,
+			DefaultAspectRatio:  config.DefaultAspectRatio,
+			DefaultDuration:     config.DefaultDuration,
+			OutputDirectory:     ".",
+			PollIntervalSeconds: config.DefaultPollInterval,
+		}
+	}
+
+	addr, _ := cmd.Flags().GetString("addr")
+	token, _ := cmd.Flags().GetString("token")
+	if token == "" {
+		token = os.Getenv("VEO3_SERVER_TOKEN")
+	}
+	outputDir := getStringWithDefault(cmd, "output", cfg.OutputDirectory)
+	inputDir, _ := cmd.Flags().GetString("input")
+	maxConcurrency, _ := cmd.Flags().GetInt("max-concurrency")
+	pollInterval, _ := cmd.Flags().GetDuration("poll-interval")
+	if pollInterval <= 0 {
+		pollInterval = time.Duration(cfg.PollIntervalSeconds) * time.Second
+	}
+
+	var clientOpts []veo3.ClientOption
+	var prometheus *metrics.Prometheus
+	enableMetrics, _ := cmd.Flags().GetBool("metrics")
+	if enableMetrics {
+		prometheus = metrics.NewPrometheus()
+		clientOpts = append(clientOpts, veo3.WithObserver(veo3.MultiObserver(prometheus, metrics.NewExpvar("veo3"))))
+	}
+
+	client, err := createVeo3Client(cfg, clientOpts...)
+	if err != nil {
+		return err
+	}
+
+	notifier := newWebhookNotifier(cmd, cfg)
+	hookRunner := hooks.NewRunner(cfg.PostDownloadHooks)
+
+	opts := server.Options{
+		Token:          token,
+		OutputDir:      outputDir,
+		InputDir:       inputDir,
+		PollInterval:   pollInterval,
+		Defaults:       cfg,
+		Notifier:       notifier,
+		Hooks:          hookRunner,
+		DebugVars:      enableMetrics,
+		Templates:      lookupTemplate,
+		MaxConcurrency: maxConcurrency,
+		NewExecutor: func(manifest *batch.BatchManifest) batch.JobExecutor {
+			return &RealJobExecutor{
+				client:    client,
+				cfg:       cfg,
+				outputDir: manifest.OutputDirectory,
+				notifier:  notifier,
+				hooks:     hookRunner,
+			}
+		},
+	}
+	if prometheus != nil {
+		opts.Metrics = prometheus.Handler()
+	}
+	srv := server.New(client, opts)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("🚀 veo3 API listening on http://%s\n", addr)
+	if token == "" {
+		fmt.Println("⚠️  No --token set; the API is unauthenticated")
+	}
+
+	if err := srv.ListenAndServe(ctx, addr); err != nil {
+		return err
+	}
+
+	fmt.Println("👋 Server stopped")
+	return nil
+}
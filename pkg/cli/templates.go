@@ -10,6 +10,7 @@ import (
 	"text/tabwriter"
 
 	"github.com/jasongoecke/go-veo3/pkg/templates"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -28,6 +29,9 @@ func newTemplatesCmd() *cobra.Command {
 		newTemplatesDeleteCmd(),
 		newTemplatesExportCmd(),
 		newTemplatesImportCmd(),
+		newTemplatesHistoryCmd(),
+		newTemplatesDiffCmd(),
+		newTemplatesRollbackCmd(),
 	)
 
 	return cmd
@@ -37,6 +41,7 @@ func newTemplatesSaveCmd() *cobra.Command {
 	var (
 		description string
 		tags        []string
+		preset      templates.Preset
 	)
 
 	cmd := &cobra.Command{
@@ -44,8 +49,36 @@ func newTemplatesSaveCmd() *cobra.Command {
 		Short: "Save a prompt template",
 		Long: `Save a prompt template with variable placeholders.
 
-Variables are specified using {{variable_name}} syntax.
-Example: "A {{style}} image of {{subject}}"`,
+Variables are specified using {{variable_name}} syntax and can be
+followed by filters:
+
+  {{season | default "autumn"}}   value used when the variable is not set
+  {{subject | upper}}             upper, lower, title, capitalize, trim
+  {{styles | join " and "}}       join a comma-separated list variable
+
+Sections can be optional or repeated:
+
+  {{if mood}}, {{mood}} mood{{else}}, calm{{end}}
+  {{if not night}} in daylight{{end}}
+  {{range styles}} #{{.}}{{end}}
+
+Example: "A {{style | default \"cinematic\"}} shot of {{subject}}"
+
+A template can also carry a generation preset: the job type, model,
+resolution, aspect ratio, duration, negative prompt and images. Commands and
+batch jobs that use the template apply the preset unless they set a value
+explicitly. The prompt may be empty when a preset is given.`,
+		Example: `  # Prompt-only template
+  veo3 templates save sunset "A {{style}} sunset over {{place}}"
+
+  # Template with a full preset
+  veo3 templates save promo "{{product}} on a marble table" \
+    --model veo-3.1-generate-preview --resolution 1080p --duration 8 \
+    --negative-prompt "text, watermark"
+
+  # Animation preset with its input image
+  veo3 templates save product-spin "The {{product}} slowly rotates" \
+    --type animate --image ./assets/product.png`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
@@ -62,14 +95,20 @@ Example: "A {{style}} image of {{subject}}"`,
 				Description: description,
 				Tags:        tags,
 			}
+			if !preset.IsEmpty() {
+				if err := absolutePresetPaths(&preset); err != nil {
+					return err
+				}
+				template.Preset = &preset
+			}
 
 			if err := manager.Save(template); err != nil {
 				return fmt.Errorf("failed to save template: %w", err)
 			}
 
 			// Show template info
-			vars := template.Variables()
-			fmt.Printf("✓ Saved template '%s'\n", name)
+			vars := describeVariables(template)
+			fmt.Printf("✓ Saved template '%s' (version %d)\n", name, template.CurrentVersion())
 			if len(vars) > 0 {
 				fmt.Printf("  Variables: %s\n", strings.Join(vars, ", "))
 			}
@@ -79,6 +118,9 @@ Example: "A {{style}} image of {{subject}}"`,
 			if len(tags) > 0 {
 				fmt.Printf("  Tags: %s\n", strings.Join(tags, ", "))
 			}
+			if summary := template.Preset.Summary(); summary != "" {
+				fmt.Printf("  Preset: %s\n", summary)
+			}
 
 			return nil
 		},
@@ -86,6 +128,15 @@ Example: "A {{style}} image of {{subject}}"`,
 
 	cmd.Flags().StringVarP(&description, "description", "d", "", "Template description")
 	cmd.Flags().StringSliceVarP(&tags, "tags", "t", []string{}, "Template tags (comma-separated)")
+	cmd.Flags().StringVar(&preset.Type, "type", "", "Preset job type (generate, animate or interpolate; inferred from images)")
+	cmd.Flags().StringVar(&preset.Model, "model", "", "Preset model")
+	cmd.Flags().StringVar(&preset.Resolution, "resolution", "", "Preset resolution (720p or 1080p)")
+	cmd.Flags().StringVar(&preset.AspectRatio, "aspect-ratio", "", "Preset aspect ratio (16:9 or 9:16)")
+	cmd.Flags().IntVar(&preset.Duration, "duration", 0, "Preset duration in seconds")
+	cmd.Flags().StringVar(&preset.NegativePrompt, "negative-prompt", "", "Preset negative prompt")
+	cmd.Flags().StringVar(&preset.Image, "image", "", "Preset image to animate, or first frame to interpolate from")
+	cmd.Flags().StringVar(&preset.LastFrame, "last-frame", "", "Preset last frame to interpolate to")
+	cmd.Flags().StringSliceVar(&preset.ReferenceImages, "reference", []string{}, "Preset reference image paths (max 3)")
 
 	return cmd
 }
@@ -153,9 +204,9 @@ func newTemplatesGetCmd() *cobra.Command {
 	var outputFormat string
 
 	cmd := &cobra.Command{
-		Use:   "get <name>",
+		Use:   "get <name>[@version]",
 		Short: "Get a prompt template",
-		Long:  "Display details of a specific prompt template",
+		Long:  "Display details of a specific prompt template, or of one of its versions (name@N)",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
@@ -165,7 +216,7 @@ func newTemplatesGetCmd() *cobra.Command {
 				return err
 			}
 
-			template, err := manager.Get(name)
+			template, err := manager.Resolve(name)
 			if err != nil {
 				return err
 			}
@@ -271,16 +322,26 @@ func newTemplatesImportCmd() *cobra.Command {
 // Helper functions
 
 func getTemplateManager() (*templates.Manager, error) {
+	configDir, err := getDataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return templates.NewManager(configDir)
+}
+
+// getDataDir returns the directory holding templates and the job queue
+func getDataDir() (string, error) {
 	configDir := viper.GetString("config-dir")
 	if configDir == "" {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			return nil, fmt.Errorf("failed to get home directory: %w", err)
+			return "", fmt.Errorf("failed to get home directory: %w", err)
 		}
 		configDir = filepath.Join(homeDir, ".veo3")
 	}
 
-	return templates.NewManager(configDir)
+	return configDir, nil
 }
 
 func outputTemplatesTable(templates []*templates.Template) error {
@@ -317,9 +378,10 @@ func outputTemplatesTable(templates []*templates.Template) error {
 
 func outputTemplateDetails(template *templates.Template) error {
 	fmt.Printf("Name:        %s\n", template.Name)
+	fmt.Printf("Version:     %d\n", template.CurrentVersion())
 	fmt.Printf("Prompt:      %s\n", template.Prompt)
 
-	vars := template.Variables()
+	vars := describeVariables(template)
 	if len(vars) > 0 {
 		fmt.Printf("Variables:   %s\n", strings.Join(vars, ", "))
 	}
@@ -332,6 +394,10 @@ func outputTemplateDetails(template *templates.Template) error {
 		fmt.Printf("Tags:        %s\n", strings.Join(template.Tags, ", "))
 	}
 
+	if summary := template.Preset.Summary(); summary != "" {
+		fmt.Printf("Preset:      %s\n", summary)
+	}
+
 	fmt.Printf("Created:     %s\n", template.CreatedAt.Format("2006-01-02 15:04:05"))
 	fmt.Printf("Updated:     %s\n", template.UpdatedAt.Format("2006-01-02 15:04:05"))
 
@@ -354,3 +420,138 @@ func hasAnyTag(templateTags, filterTags []string) bool {
 	}
 	return false
 }
+
+// describeVariables lists a template's variables with how each is used, e.g.
+// "subject", "season (default \"autumn\")", "styles (optional, list)"
+func describeVariables(template *templates.Template) []string {
+	info, err := template.VariableInfo()
+	if err != nil {
+		return template.Variables()
+	}
+
+	vars := make([]string, 0, len(info))
+	for _, v := range info {
+		var notes []string
+		switch {
+		case v.HasDefault:
+			notes = append(notes, fmt.Sprintf("default %q", v.Default))
+		case !v.Required:
+			notes = append(notes, "optional")
+		}
+		if v.List {
+			notes = append(notes, "list")
+		}
+		if len(notes) == 0 {
+			vars = append(vars, v.Name)
+			continue
+		}
+		vars = append(vars, fmt.Sprintf("%s (%s)", v.Name, strings.Join(notes, ", ")))
+	}
+	return vars
+}
+
+// absolutePresetPaths makes a preset's image paths absolute so the template
+// works from any directory
+func absolutePresetPaths(preset *templates.Preset) error {
+	paths := []*string{&preset.Image, &preset.LastFrame}
+	for i := range preset.ReferenceImages {
+		paths = append(paths, &preset.ReferenceImages[i])
+	}
+	for _, path := range paths {
+		if *path == "" {
+			continue
+		}
+		abs, err := filepath.Abs(*path)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", *path, err)
+		}
+		*path = abs
+	}
+	return nil
+}
+
+// resolveTemplate loads the template named by --template (optionally pinned
+// to a version with name@N) and renders its prompt with --vars. It returns the
+// --prompt value and a nil template when --template is not set. An explicit
+// --prompt is kept.
+func resolveTemplate(cmd *cobra.Command, jobType string) (string, *templates.Template, error) {
+	prompt, _ := cmd.Flags().GetString("prompt")
+	templateRef, _ := cmd.Flags().GetString("template")
+	if templateRef == "" {
+		return prompt, nil, nil
+	}
+	templateVars, _ := cmd.Flags().GetStringToString("vars")
+
+	template, err := lookupTemplate(templateRef)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load template: %w", err)
+	}
+	if err := template.Preset.CheckType(jobType); err != nil {
+		return "", nil, fmt.Errorf("template '%s': %w", templateRef, err)
+	}
+
+	if !cmd.Flags().Changed("prompt") {
+		prompt, err = template.Render(templateVars)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to render template: %w", err)
+		}
+	}
+
+	if !viper.GetBool("json") {
+		fmt.Printf("📝 Using template '%s' (version %d)\n", template.Name, template.CurrentVersion())
+		if len(templateVars) > 0 {
+			fmt.Println("   Variables:")
+			for k, v := range templateVars {
+				fmt.Printf("     %s: %s\n", k, v)
+			}
+		}
+		if summary := template.Preset.Summary(); summary != "" {
+			fmt.Printf("   Preset: %s\n", summary)
+		}
+		fmt.Printf("   Rendered: %s\n\n", prompt)
+	}
+
+	return prompt, template, nil
+}
+
+// templatePreset returns the template's preset, or an empty preset when there
+// is no template or it has none
+func templatePreset(template *templates.Template) *templates.Preset {
+	if template == nil || template.Preset == nil {
+		return &templates.Preset{}
+	}
+	return template.Preset
+}
+
+// recordTemplate records the template and version a generation used in the
+// operation metadata and on the downloaded video. Either may be nil.
+func recordTemplate(template *templates.Template, operation *veo3.Operation, video *veo3.GeneratedVideo) {
+	if template == nil {
+		return
+	}
+	if operation != nil {
+		if operation.Metadata == nil {
+			operation.Metadata = make(map[string]interface{})
+		}
+		operation.Metadata["template"] = template.Name
+		operation.Metadata["template_version"] = template.CurrentVersion()
+	}
+	if video != nil {
+		video.Template = template.Ref()
+	}
+}
+
+// lookupTemplate returns a saved template by reference ("name" or "name@N")
+func lookupTemplate(ref string) (*templates.Template, error) {
+	manager, err := getTemplateManager()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template manager: %w", err)
+	}
+	return manager.Resolve(ref)
+}
+
+// addTemplateFlags adds the --template and --vars flags to a generation command
+func addTemplateFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("template", "t", "", "Use a saved template (prompt and preset) by name, optionally pinned as name@version")
+	cmd.Flags().StringToString("vars", map[string]string{}, "Template variables (key=value format, repeat for each variable; list values are comma-separated)")
+}
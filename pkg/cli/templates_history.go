@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jasongoecke/go-veo3/pkg/templates"
+	"github.com/spf13/cobra"
+)
+
+// newTemplatesHistoryCmd creates the 'templates history' command
+func newTemplatesHistoryCmd() *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "history <name>",
+		Short: "List the saved versions of a template",
+		Long: `List every saved version of a template, oldest first.
+
+Each save that changes a template's prompt, description, tags or preset
+creates a new version. Use a version with 'templates get name@N',
+'generate -t name@N', 'templates diff' or 'templates rollback'.`,
+		Example: `  veo3 templates history promo
+  veo3 templates history promo -o json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manager, err := getTemplateManager()
+			if err != nil {
+				return err
+			}
+
+			template, err := manager.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			versions := template.Versions()
+			if outputFormat == "json" {
+				return outputJSON(versions)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "VERSION\tSAVED\tPROMPT\tPRESET")
+			_, _ = fmt.Fprintln(w, "-------\t-----\t------\t------")
+			for _, v := range versions {
+				version := fmt.Sprintf("v%d", v.Version)
+				if v.Version == template.CurrentVersion() {
+					version += " *"
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", version, v.SavedAt.Format("2006-01-02 15:04:05"),
+					truncate(strings.ReplaceAll(v.Prompt, "\n", " "), 50), valueOr(v.Preset.Summary(), "-"))
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json)")
+
+	return cmd
+}
+
+// newTemplatesDiffCmd creates the 'templates diff' command
+func newTemplatesDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <name> <from-version> [to-version]",
+		Short: "Show the changes between two versions of a template",
+		Long: `Show what changed between two versions of a template, field by field.
+
+Versions are written as 3 or v3. Without a second version the current version
+is used.`,
+		Example: `  # Compare version 3 with version 5
+  veo3 templates diff promo v3 v5
+
+  # Compare version 3 with the current version
+  veo3 templates diff promo 3`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manager, err := getTemplateManager()
+			if err != nil {
+				return err
+			}
+
+			template, err := manager.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			fromVersion, err := templates.ParseVersion(args[1])
+			if err != nil {
+				return err
+			}
+			toVersion := template.CurrentVersion()
+			if len(args) == 3 {
+				if toVersion, err = templates.ParseVersion(args[2]); err != nil {
+					return err
+				}
+			}
+
+			from, err := template.At(fromVersion)
+			if err != nil {
+				return err
+			}
+			to, err := template.At(toVersion)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("--- %s@%d\n", template.Name, fromVersion)
+			fmt.Printf("+++ %s@%d\n", template.Name, toVersion)
+			changed := false
+			for _, line := range templates.Diff(from, to) {
+				changed = changed || line.Op != ' '
+				fmt.Println(line)
+			}
+			if !changed {
+				fmt.Println("(no changes)")
+			}
+			return nil
+		},
+	}
+}
+
+// newTemplatesRollbackCmd creates the 'templates rollback' command
+func newTemplatesRollbackCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rollback <name> <version>",
+		Short: "Restore an earlier version of a template",
+		Long: `Restore an earlier version of a template by saving its content as a new
+version. Later versions stay in the history, so a rollback can itself be
+undone.`,
+		Example: `  veo3 templates rollback promo v3`,
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			version, err := templates.ParseVersion(args[1])
+			if err != nil {
+				return err
+			}
+
+			manager, err := getTemplateManager()
+			if err != nil {
+				return err
+			}
+
+			template, err := manager.Rollback(args[0], version)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("✓ Restored template '%s' to version %d as version %d\n", template.Name, version, template.CurrentVersion())
+			return nil
+		},
+	}
+}
+
+// truncate shortens s to at most n characters
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
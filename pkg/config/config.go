@@ -1,34 +1,49 @@
 package config
 
-import (
-	"fmt"
-
-	"github.com/jasongoecke/go-veo3/pkg/veo3"
-)
-
 // Configuration User settings and preferences.
 type Configuration struct {
-	APIKey              string `yaml:"api_key,omitempty" json:"-"`
-	APIKeyEnv           string `yaml:"api_key_env,omitempty" json:"api_key_env,omitempty"`
-	DefaultModel        string `yaml:"default_model" json:"default_model"`
-	DefaultResolution   string `yaml:"default_resolution" json:"default_resolution"`
-	DefaultAspectRatio  string `yaml:"default_aspect_ratio" json:"default_aspect_ratio"`
-	DefaultDuration     int    `yaml:"default_duration" json:"default_duration"`
-	OutputDirectory     string `yaml:"output_directory" json:"output_directory"`
-	PollIntervalSeconds int    `yaml:"poll_interval_seconds" json:"poll_interval_seconds"`
-	ConfigVersion       string `yaml:"version" json:"version"`
+	APIKey              string       `yaml:"api_key,omitempty" json:"-" mapstructure:"api_key"`
+	APIKeyEnv           string       `yaml:"api_key_env,omitempty" json:"api_key_env,omitempty" mapstructure:"api_key_env"`
+	APIKeyCommand       string       `yaml:"api_key_command,omitempty" json:"api_key_command,omitempty" mapstructure:"api_key_command"`
+	BaseURL             string       `yaml:"base_url,omitempty" json:"base_url,omitempty" mapstructure:"base_url"`
+	DefaultModel        string       `yaml:"default_model" json:"default_model" mapstructure:"default_model"`
+	DefaultResolution   string       `yaml:"default_resolution" json:"default_resolution" mapstructure:"default_resolution"`
+	DefaultAspectRatio  string       `yaml:"default_aspect_ratio" json:"default_aspect_ratio" mapstructure:"default_aspect_ratio"`
+	DefaultDuration     int          `yaml:"default_duration" json:"default_duration" mapstructure:"default_duration"`
+	OutputDirectory     string       `yaml:"output_directory" json:"output_directory" mapstructure:"output_directory"`
+	PollIntervalSeconds int          `yaml:"poll_interval_seconds" json:"poll_interval_seconds" mapstructure:"poll_interval_seconds"`
+	Webhooks            []string     `yaml:"webhooks,omitempty" json:"webhooks,omitempty" mapstructure:"webhooks"`
+	WebhookSecret       string       `yaml:"webhook_secret,omitempty" json:"-" mapstructure:"webhook_secret"`
+	PostDownloadHooks   []HookConfig `yaml:"post_download_hooks,omitempty" json:"post_download_hooks,omitempty" mapstructure:"post_download_hooks"`
+	QueueConcurrency    int          `yaml:"queue_concurrency,omitempty" json:"queue_concurrency,omitempty" mapstructure:"queue_concurrency"`
+	ConfigVersion       string       `yaml:"version" json:"version" mapstructure:"version"`
+
+	ActiveProfile string             `yaml:"active_profile,omitempty" json:"active_profile,omitempty" mapstructure:"active_profile"`
+	Profiles      map[string]Profile `yaml:"profiles,omitempty" json:"profiles,omitempty" mapstructure:"profiles"`
+	// Profile is the name of the profile applied by Load, if any
+	Profile string `yaml:"-" json:"profile,omitempty" mapstructure:"-"`
 }
 
-// Validate checks if the configuration values are valid
-func (c *Configuration) Validate() error {
-	// Validate default model exists in registry
-	if c.DefaultModel != "" {
-		if _, exists := veo3.GetModel(c.DefaultModel); !exists {
-			return fmt.Errorf("invalid default_model '%s': model not found in registry. Use 'veo3 models list' to see available models", c.DefaultModel)
-		}
-	}
+// HookFailurePolicy controls what happens when a post-download hook fails
+type HookFailurePolicy string
 
-	// Additional validation can be added here for other fields
+const (
+	// HookFail stops the pipeline and fails the command or batch job (default)
+	HookFail HookFailurePolicy = "fail"
+	// HookStop skips the remaining hooks without failing
+	HookStop HookFailurePolicy = "stop"
+	// HookContinue records the failure and runs the next hook
+	HookContinue HookFailurePolicy = "continue"
+)
 
-	return nil
+// HookConfig defines an external command run after a video is downloaded.
+// Args and Env values may reference {{file_path}}, {{prompt}}, {{model}},
+// {{operation_id}} and the other GeneratedVideo fields.
+type HookConfig struct {
+	Name           string            `yaml:"name,omitempty" json:"name,omitempty" mapstructure:"name"`
+	Command        string            `yaml:"command" json:"command" mapstructure:"command"`
+	Args           []string          `yaml:"args,omitempty" json:"args,omitempty" mapstructure:"args"`
+	Env            map[string]string `yaml:"env,omitempty" json:"env,omitempty" mapstructure:"env"`
+	TimeoutSeconds int               `yaml:"timeout_seconds,omitempty" json:"timeout_seconds,omitempty" mapstructure:"timeout_seconds"`
+	OnFailure      string            `yaml:"on_failure,omitempty" json:"on_failure,omitempty" mapstructure:"on_failure"`
 }
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,6 +53,74 @@ func TestConfiguration_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid default_model",
 		},
+		{
+			name: "valid post-download hooks",
+			config: Configuration{
+				PostDownloadHooks: []HookConfig{
+					{Name: "transcode", Command: "ffmpeg", Args: []string{"-i", "{{file_path}}"}, TimeoutSeconds: 60},
+					{Command: "notify", OnFailure: "continue"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "hook without command",
+			config: Configuration{
+				PostDownloadHooks: []HookConfig{{Name: "empty"}},
+			},
+			wantErr: true,
+			errMsg:  "command is required",
+		},
+		{
+			name: "hook with invalid failure policy",
+			config: Configuration{
+				PostDownloadHooks: []HookConfig{{Command: "true", OnFailure: "retry"}},
+			},
+			wantErr: true,
+			errMsg:  "on_failure must be fail, stop, or continue",
+		},
+		{
+			name:    "negative queue concurrency",
+			config:  Configuration{QueueConcurrency: -1},
+			wantErr: true,
+			errMsg:  "invalid queue_concurrency",
+		},
+		{
+			name:    "resolution unsupported by model",
+			config:  Configuration{DefaultModel: "veo-2.0-generate-001", DefaultResolution: "1080p"},
+			wantErr: true,
+			errMsg:  "invalid default_resolution '1080p'",
+		},
+		{
+			name:    "unknown aspect ratio",
+			config:  Configuration{DefaultAspectRatio: "4:3"},
+			wantErr: true,
+			errMsg:  "invalid default_aspect_ratio",
+		},
+		{
+			name:    "duration unsupported by default model",
+			config:  Configuration{DefaultDuration: 5},
+			wantErr: true,
+			errMsg:  "invalid default_duration 5",
+		},
+		{
+			name:    "poll interval too long",
+			config:  Configuration{PollIntervalSeconds: 3600},
+			wantErr: true,
+			errMsg:  "must be between 1 and 300 seconds",
+		},
+		{
+			name:    "base url without scheme",
+			config:  Configuration{BaseURL: "localhost:8089"},
+			wantErr: true,
+			errMsg:  "invalid base_url",
+		},
+		{
+			name:    "unsupported version",
+			config:  Configuration{ConfigVersion: "9.0"},
+			wantErr: true,
+			errMsg:  "unsupported config version",
+		},
 	}
 
 	for _, tt := range tests {
@@ -68,6 +138,61 @@ func TestConfiguration_Validate(t *testing.T) {
 	}
 }
 
+func TestConfiguration_Validate_ReportsEveryProblem(t *testing.T) {
+	cfg := Configuration{DefaultModel: "veo-2.0-generate-001", DefaultResolution: "1080p", DefaultDuration: 4, PollIntervalSeconds: -1}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	joined, ok := err.(interface{ Unwrap() []error })
+	require.True(t, ok)
+	assert.Len(t, joined.Unwrap(), 3)
+}
+
+func TestConfiguration_Validate_OutputDirectory(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(file, nil, 0600))
+
+	assert.NoError(t, (&Configuration{OutputDirectory: dir}).Validate())
+	assert.NoError(t, (&Configuration{OutputDirectory: filepath.Join(dir, "new", "nested")}).Validate())
+
+	err := (&Configuration{OutputDirectory: file}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a directory")
+
+	err = (&Configuration{OutputDirectory: filepath.Join(file, "videos")}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not a directory")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "validation should not write to the directory")
+}
+
+func TestConfiguration_CheckOutputDirectories(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &Configuration{
+		OutputDirectory: dir,
+		Profiles:        map[string]Profile{"team": {OutputDirectory: filepath.Join(dir, "missing")}},
+	}
+	assert.NoError(t, cfg.CheckOutputDirectories())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "write check should clean up after itself")
+
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	readOnly := filepath.Join(dir, "read-only")
+	require.NoError(t, os.Mkdir(readOnly, 0500))
+	cfg.Profiles["team"] = Profile{OutputDirectory: readOnly}
+	err = cfg.CheckOutputDirectories()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid profiles.team.output_directory")
+	assert.Contains(t, err.Error(), "not writable")
+}
+
 func TestConfiguration_Fields(t *testing.T) {
 	config := Configuration{
 		APIKey:              "test-api-key",
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIKeyCommandTimeout bounds how long a credential helper may run, leaving
+// time for helpers that prompt to unlock a password manager
+const APIKeyCommandTimeout = 2 * time.Minute
+
+var (
+	apiKeyCacheMu sync.Mutex
+	apiKeyCache   = map[string]string{}
+)
+
+// ResolveAPIKey returns the API key from the VEO3_API_KEY or GEMINI_API_KEY
+// environment variables, the variable named by api_key_env, the
+// api_key_command credential helper, or api_key, in that order. When the
+// applied profile sets its own key source, the generic variables are skipped
+// so that a selected profile never bills another key.
+func (c *Configuration) ResolveAPIKey(ctx context.Context) (string, error) {
+	if !c.ProfileSetsAPIKey() {
+		// Check environment variables directly (viper's flag binding can override env vars)
+		for _, env := range []string{"VEO3_API_KEY", "GEMINI_API_KEY"} {
+			if key := os.Getenv(env); key != "" {
+				return key, nil
+			}
+		}
+	}
+	if c.APIKeyEnv != "" {
+		if key := os.Getenv(c.APIKeyEnv); key != "" {
+			return key, nil
+		}
+	}
+
+	if c.APIKeyCommand != "" {
+		return RunAPIKeyCommand(ctx, c.APIKeyCommand)
+	}
+
+	return c.APIKey, nil
+}
+
+// RunAPIKeyCommand runs a credential helper through the shell and returns the
+// first line it prints, like git's credential helpers. The helper inherits
+// stdin and stderr so it can prompt. Keys are cached per command for the
+// lifetime of the process, and errors never include the helper's output.
+func RunAPIKeyCommand(ctx context.Context, command string) (string, error) {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return "", fmt.Errorf("api_key_command is empty")
+	}
+
+	apiKeyCacheMu.Lock()
+	defer apiKeyCacheMu.Unlock()
+	if key, ok := apiKeyCache[command]; ok {
+		return key, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, APIKeyCommandTimeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := shellCommand(ctx, command)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		switch {
+		case ctx.Err() == context.DeadlineExceeded:
+			return "", fmt.Errorf("api_key_command timed out after %s", APIKeyCommandTimeout)
+		case errors.As(err, &exitErr):
+			return "", fmt.Errorf("api_key_command exited with code %d", exitErr.ExitCode())
+		default:
+			return "", fmt.Errorf("api_key_command failed: %w", err)
+		}
+	}
+
+	key, _, _ := strings.Cut(stdout.String(), "\n")
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", fmt.Errorf("api_key_command printed no API key")
+	}
+
+	apiKeyCache[command] = key
+	return key, nil
+}
+
+// shellCommand runs command through the platform shell
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command) // #nosec G204 -- The credential helper comes from the user's own configuration
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command) // #nosec G204 -- The credential helper comes from the user's own configuration
+}
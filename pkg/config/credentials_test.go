@@ -0,0 +1,57 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunAPIKeyCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper tests use sh")
+	}
+
+	t.Run("returns first line trimmed", func(t *testing.T) {
+		key, err := RunAPIKeyCommand(context.Background(), `printf '  AIza-first \nsecond\n'`)
+		require.NoError(t, err)
+		assert.Equal(t, "AIza-first", key)
+	})
+
+	t.Run("caches for the process lifetime", func(t *testing.T) {
+		counter := filepath.Join(t.TempDir(), "runs")
+		command := "echo run >> " + counter + " && echo AIza-cached"
+
+		for i := 0; i < 3; i++ {
+			key, err := RunAPIKeyCommand(context.Background(), command)
+			require.NoError(t, err)
+			assert.Equal(t, "AIza-cached", key)
+		}
+
+		runs, err := os.ReadFile(counter)
+		require.NoError(t, err)
+		assert.Equal(t, "run\n", string(runs), "helper should run once")
+	})
+
+	t.Run("failure does not reveal output", func(t *testing.T) {
+		_, err := RunAPIKeyCommand(context.Background(), "echo AIza-leaked; exit 3")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exited with code 3")
+		assert.NotContains(t, err.Error(), "AIza-leaked")
+	})
+
+	t.Run("empty output", func(t *testing.T) {
+		_, err := RunAPIKeyCommand(context.Background(), "true")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no API key")
+	})
+
+	t.Run("empty command", func(t *testing.T) {
+		_, err := RunAPIKeyCommand(context.Background(), "  ")
+		assert.Error(t, err)
+	})
+}
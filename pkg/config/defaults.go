@@ -5,9 +5,9 @@ const (
 	DefaultResolution    = "720p"
 	DefaultAspectRatio   = "16:9"
 	DefaultDuration      = 8
-	DefaultPollInterval  = 10 // seconds
-	DefaultConcurrency   = 3  // batch jobs
-	DefaultConfigVersion = "1.0"
+	DefaultPollInterval  = 10    // seconds
+	DefaultConcurrency   = 3     // batch jobs
+	DefaultConfigVersion = "2.0" // config file schema
 
 	MaxImageSize       = 20 * 1024 * 1024 // 20MB
 	MaxVideoLength     = 141              // seconds
@@ -14,7 +14,7 @@ func TestConstants(t *testing.T) {
 	assert.Equal(t, 8, DefaultDuration)
 	assert.Equal(t, 10, DefaultPollInterval)
 	assert.Equal(t, 3, DefaultConcurrency)
-	assert.Equal(t, "1.0", DefaultConfigVersion)
+	assert.Equal(t, "2.0", DefaultConfigVersion)
 }
 
 func TestConstraintConstants(t *testing.T) {
@@ -0,0 +1,262 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigFile is the name of the project-local config file searched for
+// from the working directory upwards
+const ProjectConfigFile = ".veo3.yaml"
+
+// ErrInvalidProjectConfig is returned when a project file cannot be used
+var ErrInvalidProjectConfig = errors.New("invalid project config")
+
+// projectKeys are the settings a project file may set. Credentials, endpoints,
+// webhooks, hooks and profile definitions stay in the user config so that
+// running veo3 inside a cloned repository cannot run commands or send the API
+// key elsewhere.
+var projectKeys = []string{
+	"version", "default_model", "default_resolution", "default_aspect_ratio",
+	"default_duration", "output_directory", "poll_interval_seconds",
+	"queue_concurrency", "active_profile",
+}
+
+// Configuration layers, lowest precedence first. Command-line flags are
+// applied by each command on top of all of them.
+const (
+	OriginDefault = "default"
+	OriginUser    = "user"
+	OriginProject = "project"
+	OriginProfile = "profile"
+	OriginEnv     = "env"
+)
+
+// Origin records which layer supplied an effective configuration value
+type Origin struct {
+	Layer string `json:"layer"`
+	// Source is the file, profile name or environment variable of the layer
+	Source string `json:"source,omitempty"`
+}
+
+// String formats the origin for display, e.g. "project (/repo/.veo3.yaml)"
+func (o Origin) String() string {
+	if o.Source == "" {
+		return o.Layer
+	}
+	return fmt.Sprintf("%s (%s)", o.Layer, o.Source)
+}
+
+// FindProjectConfig returns the nearest .veo3.yaml in dir or one of its
+// parents, or "" when there is none
+func FindProjectConfig(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve working directory: %w", err)
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectConfigFile)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// readProjectConfig reads a project file, rejecting settings that only the
+// user config may hold. A relative output_directory is resolved against the
+// directory containing the file.
+func readProjectConfig(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- Path is found by FindProjectConfig
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProjectConfig, err)
+	}
+
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidProjectConfig, path, err)
+	}
+
+	for key := range values {
+		if !contains(projectKeys, key) {
+			return nil, fmt.Errorf("%w: %s: '%s' can only be set in the user config (allowed: %s)",
+				ErrInvalidProjectConfig, path, key, strings.Join(projectKeys[1:], ", "))
+		}
+	}
+
+	if dir, ok := values["output_directory"].(string); ok && dir != "" && !filepath.IsAbs(dir) {
+		values["output_directory"] = filepath.Join(filepath.Dir(path), dir)
+	}
+	return values, nil
+}
+
+// fileKeys returns the top-level keys set in a YAML file, or nil if it cannot be read
+func fileKeys(path string) []string {
+	data, err := os.ReadFile(path) // #nosec G304 -- Path is the user's config file
+	if err != nil {
+		return nil
+	}
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// overrides returns the settings a profile changes, keyed like the top-level
+// configuration. Setting any key source clears the other two.
+func (p Profile) overrides() map[string]interface{} {
+	values := make(map[string]interface{})
+	if p.setsAPIKey() {
+		values["api_key"] = p.APIKey
+		values["api_key_env"] = p.APIKeyEnv
+		values["api_key_command"] = p.APIKeyCommand
+	}
+	setString := func(key, value string) {
+		if value != "" {
+			values[key] = value
+		}
+	}
+	setInt := func(key string, value int) {
+		if value != 0 {
+			values[key] = value
+		}
+	}
+	setString("base_url", p.BaseURL)
+	setString("default_model", p.DefaultModel)
+	setString("default_resolution", p.DefaultResolution)
+	setString("default_aspect_ratio", p.DefaultAspectRatio)
+	setInt("default_duration", p.DefaultDuration)
+	setString("output_directory", p.OutputDirectory)
+	setInt("poll_interval_seconds", p.PollIntervalSeconds)
+	setInt("queue_concurrency", p.QueueConcurrency)
+	return values
+}
+
+// apiKeySources are the settings an API key can come from
+var apiKeySources = map[string]bool{"api_key": true, "api_key_env": true, "api_key_command": true}
+
+// mergeLayers merges the project file and the selected profile over the user
+// config already read into viper and records where each value comes from.
+// Environment variables keep precedence through viper's AutomaticEnv, except
+// over the key source of a profile that sets one.
+func (m *Manager) mergeLayers() (string, error) {
+	origins := make(map[string]Origin)
+	userPath := m.getConfigPath()
+	for _, key := range fileKeys(userPath) {
+		origins[key] = Origin{Layer: OriginUser, Source: userPath}
+	}
+
+	projectPath, err := m.projectConfigPath()
+	if err != nil {
+		return "", err
+	}
+	if projectPath != "" {
+		values, err := readProjectConfig(projectPath)
+		if err != nil {
+			return "", err
+		}
+		if err := viper.MergeConfigMap(values); err != nil {
+			return "", fmt.Errorf("failed to merge project config: %w", err)
+		}
+		for key := range values {
+			origins[key] = Origin{Layer: OriginProject, Source: projectPath}
+		}
+	}
+	m.projectPath = projectPath
+
+	profileKey := false
+	profile := m.profile
+	if profile == "" {
+		profile = os.Getenv("VEO3_PROFILE")
+	}
+	if profile == "" {
+		profile = viper.GetString("active_profile")
+	}
+	if profile != "" {
+		var layered Configuration
+		if err := viper.UnmarshalKey("profiles", &layered.Profiles); err != nil {
+			return "", fmt.Errorf("failed to read profiles: %w", err)
+		}
+		p, ok := layered.Profiles[profile]
+		if !ok {
+			return "", layered.ApplyProfile(profile)
+		}
+		values := p.overrides()
+		if err := viper.MergeConfigMap(values); err != nil {
+			return "", fmt.Errorf("failed to apply profile: %w", err)
+		}
+		for key := range values {
+			origins[key] = Origin{Layer: OriginProfile, Source: profile}
+		}
+		profileKey = p.setsAPIKey()
+	}
+
+	for _, key := range schemaKeys {
+		if profileKey && apiKeySources[key] {
+			continue
+		}
+		env := "VEO3_" + strings.ToUpper(key)
+		if os.Getenv(env) != "" {
+			origins[key] = Origin{Layer: OriginEnv, Source: env}
+		}
+	}
+
+	m.origins = origins
+	return profile, nil
+}
+
+// projectConfigPath finds the project file for the manager's working directory
+func (m *Manager) projectConfigPath() (string, error) {
+	dir := m.workDir
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", nil
+		}
+		dir = wd
+	}
+	return FindProjectConfig(dir)
+}
+
+// SetWorkingDir sets the directory the project config search starts from
+// (default: the current working directory)
+func (m *Manager) SetWorkingDir(dir string) {
+	m.workDir = dir
+}
+
+// ProjectConfigFile returns the project file merged by the last Load, if any
+func (m *Manager) ProjectConfigFile() string {
+	return m.projectPath
+}
+
+// Origins returns the layer each top-level setting came from in the last
+// Load. Settings no layer sets are reported as OriginDefault.
+func (m *Manager) Origins() map[string]Origin {
+	origins := make(map[string]Origin, len(schemaKeys))
+	for _, key := range schemaKeys {
+		origin, ok := m.origins[key]
+		if !ok {
+			origin = Origin{Layer: OriginDefault}
+		}
+		origins[key] = origin
+	}
+	return origins
+}
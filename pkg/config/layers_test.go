@@ -0,0 +1,122 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// projectLayout creates a user config and a repository with a project file,
+// returning the user config path and a subdirectory of the repository
+func projectLayout(t *testing.T, userConfig, projectConfig string) (string, string) {
+	t.Helper()
+	root := t.TempDir()
+
+	userPath := filepath.Join(root, "user", "config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(userPath), 0750))
+	if userConfig != "" {
+		require.NoError(t, os.WriteFile(userPath, []byte(userConfig), 0600))
+	}
+
+	repo := filepath.Join(root, "repo")
+	subdir := filepath.Join(repo, "scenes", "intro")
+	require.NoError(t, os.MkdirAll(subdir, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ProjectConfigFile), []byte(projectConfig), 0600))
+
+	return userPath, subdir
+}
+
+func TestFindProjectConfig(t *testing.T) {
+	_, subdir := projectLayout(t, "", "default_resolution: 1080p\n")
+
+	path, err := FindProjectConfig(subdir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(filepath.Dir(filepath.Dir(subdir)), ProjectConfigFile), path)
+
+	path, err = FindProjectConfig(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, path)
+}
+
+func TestManager_Load_ProjectLayer(t *testing.T) {
+	userPath, subdir := projectLayout(t, `
+api_key: user-key
+default_model: veo-3.1-generate-preview
+default_resolution: 720p
+output_directory: /tmp
+active_profile: fast
+profiles:
+  fast:
+    default_model: veo-3.1-fast-generate-preview
+`, `
+default_resolution: 1080p
+default_duration: 6
+output_directory: ./renders
+`)
+	t.Setenv("VEO3_DEFAULT_DURATION", "4")
+
+	viper.Reset()
+	manager := NewManager(userPath)
+	manager.SetWorkingDir(subdir)
+	cfg, err := manager.Load()
+	require.NoError(t, err)
+
+	repo := filepath.Dir(filepath.Dir(subdir))
+	assert.Equal(t, "user-key", cfg.APIKey)
+	assert.Equal(t, "veo-3.1-fast-generate-preview", cfg.DefaultModel)
+	assert.Equal(t, "1080p", cfg.DefaultResolution)
+	assert.Equal(t, 4, cfg.DefaultDuration, "environment overrides the project file")
+	assert.Equal(t, filepath.Join(repo, "renders"), cfg.OutputDirectory, "relative to the project file")
+	assert.Equal(t, filepath.Join(repo, ProjectConfigFile), manager.ProjectConfigFile())
+
+	origins := manager.Origins()
+	assert.Equal(t, Origin{Layer: OriginUser, Source: userPath}, origins["api_key"])
+	assert.Equal(t, Origin{Layer: OriginProfile, Source: "fast"}, origins["default_model"])
+	assert.Equal(t, OriginProject, origins["default_resolution"].Layer)
+	assert.Equal(t, Origin{Layer: OriginEnv, Source: "VEO3_DEFAULT_DURATION"}, origins["default_duration"])
+	assert.Equal(t, Origin{Layer: OriginDefault}, origins["default_aspect_ratio"])
+
+	t.Run("LoadBase ignores the project file", func(t *testing.T) {
+		viper.Reset()
+		base, err := manager.LoadBase()
+		require.NoError(t, err)
+		assert.Equal(t, "720p", base.DefaultResolution)
+		assert.Equal(t, "/tmp", base.OutputDirectory)
+	})
+}
+
+func TestManager_Load_ProjectWithoutUserConfig(t *testing.T) {
+	userPath, subdir := projectLayout(t, "", "default_resolution: 1080p\n")
+
+	viper.Reset()
+	manager := NewManager(userPath)
+	manager.SetWorkingDir(subdir)
+	cfg, err := manager.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "1080p", cfg.DefaultResolution)
+	assert.Equal(t, DefaultModel, cfg.DefaultModel)
+
+	viper.Reset()
+	manager.SetWorkingDir(t.TempDir())
+	_, err = manager.Load()
+	assert.ErrorIs(t, err, os.ErrNotExist, "without a project file a missing user config is still an error")
+}
+
+func TestManager_Load_ProjectRestrictedKeys(t *testing.T) {
+	for _, key := range []string{"api_key_command", "base_url", "post_download_hooks", "profiles"} {
+		t.Run(key, func(t *testing.T) {
+			userPath, subdir := projectLayout(t, "default_model: veo-3.1-generate-preview\n", key+": x\n")
+
+			viper.Reset()
+			manager := NewManager(userPath)
+			manager.SetWorkingDir(subdir)
+			_, err := manager.Load()
+			require.ErrorIs(t, err, ErrInvalidProjectConfig)
+			assert.Contains(t, err.Error(), "'"+key+"' can only be set in the user config")
+		})
+	}
+}
@@ -1,16 +1,29 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
+// ErrInvalidConfig is returned by Load when a setting fails validation
+var ErrInvalidConfig = errors.New("invalid configuration")
+
 // Manager manages configuration loading and access
 type Manager struct {
 	configPath string
+	profile    string
+	workDir    string
+
+	// Set by Load
+	projectPath string
+	origins     map[string]Origin
 }
 
 // NewManager creates a new configuration manager
@@ -20,8 +33,50 @@ func NewManager(configPath string) *Manager {
 	}
 }
 
-// Load reads the configuration from file or environment
+// SetProfile selects the profile applied by Load, taking precedence over
+// VEO3_PROFILE and the configured active_profile
+func (m *Manager) SetProfile(name string) {
+	m.profile = name
+}
+
+// Load reads the configuration in layers: built-in defaults, the user
+// config, the nearest project .veo3.yaml, the selected profile and finally
+// VEO3_* environment variables
 func (m *Manager) Load() (*Configuration, error) {
+	cfg, err := m.read(true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Manual bind for API Key Env if needed, or rely on automatic env. A
+	// profile's own key source is not replaced by the generic variables.
+	if cfg.APIKey == "" && os.Getenv("GEMINI_API_KEY") != "" && !cfg.ProfileSetsAPIKey() {
+		cfg.APIKey = os.Getenv("GEMINI_API_KEY")
+		m.origins["api_key"] = Origin{Layer: OriginEnv, Source: "GEMINI_API_KEY"}
+	}
+	if cfg.WebhookSecret == "" && os.Getenv("VEO3_WEBHOOK_SECRET") != "" {
+		cfg.WebhookSecret = os.Getenv("VEO3_WEBHOOK_SECRET")
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	return cfg, nil
+}
+
+// LoadBase reads the configuration file without applying a profile or
+// environment overrides, for commands that edit and save it. The result is
+// not validated so that an invalid value can still be corrected; callers
+// validate before saving.
+func (m *Manager) LoadBase() (*Configuration, error) {
+	return m.read(false)
+}
+
+// read loads the configuration file with defaults applied, and with layered
+// also the project file and the selected profile
+func (m *Manager) read(layered bool) (*Configuration, error) {
 	if m.configPath != "" {
 		viper.SetConfigFile(m.configPath)
 	} else {
@@ -47,13 +102,31 @@ func (m *Manager) Load() (*Configuration, error) {
 	viper.SetDefault("output_directory", ".")
 
 	// If config file exists, read it
+	var missingErr error
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
+			if !layered || !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("failed to read config file: %w", err)
+			}
+			// A project file can stand in for a missing user config
+			missingErr = fmt.Errorf("failed to read config file: %w", err)
+			viper.SetConfigType("yaml")
+			_ = viper.ReadConfig(bytes.NewReader(nil))
 		}
 		// Config file not found is okay, use defaults
 	}
 
+	var profile string
+	if layered {
+		var err error
+		if profile, err = m.mergeLayers(); err != nil {
+			return nil, err
+		}
+		if missingErr != nil && m.projectPath == "" {
+			return nil, missingErr
+		}
+	}
+
 	var cfg Configuration
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
@@ -78,15 +151,14 @@ func (m *Manager) Load() (*Configuration, error) {
 	if cfg.OutputDirectory == "" {
 		cfg.OutputDirectory = "."
 	}
-
-	// Manual bind for API Key Env if needed, or rely on automatic env
-	if cfg.APIKey == "" {
-		cfg.APIKey = os.Getenv("GEMINI_API_KEY")
+	if cfg.QueueConcurrency == 0 {
+		cfg.QueueConcurrency = DefaultConcurrency
 	}
-
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+	cfg.Profile = profile
+	if cfg.ProfileSetsAPIKey() {
+		// Undo VEO3_API_KEY and the like, which viper applies over the profile
+		p := cfg.Profiles[profile]
+		cfg.APIKey, cfg.APIKeyEnv, cfg.APIKeyCommand = p.APIKey, p.APIKeyEnv, p.APIKeyCommand
 	}
 
 	return &cfg, nil
@@ -102,15 +174,53 @@ func (m *Manager) Save(cfg *Configuration) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
+	// The file is always written in the current schema
+	saved := *cfg
+	saved.ConfigVersion = DefaultConfigVersion
+
+	// Write the struct directly rather than through viper, whose override map
+	// would keep a deleted profile in the file
+	data, err := yaml.Marshal(&saved)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := writeFileAtomic(configPath, data); err != nil {
+		return err
+	}
+
+	// Saved values take effect for the rest of the process. Profiles are left
+	// out so that a deleted profile is not kept alive by viper's override map.
 	viper.Set("api_key", cfg.APIKey)
+	viper.Set("api_key_env", cfg.APIKeyEnv)
+	viper.Set("api_key_command", cfg.APIKeyCommand)
+	viper.Set("base_url", cfg.BaseURL)
 	viper.Set("default_model", cfg.DefaultModel)
 	viper.Set("default_resolution", cfg.DefaultResolution)
 	viper.Set("default_aspect_ratio", cfg.DefaultAspectRatio)
 	viper.Set("default_duration", cfg.DefaultDuration)
 	viper.Set("poll_interval_seconds", cfg.PollIntervalSeconds)
 	viper.Set("output_directory", cfg.OutputDirectory)
+	viper.Set("webhooks", cfg.Webhooks)
+	viper.Set("webhook_secret", cfg.WebhookSecret)
+	viper.Set("post_download_hooks", cfg.PostDownloadHooks)
+	viper.Set("queue_concurrency", cfg.QueueConcurrency)
+	viper.Set("active_profile", cfg.ActiveProfile)
+	viper.Set("version", saved.ConfigVersion)
+
+	return nil
+}
 
-	return viper.WriteConfigAs(configPath)
+// writeFileAtomic replaces path with data, readable only by the owner
+func writeFileAtomic(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
 }
 
 // ConfigFile returns the path to the configuration file
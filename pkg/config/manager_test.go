@@ -229,6 +229,16 @@ func TestManager_Save_CreatesDirectory(t *testing.T) {
 	assert.True(t, info.IsDir())
 }
 
+func TestManager_Load_InvalidSetting(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("poll_interval_seconds: 9999\n"), 0600))
+
+	viper.Reset()
+	_, err := NewManager(configPath).Load()
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	assert.Contains(t, err.Error(), "invalid configuration: invalid poll_interval_seconds 9999")
+}
+
 func TestManager_Load_InvalidConfigFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "invalid.yaml")
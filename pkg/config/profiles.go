@@ -0,0 +1,108 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// profileNamePattern restricts profile names to keys that survive viper's
+// case-insensitive map handling
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ErrProfileNotFound is returned when the selected profile is not configured
+var ErrProfileNotFound = errors.New("profile not found")
+
+// Profile is a named set of overrides for the top-level configuration, e.g.
+// a team quota project or a staging endpoint. Empty fields inherit the
+// top-level value; setting any API key source replaces all of them.
+type Profile struct {
+	APIKey              string `yaml:"api_key,omitempty" json:"-" mapstructure:"api_key"`
+	APIKeyEnv           string `yaml:"api_key_env,omitempty" json:"api_key_env,omitempty" mapstructure:"api_key_env"`
+	APIKeyCommand       string `yaml:"api_key_command,omitempty" json:"api_key_command,omitempty" mapstructure:"api_key_command"`
+	BaseURL             string `yaml:"base_url,omitempty" json:"base_url,omitempty" mapstructure:"base_url"`
+	DefaultModel        string `yaml:"default_model,omitempty" json:"default_model,omitempty" mapstructure:"default_model"`
+	DefaultResolution   string `yaml:"default_resolution,omitempty" json:"default_resolution,omitempty" mapstructure:"default_resolution"`
+	DefaultAspectRatio  string `yaml:"default_aspect_ratio,omitempty" json:"default_aspect_ratio,omitempty" mapstructure:"default_aspect_ratio"`
+	DefaultDuration     int    `yaml:"default_duration,omitempty" json:"default_duration,omitempty" mapstructure:"default_duration"`
+	OutputDirectory     string `yaml:"output_directory,omitempty" json:"output_directory,omitempty" mapstructure:"output_directory"`
+	PollIntervalSeconds int    `yaml:"poll_interval_seconds,omitempty" json:"poll_interval_seconds,omitempty" mapstructure:"poll_interval_seconds"`
+	QueueConcurrency    int    `yaml:"queue_concurrency,omitempty" json:"queue_concurrency,omitempty" mapstructure:"queue_concurrency"`
+}
+
+// ValidateProfileName checks that a profile name is usable as a config key
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// setsAPIKey reports whether the profile has its own API key source
+func (p Profile) setsAPIKey() bool {
+	return p.APIKey != "" || p.APIKeyEnv != "" || p.APIKeyCommand != ""
+}
+
+// ProfileSetsAPIKey reports whether the applied profile has its own API key
+// source, which then takes precedence over VEO3_API_KEY and GEMINI_API_KEY
+func (c *Configuration) ProfileSetsAPIKey() bool {
+	p, ok := c.Profiles[c.Profile]
+	return c.Profile != "" && ok && p.setsAPIKey()
+}
+
+// ProfileNames returns the configured profile names in sorted order
+func (c *Configuration) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyProfile overlays the named profile onto the top-level settings and
+// records it in Profile
+func (c *Configuration) ApplyProfile(name string) error {
+	p, ok := c.Profiles[name]
+	if !ok {
+		if len(c.Profiles) == 0 {
+			return fmt.Errorf("%w: %q (no profiles are configured)", ErrProfileNotFound, name)
+		}
+		return fmt.Errorf("%w: %q (available: %s)", ErrProfileNotFound, name, strings.Join(c.ProfileNames(), ", "))
+	}
+
+	if p.setsAPIKey() {
+		c.APIKey = p.APIKey
+		c.APIKeyEnv = p.APIKeyEnv
+		c.APIKeyCommand = p.APIKeyCommand
+	}
+	if p.BaseURL != "" {
+		c.BaseURL = p.BaseURL
+	}
+	if p.DefaultModel != "" {
+		c.DefaultModel = p.DefaultModel
+	}
+	if p.DefaultResolution != "" {
+		c.DefaultResolution = p.DefaultResolution
+	}
+	if p.DefaultAspectRatio != "" {
+		c.DefaultAspectRatio = p.DefaultAspectRatio
+	}
+	if p.DefaultDuration != 0 {
+		c.DefaultDuration = p.DefaultDuration
+	}
+	if p.OutputDirectory != "" {
+		c.OutputDirectory = p.OutputDirectory
+	}
+	if p.PollIntervalSeconds != 0 {
+		c.PollIntervalSeconds = p.PollIntervalSeconds
+	}
+	if p.QueueConcurrency != 0 {
+		c.QueueConcurrency = p.QueueConcurrency
+	}
+
+	c.Profile = name
+	return nil
+}
@@ -0,0 +1,220 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profilesConfig = `
+api_key: personal-key
+default_model: veo-3.1-generate-preview
+default_resolution: 720p
+output_directory: ./videos
+active_profile: team
+profiles:
+  team:
+    api_key_command: pass show veo3/team
+    default_resolution: 1080p
+    queue_concurrency: 8
+  staging:
+    base_url: http://localhost:8089
+    api_key: fake-key
+`
+
+func writeProfilesConfig(t *testing.T) string {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(profilesConfig), 0600))
+	return configPath
+}
+
+func TestConfiguration_ApplyProfile(t *testing.T) {
+	cfg := &Configuration{
+		APIKey:            "personal-key",
+		APIKeyCommand:     "pass show veo3/personal",
+		DefaultModel:      DefaultModel,
+		DefaultResolution: "720p",
+		OutputDirectory:   "./videos",
+		Profiles: map[string]Profile{
+			"team":    {APIKeyEnv: "TEAM_KEY", DefaultResolution: "1080p"},
+			"staging": {BaseURL: "http://localhost:8089"},
+		},
+	}
+
+	staging := *cfg
+	require.NoError(t, staging.ApplyProfile("staging"))
+	assert.Equal(t, "http://localhost:8089", staging.BaseURL)
+	assert.Equal(t, "personal-key", staging.APIKey, "key sources are inherited when unset")
+	assert.Equal(t, "staging", staging.Profile)
+
+	team := *cfg
+	require.NoError(t, team.ApplyProfile("team"))
+	assert.Equal(t, "1080p", team.DefaultResolution)
+	assert.Equal(t, "./videos", team.OutputDirectory)
+	assert.Equal(t, "TEAM_KEY", team.APIKeyEnv)
+	assert.Empty(t, team.APIKey, "a profile key source replaces all top-level sources")
+	assert.Empty(t, team.APIKeyCommand)
+
+	err := cfg.ApplyProfile("prod")
+	require.ErrorIs(t, err, ErrProfileNotFound)
+	assert.Contains(t, err.Error(), "available: staging, team")
+}
+
+func TestManager_Load_ProfileSelection(t *testing.T) {
+	configPath := writeProfilesConfig(t)
+
+	t.Run("active profile", func(t *testing.T) {
+		viper.Reset()
+		cfg, err := NewManager(configPath).Load()
+		require.NoError(t, err)
+		assert.Equal(t, "team", cfg.Profile)
+		assert.Equal(t, "1080p", cfg.DefaultResolution)
+		assert.Equal(t, 8, cfg.QueueConcurrency)
+		assert.Equal(t, "pass show veo3/team", cfg.APIKeyCommand)
+	})
+
+	t.Run("environment overrides active profile", func(t *testing.T) {
+		t.Setenv("VEO3_PROFILE", "staging")
+		viper.Reset()
+		cfg, err := NewManager(configPath).Load()
+		require.NoError(t, err)
+		assert.Equal(t, "staging", cfg.Profile)
+		assert.Equal(t, "http://localhost:8089", cfg.BaseURL)
+		assert.Equal(t, "fake-key", cfg.APIKey)
+	})
+
+	t.Run("SetProfile overrides environment", func(t *testing.T) {
+		t.Setenv("VEO3_PROFILE", "staging")
+		viper.Reset()
+		manager := NewManager(configPath)
+		manager.SetProfile("team")
+		cfg, err := manager.Load()
+		require.NoError(t, err)
+		assert.Equal(t, "team", cfg.Profile)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		viper.Reset()
+		manager := NewManager(configPath)
+		manager.SetProfile("prod")
+		_, err := manager.Load()
+		assert.ErrorIs(t, err, ErrProfileNotFound)
+	})
+
+	t.Run("LoadBase ignores profiles", func(t *testing.T) {
+		t.Setenv("VEO3_PROFILE", "staging")
+		viper.Reset()
+		cfg, err := NewManager(configPath).LoadBase()
+		require.NoError(t, err)
+		assert.Empty(t, cfg.Profile)
+		assert.Equal(t, "720p", cfg.DefaultResolution)
+		assert.Equal(t, "personal-key", cfg.APIKey)
+	})
+}
+
+func TestManager_Load_ProfileKeyBeatsEnvironment(t *testing.T) {
+	configPath := writeProfilesConfig(t)
+	t.Setenv("GEMINI_API_KEY", "env-gemini-key")
+	t.Setenv("VEO3_API_KEY", "env-veo3-key")
+
+	t.Run("profile with a key source", func(t *testing.T) {
+		viper.Reset()
+		manager := NewManager(configPath)
+		manager.SetProfile("staging")
+		cfg, err := manager.Load()
+		require.NoError(t, err)
+		assert.Equal(t, "fake-key", cfg.APIKey)
+		assert.Equal(t, OriginProfile, manager.Origins()["api_key"].Layer)
+
+		key, err := cfg.ResolveAPIKey(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "fake-key", key)
+	})
+
+	t.Run("profile without a key source", func(t *testing.T) {
+		t.Setenv("VEO3_API_KEY", "")
+		cfg := &Configuration{
+			APIKey:   "personal-key",
+			Profile:  "staging",
+			Profiles: map[string]Profile{"staging": {BaseURL: "http://localhost:8089"}},
+		}
+
+		key, err := cfg.ResolveAPIKey(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "env-gemini-key", key)
+	})
+}
+
+func TestManager_Save_Profiles(t *testing.T) {
+	configPath := writeProfilesConfig(t)
+	manager := NewManager(configPath)
+
+	viper.Reset()
+	cfg, err := manager.LoadBase()
+	require.NoError(t, err)
+
+	delete(cfg.Profiles, "staging")
+	cfg.Profiles["nightly"] = Profile{DefaultModel: "veo-3.1-fast-generate-preview"}
+	cfg.ActiveProfile = "nightly"
+	require.NoError(t, manager.Save(cfg))
+
+	viper.Reset()
+	saved, err := manager.LoadBase()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nightly", "team"}, saved.ProfileNames())
+	assert.Equal(t, "nightly", saved.ActiveProfile)
+	assert.Equal(t, "personal-key", saved.APIKey)
+
+	info, err := os.Stat(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestConfiguration_Validate_Profiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Configuration
+		wantErr string
+	}{
+		{
+			name:    "uppercase name",
+			cfg:     Configuration{Profiles: map[string]Profile{"Team": {}}},
+			wantErr: "invalid profile name",
+		},
+		{
+			name:    "unknown model",
+			cfg:     Configuration{Profiles: map[string]Profile{"team": {DefaultModel: "veo-9"}}},
+			wantErr: "profiles.team.default_model",
+		},
+		{
+			name:    "missing active profile",
+			cfg:     Configuration{ActiveProfile: "team"},
+			wantErr: "invalid active_profile",
+		},
+		{
+			name: "valid",
+			cfg: Configuration{
+				ActiveProfile: "team",
+				Profiles:      map[string]Profile{"team": {DefaultModel: DefaultModel}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
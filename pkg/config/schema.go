@@ -0,0 +1,260 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LegacyConfigVersion is assumed for files without a version field. Releases
+// before schema 2.0 never wrote the field.
+const LegacyConfigVersion = "1.0"
+
+// configVersions lists the schema versions this release can read, oldest first
+var configVersions = []string{LegacyConfigVersion, DefaultConfigVersion}
+
+// legacyFlagKeys are command-line flag names that releases before schema 2.0
+// wrote into the config file alongside the real settings
+var legacyFlagKeys = []string{
+	"api-key", "aspect-ratio", "duration", "json", "log-file", "log-format",
+	"model", "output", "profile", "quiet", "resolution", "verbose", "wire-debug",
+}
+
+// schemaKeys are the top-level keys of the current schema
+var schemaKeys = []string{
+	"version", "api_key", "api_key_env", "api_key_command", "base_url",
+	"default_model", "default_resolution", "default_aspect_ratio", "default_duration",
+	"output_directory", "poll_interval_seconds", "webhooks", "webhook_secret",
+	"post_download_hooks", "queue_concurrency", "active_profile", "profiles",
+}
+
+// migration upgrades a config document from one schema version to the next
+// and describes each change it made
+type migration struct {
+	from    string
+	to      string
+	migrate func(doc *yaml.Node) []string
+}
+
+// migrations are applied in order until the document reaches DefaultConfigVersion
+var migrations = []migration{
+	{from: "1.0", to: "2.0", migrate: migrateV1ToV2},
+}
+
+// MigrationResult describes the outcome of Manager.Migrate
+type MigrationResult struct {
+	ConfigFile  string   `json:"config_file"`
+	FromVersion string   `json:"from_version"`
+	ToVersion   string   `json:"to_version"`
+	Changes     []string `json:"changes,omitempty"`
+	BackupFile  string   `json:"backup_file,omitempty"`
+}
+
+// Migrated reports whether the file needed an upgrade
+func (r *MigrationResult) Migrated() bool {
+	return r.FromVersion != r.ToVersion
+}
+
+// isKnownConfigVersion reports whether this release can read version
+func isKnownConfigVersion(version string) bool {
+	return configVersionIndex(version) >= 0
+}
+
+func configVersionIndex(version string) int {
+	for i, v := range configVersions {
+		if v == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsOutdatedConfigVersion reports whether version predates the current schema.
+// An empty version is treated as LegacyConfigVersion.
+func IsOutdatedConfigVersion(version string) bool {
+	if version == "" {
+		version = LegacyConfigVersion
+	}
+	i := configVersionIndex(version)
+	return i >= 0 && i < configVersionIndex(DefaultConfigVersion)
+}
+
+// Migrate upgrades the config file to the current schema version. The
+// original file is copied to a backup next to it before it is rewritten, and
+// comments and key order are preserved. With dryRun nothing is written.
+func (m *Manager) Migrate(dryRun bool) (*MigrationResult, error) {
+	configPath := m.getConfigPath()
+	data, err := os.ReadFile(configPath) // #nosec G304 -- Path is the user's config file
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	root := documentMapping(&doc)
+	if root == nil {
+		return nil, fmt.Errorf("failed to parse config file: top level is not a mapping")
+	}
+
+	version := LegacyConfigVersion
+	if node := mappingValue(root, "version"); node != nil && node.Value != "" {
+		version = node.Value
+	}
+	if !isKnownConfigVersion(version) {
+		return nil, fmt.Errorf("unsupported config version %q (this release reads up to %s)", version, DefaultConfigVersion)
+	}
+
+	result := &MigrationResult{ConfigFile: configPath, FromVersion: version, ToVersion: version}
+	for _, step := range migrations {
+		if step.from != result.ToVersion {
+			continue
+		}
+		result.Changes = append(result.Changes, step.migrate(root)...)
+		setMappingValue(root, "version", step.to)
+		result.Changes = append(result.Changes, fmt.Sprintf("set version to %s", step.to))
+		result.ToVersion = step.to
+	}
+
+	if !result.Migrated() || dryRun {
+		return result, nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	_ = encoder.Close()
+
+	backupPath := backupPathFor(configPath, result.FromVersion)
+	if err := os.WriteFile(backupPath, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+	result.BackupFile = backupPath
+
+	if err := writeFileAtomic(configPath, buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UnknownKeys returns top-level keys in the config file that are not part of
+// the current schema, e.g. typos or keys left by older releases
+func (m *Manager) UnknownKeys() ([]string, error) {
+	data, err := os.ReadFile(m.getConfigPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	root := documentMapping(&doc)
+	if root == nil {
+		return nil, nil
+	}
+
+	var unknown []string
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if !contains(schemaKeys, root.Content[i].Value) {
+			unknown = append(unknown, root.Content[i].Value)
+		}
+	}
+	return unknown, nil
+}
+
+// migrateV1ToV2 removes the flag values and empty placeholders that the
+// viper-based writer of 1.0 releases stored in the file
+func migrateV1ToV2(root *yaml.Node) []string {
+	var changes []string
+	kept := root.Content[:0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch {
+		case contains(legacyFlagKeys, key.Value):
+			changes = append(changes, fmt.Sprintf("removed command-line flag value '%s'", key.Value))
+		case contains(schemaKeys, key.Value) && isEmptyNode(value):
+			changes = append(changes, fmt.Sprintf("removed empty setting '%s'", key.Value))
+		default:
+			kept = append(kept, key, value)
+		}
+	}
+	root.Content = kept
+	return changes
+}
+
+// backupPathFor returns an unused backup path for a config file at version
+func backupPathFor(configPath, version string) string {
+	backupPath := fmt.Sprintf("%s.v%s.bak", configPath, version)
+	if _, err := os.Stat(backupPath); err == nil {
+		backupPath = fmt.Sprintf("%s.v%s.%s.bak", configPath, version, time.Now().Format("20060102-150405"))
+	}
+	return backupPath
+}
+
+// documentMapping returns the top-level mapping of a YAML document, creating
+// it for an empty document
+func documentMapping(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == 0 {
+		doc.Kind = yaml.DocumentNode
+	}
+	if doc.Kind != yaml.DocumentNode {
+		return nil
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+	return doc.Content[0]
+}
+
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue sets a string value, adding the key at the top if needed
+func setMappingValue(mapping *yaml.Node, key, value string) {
+	if node := mappingValue(mapping, key); node != nil {
+		node.Kind, node.Tag, node.Value, node.Style, node.Content = yaml.ScalarNode, "!!str", value, yaml.DoubleQuotedStyle, nil
+		return
+	}
+	mapping.Content = append([]*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, Style: yaml.DoubleQuotedStyle},
+	}, mapping.Content...)
+}
+
+// isEmptyNode reports whether a value is null, an empty string, zero or an
+// empty collection
+func isEmptyNode(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Tag == "!!null" || node.Value == "" || (node.Tag == "!!int" && node.Value == "0")
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(node.Content) == 0
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
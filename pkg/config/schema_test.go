@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// legacyConfig mimics a file written by the viper-based writer of 1.0 releases
+const legacyConfig = `# personal settings
+api_key: AIza-test
+api-key: ""
+json: false
+verbose: false
+default_model: veo-3.1-generate-preview
+default_resolution: 1080p
+webhooks: []
+webhook_secret: ""
+queue_concurrency: 0
+`
+
+func TestManager_Migrate(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(legacyConfig), 0600))
+	manager := NewManager(configPath)
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		result, err := manager.Migrate(true)
+		require.NoError(t, err)
+		assert.Equal(t, "1.0", result.FromVersion)
+		assert.Equal(t, "2.0", result.ToVersion)
+		assert.Contains(t, result.Changes, "removed command-line flag value 'api-key'")
+		assert.Contains(t, result.Changes, "removed empty setting 'webhooks'")
+		assert.Empty(t, result.BackupFile)
+
+		data, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, legacyConfig, string(data))
+	})
+
+	t.Run("migrates and keeps a backup", func(t *testing.T) {
+		result, err := manager.Migrate(false)
+		require.NoError(t, err)
+		assert.True(t, result.Migrated())
+		assert.Equal(t, configPath+".v1.0.bak", result.BackupFile)
+
+		backup, err := os.ReadFile(result.BackupFile)
+		require.NoError(t, err)
+		assert.Equal(t, legacyConfig, string(backup))
+
+		data, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		migrated := string(data)
+		assert.Contains(t, migrated, "# personal settings")
+		assert.Contains(t, migrated, `version: "2.0"`)
+		assert.Contains(t, migrated, "default_resolution: 1080p")
+		for _, removed := range []string{"api-key", "json", "verbose", "webhooks", "webhook_secret", "queue_concurrency"} {
+			assert.NotContains(t, migrated, removed+":")
+		}
+
+		unknown, err := manager.UnknownKeys()
+		require.NoError(t, err)
+		assert.Empty(t, unknown)
+
+		viper.Reset()
+		cfg, err := manager.LoadBase()
+		require.NoError(t, err)
+		assert.Equal(t, "AIza-test", cfg.APIKey)
+		assert.Equal(t, DefaultConfigVersion, cfg.ConfigVersion)
+	})
+
+	t.Run("current version is left alone", func(t *testing.T) {
+		result, err := manager.Migrate(false)
+		require.NoError(t, err)
+		assert.False(t, result.Migrated())
+		assert.Empty(t, result.BackupFile)
+	})
+
+	t.Run("newer version is rejected", func(t *testing.T) {
+		futurePath := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(futurePath, []byte("version: \"9.0\"\n"), 0600))
+		_, err := NewManager(futurePath).Migrate(false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported config version")
+	})
+}
+
+func TestManager_Save_WritesCurrentVersion(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	manager := NewManager(configPath)
+
+	require.NoError(t, manager.Save(&Configuration{DefaultModel: DefaultModel, ConfigVersion: LegacyConfigVersion}))
+
+	viper.Reset()
+	cfg, err := manager.LoadBase()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfigVersion, cfg.ConfigVersion)
+}
+
+func TestIsOutdatedConfigVersion(t *testing.T) {
+	assert.True(t, IsOutdatedConfigVersion(""))
+	assert.True(t, IsOutdatedConfigVersion("1.0"))
+	assert.False(t, IsOutdatedConfigVersion(DefaultConfigVersion))
+	assert.False(t, IsOutdatedConfigVersion("9.0"))
+}
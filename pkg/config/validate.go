@@ -0,0 +1,236 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/jasongoecke/go-veo3/internal/validation"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+)
+
+// MaxPollInterval is the longest status polling interval accepted, in seconds
+const MaxPollInterval = 300
+
+// Validate checks if the configuration values are valid. Every problem is
+// reported; the returned error unwraps to one error per invalid field.
+func (c *Configuration) Validate() error {
+	var errs []error
+
+	if c.ConfigVersion != "" && !isKnownConfigVersion(c.ConfigVersion) {
+		errs = append(errs, fmt.Errorf("invalid version '%s': unsupported config version (this release reads up to %s)", c.ConfigVersion, DefaultConfigVersion))
+	}
+
+	// Validate default model exists in registry
+	if c.DefaultModel != "" {
+		if _, exists := veo3.GetModel(c.DefaultModel); !exists {
+			errs = append(errs, fmt.Errorf("invalid default_model '%s': model not found in registry. Use 'veo3 models list' to see available models", c.DefaultModel))
+		}
+	}
+	errs = append(errs, validateGenerationDefaults("", c.DefaultModel, c.DefaultResolution, c.DefaultAspectRatio, c.DefaultDuration)...)
+
+	if err := validateOutputDirectory("output_directory", c.OutputDirectory); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validatePollInterval("poll_interval_seconds", c.PollIntervalSeconds); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateHTTPURL("base_url", c.BaseURL); err != nil {
+		errs = append(errs, err)
+	}
+	for i, webhook := range c.Webhooks {
+		if err := validateHTTPURL(fmt.Sprintf("webhooks[%d]", i), webhook); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for i, hook := range c.PostDownloadHooks {
+		if hook.Command == "" {
+			errs = append(errs, fmt.Errorf("invalid post_download_hooks[%d]: command is required", i))
+		}
+		if hook.TimeoutSeconds < 0 {
+			errs = append(errs, fmt.Errorf("invalid post_download_hooks[%d]: timeout_seconds must not be negative", i))
+		}
+		switch HookFailurePolicy(hook.OnFailure) {
+		case "", HookFail, HookStop, HookContinue:
+		default:
+			errs = append(errs, fmt.Errorf("invalid post_download_hooks[%d]: on_failure must be fail, stop, or continue", i))
+		}
+	}
+
+	if c.QueueConcurrency < 0 {
+		errs = append(errs, fmt.Errorf("invalid queue_concurrency %d: must not be negative", c.QueueConcurrency))
+	}
+
+	for _, name := range c.ProfileNames() {
+		errs = append(errs, c.validateProfile(name)...)
+	}
+	if c.ActiveProfile != "" {
+		if _, ok := c.Profiles[c.ActiveProfile]; !ok {
+			errs = append(errs, fmt.Errorf("invalid active_profile '%s': profile not found", c.ActiveProfile))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateProfile checks a profile's own fields and the generation defaults
+// it produces when layered over the top-level configuration
+func (c *Configuration) validateProfile(name string) []error {
+	if err := ValidateProfileName(name); err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	profile := c.Profiles[name]
+	prefix := "profiles." + name + "."
+
+	if profile.DefaultModel != "" {
+		if _, exists := veo3.GetModel(profile.DefaultModel); !exists {
+			errs = append(errs, fmt.Errorf("invalid %sdefault_model '%s': model not found in registry", prefix, profile.DefaultModel))
+		}
+	}
+	if profile.DefaultModel != "" || profile.DefaultResolution != "" || profile.DefaultAspectRatio != "" || profile.DefaultDuration != 0 {
+		effective := *c
+		effective.Profiles = map[string]Profile{name: profile}
+		_ = effective.ApplyProfile(name)
+		errs = append(errs, validateGenerationDefaults(prefix, effective.DefaultModel, effective.DefaultResolution, effective.DefaultAspectRatio, effective.DefaultDuration)...)
+	}
+
+	if err := validateOutputDirectory(prefix+"output_directory", profile.OutputDirectory); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validatePollInterval(prefix+"poll_interval_seconds", profile.PollIntervalSeconds); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateHTTPURL(prefix+"base_url", profile.BaseURL); err != nil {
+		errs = append(errs, err)
+	}
+	if profile.QueueConcurrency < 0 {
+		errs = append(errs, fmt.Errorf("invalid %squeue_concurrency %d: must not be negative", prefix, profile.QueueConcurrency))
+	}
+	return errs
+}
+
+// validateGenerationDefaults checks resolution, aspect ratio and duration
+// against the model they will be used with. Empty values fall back to the
+// built-in defaults and are not checked.
+func validateGenerationDefaults(prefix, modelID, resolution, aspectRatio string, duration int) []error {
+	if modelID == "" {
+		modelID = DefaultModel
+	}
+	model, knownModel := veo3.GetModel(modelID)
+
+	var errs []error
+	if resolution != "" {
+		if err := validation.ValidateResolution(resolution); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %sdefault_resolution '%s': %w", prefix, resolution, err))
+		} else if knownModel {
+			if err := veo3.ValidateModelForResolution(modelID, resolution); err != nil {
+				errs = append(errs, fmt.Errorf("invalid %sdefault_resolution '%s': %w", prefix, resolution, err))
+			}
+		}
+	}
+
+	if aspectRatio != "" {
+		if err := validation.ValidateAspectRatio(aspectRatio); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %sdefault_aspect_ratio '%s': %w", prefix, aspectRatio, err))
+		} else if knownModel && model.Constraints.RequiredAspectRatio != "" && aspectRatio != model.Constraints.RequiredAspectRatio {
+			errs = append(errs, fmt.Errorf("invalid %sdefault_aspect_ratio '%s': model %s requires %s", prefix, aspectRatio, modelID, model.Constraints.RequiredAspectRatio))
+		}
+	}
+
+	if duration < 0 {
+		errs = append(errs, fmt.Errorf("invalid %sdefault_duration %d: must not be negative", prefix, duration))
+	} else if duration > 0 && knownModel {
+		if err := veo3.ValidateModelForDuration(modelID, duration); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %sdefault_duration %d: %w", prefix, duration, err))
+		}
+	}
+
+	return errs
+}
+
+// validatePollInterval checks that a polling interval is unset or sane
+func validatePollInterval(field string, seconds int) error {
+	if seconds < 0 || seconds > MaxPollInterval {
+		return fmt.Errorf("invalid %s %d: must be between 1 and %d seconds", field, seconds, MaxPollInterval)
+	}
+	return nil
+}
+
+// validateHTTPURL checks that an optional URL is an absolute http(s) URL
+func validateHTTPURL(field, value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid %s '%s': must be an http or https URL", field, value)
+	}
+	return nil
+}
+
+// CheckOutputDirectories checks that the output directories of the
+// configuration and its profiles that already exist are writable, by creating
+// and removing a file in each. Validate leaves this to 'config validate' so
+// that loading the configuration does not write to disk.
+func (c *Configuration) CheckOutputDirectories() error {
+	errs := []error{checkWritable("output_directory", c.OutputDirectory)}
+	for _, name := range c.ProfileNames() {
+		errs = append(errs, checkWritable("profiles."+name+".output_directory", c.Profiles[name].OutputDirectory))
+	}
+	return errors.Join(errs...)
+}
+
+// checkWritable checks that an output directory, if it exists, is writable
+func checkWritable(field, dir string) error {
+	if dir == "" {
+		return nil
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil // reported by Validate, or created on first download
+	}
+	probe, err := os.CreateTemp(dir, ".veo3-write-check-*")
+	if err != nil {
+		return fmt.Errorf("invalid %s '%s': directory is not writable", field, dir)
+	}
+	_ = probe.Close()
+	_ = os.Remove(probe.Name())
+	return nil
+}
+
+// validateOutputDirectory checks that an existing output directory is a
+// directory. A missing directory is created on first download, so only its
+// nearest existing parent has to be a directory.
+func validateOutputDirectory(field, dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	info, err := os.Stat(dir)
+	if err == nil {
+		if !info.IsDir() {
+			return fmt.Errorf("invalid %s '%s': not a directory", field, dir)
+		}
+		return nil
+	}
+	if errors.Is(err, os.ErrPermission) {
+		return fmt.Errorf("invalid %s '%s': %w", field, dir, err)
+	}
+
+	for parent := filepath.Dir(filepath.Clean(dir)); ; parent = filepath.Dir(parent) {
+		info, err := os.Stat(parent)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("invalid %s '%s': %s is not a directory", field, dir, parent)
+			}
+			return nil
+		}
+		if parent == filepath.Dir(parent) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/config"
+	"github.com/jasongoecke/go-veo3/pkg/templates"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+)
+
+// DefaultTimeout is applied to hooks that do not set timeout_seconds
+const DefaultTimeout = 5 * time.Minute
+
+// maxOutputBytes limits how much hook output is kept in results
+const maxOutputBytes = 4096
+
+// Result records the outcome of a single hook execution
+type Result struct {
+	Name     string        `json:"name"`
+	Command  string        `json:"command"`
+	Args     []string      `json:"args,omitempty"`
+	Success  bool          `json:"success"`
+	Skipped  bool          `json:"skipped,omitempty"`
+	ExitCode int           `json:"exit_code"`
+	Error    string        `json:"error,omitempty"`
+	Output   string        `json:"output,omitempty"` // Tail of combined stdout and stderr
+	Duration time.Duration `json:"duration"`
+}
+
+// Runner executes the configured post-download hooks in order
+type Runner struct {
+	hooks []config.HookConfig
+}
+
+// NewRunner creates a runner for the given hook definitions
+func NewRunner(hooks []config.HookConfig) *Runner {
+	return &Runner{hooks: hooks}
+}
+
+// Enabled reports whether any hooks are configured
+func (r *Runner) Enabled() bool {
+	return r != nil && len(r.hooks) > 0
+}
+
+// Run executes each hook against the downloaded video. A failing hook with
+// the "fail" policy stops the pipeline and is returned as an error; "stop"
+// skips the remaining hooks without failing; "continue" moves on to the next
+// hook. Skipped hooks are included in the results.
+func (r *Runner) Run(ctx context.Context, video *veo3.GeneratedVideo) ([]Result, error) {
+	if !r.Enabled() || video == nil {
+		return nil, nil
+	}
+
+	variables := Variables(video)
+	results := make([]Result, 0, len(r.hooks))
+
+	var pipelineErr error
+	stopped := false
+	for i, hook := range r.hooks {
+		name := hookName(hook, i)
+
+		if stopped {
+			results = append(results, Result{Name: name, Command: hook.Command, Skipped: true})
+			continue
+		}
+
+		result := runHook(ctx, hook, name, variables)
+		results = append(results, result)
+		if result.Success {
+			continue
+		}
+
+		switch config.HookFailurePolicy(hook.OnFailure) {
+		case config.HookContinue:
+			continue
+		case config.HookStop:
+			stopped = true
+		default:
+			stopped = true
+			pipelineErr = fmt.Errorf("post-download hook %s failed: %s", name, result.Error)
+		}
+	}
+
+	return results, pipelineErr
+}
+
+// Variables returns the template variables available to hook arguments and
+// environment values, keyed like the GeneratedVideo JSON fields
+func Variables(video *veo3.GeneratedVideo) map[string]string {
+	return map[string]string{
+		"file_path":               video.FilePath,
+		"operation_id":            video.OperationID,
+		"model":                   video.Model,
+		"prompt":                  video.Prompt,
+		"duration_seconds":        strconv.Itoa(video.DurationSeconds),
+		"resolution":              video.Resolution,
+		"aspect_ratio":            video.AspectRatio,
+		"file_size_bytes":         strconv.FormatInt(video.FileSizeBytes, 10),
+		"generation_time_seconds": strconv.Itoa(video.GenerationTimeSeconds),
+		"template":                video.Template,
+	}
+}
+
+// runHook executes a single hook and captures its result
+func runHook(ctx context.Context, hook config.HookConfig, name string, variables map[string]string) Result {
+	result := Result{Name: name, Command: hook.Command, ExitCode: -1}
+	start := time.Now()
+	defer func() { result.Duration = time.Since(start) }()
+
+	args := make([]string, 0, len(hook.Args))
+	for _, arg := range hook.Args {
+		rendered, err := templates.SubstituteVariables(arg, variables)
+		if err != nil {
+			result.Error = fmt.Sprintf("invalid argument %q: %v", arg, err)
+			return result
+		}
+		args = append(args, rendered)
+	}
+	result.Args = args
+
+	env, err := buildEnv(hook.Env, variables)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	timeout := DefaultTimeout
+	if hook.TimeoutSeconds > 0 {
+		timeout = time.Duration(hook.TimeoutSeconds) * time.Second
+	}
+	hookCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(hookCtx, hook.Command, args...) // #nosec G204 -- Hook commands come from the user's own configuration
+	cmd.Env = env
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err = cmd.Run()
+	result.Output = tail(output.String(), maxOutputBytes)
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+	}
+
+	switch {
+	case hookCtx.Err() == context.DeadlineExceeded:
+		result.Error = fmt.Sprintf("timed out after %s", timeout)
+	case err != nil:
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			result.Error = fmt.Sprintf("exited with code %d", result.ExitCode)
+		} else {
+			result.Error = err.Error()
+		}
+	default:
+		result.Success = true
+	}
+
+	return result
+}
+
+// buildEnv returns the process environment plus VEO3_* video variables and
+// the hook's templated environment values
+func buildEnv(hookEnv map[string]string, variables map[string]string) ([]string, error) {
+	env := os.Environ()
+	for key, value := range variables {
+		env = append(env, "VEO3_"+strings.ToUpper(key)+"="+value)
+	}
+
+	for key, value := range hookEnv {
+		rendered, err := templates.SubstituteVariables(value, variables)
+		if err != nil {
+			return nil, fmt.Errorf("invalid environment value for %s: %w", key, err)
+		}
+		env = append(env, key+"="+rendered)
+	}
+
+	return env, nil
+}
+
+// hookName returns the display name for a hook, falling back to its command
+func hookName(hook config.HookConfig, index int) string {
+	if hook.Name != "" {
+		return hook.Name
+	}
+	return fmt.Sprintf("%d:%s", index+1, hook.Command)
+}
+
+// tail returns at most the last n bytes of s
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[len(s)-n:]
+}
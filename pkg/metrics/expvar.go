@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"context"
+	"expvar"
+	"strconv"
+
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+)
+
+// Expvar is an observer that publishes counters under a single expvar map,
+// served by the standard /debug/vars handler. Keys within each sub-map join
+// label values with ":", e.g. api_requests["generate:200"].
+type Expvar struct {
+	root *expvar.Map
+
+	requests        *expvar.Map // call:code -> count
+	requestSeconds  *expvar.Map // call -> total seconds
+	polls           *expvar.Map // model:status -> count
+	completions     *expvar.Map // model:status -> count
+	completionTime  *expvar.Map // model -> total seconds to completion
+	downloads       *expvar.Map // result -> count
+	downloadedBytes *expvar.Map // model -> bytes
+	batchJobs       *expvar.Map // type:result -> count
+}
+
+var _ veo3.Observer = (*Expvar)(nil)
+
+// NewExpvar publishes the metrics map under name. Calling it again with the
+// same name reuses the published map, since expvar names cannot be unregistered.
+func NewExpvar(name string) *Expvar {
+	root, ok := expvar.Get(name).(*expvar.Map)
+	if !ok {
+		root = expvar.NewMap(name)
+	}
+
+	sub := func(key string) *expvar.Map {
+		if existing, ok := root.Get(key).(*expvar.Map); ok {
+			return existing
+		}
+		m := new(expvar.Map).Init()
+		root.Set(key, m)
+		return m
+	}
+
+	return &Expvar{
+		root:            root,
+		requests:        sub("api_requests"),
+		requestSeconds:  sub("api_request_seconds"),
+		polls:           sub("operation_polls"),
+		completions:     sub("operation_completions"),
+		completionTime:  sub("operation_completion_seconds"),
+		downloads:       sub("downloads"),
+		downloadedBytes: sub("downloaded_bytes"),
+		batchJobs:       sub("batch_jobs"),
+	}
+}
+
+// Map returns the published expvar map
+func (e *Expvar) Map() *expvar.Map {
+	return e.root
+}
+
+// RequestCompleted implements veo3.Observer
+func (e *Expvar) RequestCompleted(_ context.Context, event veo3.RequestEvent) {
+	e.requests.Add(event.Call+":"+statusCode(event.StatusCode), 1)
+	e.requestSeconds.AddFloat(event.Call, event.Duration.Seconds())
+}
+
+// OperationPolled implements veo3.Observer
+func (e *Expvar) OperationPolled(_ context.Context, event veo3.PollEvent) {
+	e.polls.Add(event.Model+":"+pollStatus(event.Status, event.Err), 1)
+}
+
+// OperationCompleted implements veo3.Observer
+func (e *Expvar) OperationCompleted(_ context.Context, event veo3.CompletionEvent) {
+	e.completions.Add(event.Model+":"+string(event.Status), 1)
+	e.completionTime.AddFloat(event.Model, event.Duration.Seconds())
+}
+
+// VideoDownloaded implements veo3.Observer
+func (e *Expvar) VideoDownloaded(_ context.Context, event veo3.DownloadEvent) {
+	e.downloads.Add(result(event.Err == nil), 1)
+	e.downloadedBytes.Add(event.Model, event.Bytes)
+}
+
+// BatchJobCompleted implements veo3.Observer
+func (e *Expvar) BatchJobCompleted(_ context.Context, event veo3.BatchJobEvent) {
+	e.batchJobs.Add(event.Type+":"+result(event.Success), 1)
+}
+
+// statusCode renders an HTTP status code label; requests without a response are "error"
+func statusCode(code int) string {
+	if code == 0 {
+		return "error"
+	}
+	return strconv.Itoa(code)
+}
+
+// pollStatus renders a poll outcome label; failed polls are "error"
+func pollStatus(status veo3.OperationStatus, err error) string {
+	if err != nil || status == "" {
+		return "error"
+	}
+	return string(status)
+}
+
+// result renders a success or failure label
+func result(success bool) string {
+	if success {
+		return "success"
+	}
+	return "failure"
+}
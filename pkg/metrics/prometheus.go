@@ -0,0 +1,264 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+)
+
+// ContentType is the Prometheus text exposition format content type
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var (
+	// requestBuckets are latency buckets in seconds for API requests
+	requestBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+	// completionBuckets are time-to-completion buckets in seconds for operations
+	completionBuckets = []float64{30, 60, 90, 120, 180, 300, 600, 1200}
+	// jobBuckets are duration buckets in seconds for batch jobs
+	jobBuckets = []float64{30, 60, 120, 300, 600, 1200, 1800}
+)
+
+// Prometheus is an observer that aggregates events in memory and serves them
+// in the Prometheus text exposition format
+type Prometheus struct {
+	mu sync.Mutex
+
+	requests        *counterVec
+	requestDuration *histogramVec
+	polls           *counterVec
+	completions     *histogramVec
+	downloads       *counterVec
+	downloadedBytes *counterVec
+	batchJobs       *counterVec
+	batchDuration   *histogramVec
+}
+
+var _ veo3.Observer = (*Prometheus)(nil)
+
+// NewPrometheus creates an empty Prometheus collector
+func NewPrometheus() *Prometheus {
+	return &Prometheus{
+		requests: newCounterVec("veo3_api_requests_total",
+			"Veo API requests by client call and HTTP status code.", "call", "code"),
+		requestDuration: newHistogramVec("veo3_api_request_duration_seconds",
+			"Veo API request latency.", requestBuckets, "call"),
+		polls: newCounterVec("veo3_operation_polls_total",
+			"Operation status polls by model and reported status.", "model", "status"),
+		completions: newHistogramVec("veo3_operation_completion_seconds",
+			"Time from submission to completion of operations.", completionBuckets, "model", "status"),
+		downloads: newCounterVec("veo3_downloads_total",
+			"Video downloads by result.", "result"),
+		downloadedBytes: newCounterVec("veo3_downloaded_bytes_total",
+			"Bytes of video downloaded.", "model"),
+		batchJobs: newCounterVec("veo3_batch_jobs_total",
+			"Batch jobs by type and result.", "type", "result"),
+		batchDuration: newHistogramVec("veo3_batch_job_duration_seconds",
+			"Batch job duration including polling and download.", jobBuckets, "type"),
+	}
+}
+
+// RequestCompleted implements veo3.Observer
+func (p *Prometheus) RequestCompleted(_ context.Context, event veo3.RequestEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests.add(1, event.Call, statusCode(event.StatusCode))
+	p.requestDuration.observe(event.Duration.Seconds(), event.Call)
+}
+
+// OperationPolled implements veo3.Observer
+func (p *Prometheus) OperationPolled(_ context.Context, event veo3.PollEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.polls.add(1, event.Model, pollStatus(event.Status, event.Err))
+}
+
+// OperationCompleted implements veo3.Observer
+func (p *Prometheus) OperationCompleted(_ context.Context, event veo3.CompletionEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.completions.observe(event.Duration.Seconds(), event.Model, string(event.Status))
+}
+
+// VideoDownloaded implements veo3.Observer
+func (p *Prometheus) VideoDownloaded(_ context.Context, event veo3.DownloadEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.downloads.add(1, result(event.Err == nil))
+	p.downloadedBytes.add(float64(event.Bytes), event.Model)
+}
+
+// BatchJobCompleted implements veo3.Observer
+func (p *Prometheus) BatchJobCompleted(_ context.Context, event veo3.BatchJobEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.batchJobs.add(1, event.Type, result(event.Success))
+	p.batchDuration.observe(event.Duration.Seconds(), event.Type)
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var b strings.Builder
+	p.requests.write(&b)
+	p.requestDuration.write(&b)
+	p.polls.write(&b)
+	p.completions.write(&b)
+	p.downloads.write(&b)
+	p.downloadedBytes.write(&b)
+	p.batchJobs.write(&b)
+	p.batchDuration.write(&b)
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// Handler returns an HTTP handler serving the metrics, e.g. at /metrics
+func (p *Prometheus) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_, _ = p.WriteTo(w)
+	})
+}
+
+// counterVec is a counter partitioned by label values
+type counterVec struct {
+	name, help string
+	labels     []string
+	values     map[string]float64
+}
+
+func newCounterVec(name, help string, labels ...string) *counterVec {
+	return &counterVec{name: name, help: help, labels: labels, values: make(map[string]float64)}
+}
+
+func (c *counterVec) add(delta float64, labelValues ...string) {
+	c.values[seriesKey(labelValues)] += delta
+}
+
+func (c *counterVec) write(b *strings.Builder) {
+	if len(c.values) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(b, "%s%s %s\n", c.name, formatLabels(c.labels, splitKey(key)), formatFloat(c.values[key]))
+	}
+}
+
+// histogram holds cumulative bucket counts for one label combination
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// histogramVec is a histogram partitioned by label values
+type histogramVec struct {
+	name, help string
+	labels     []string
+	buckets    []float64
+	values     map[string]*histogram
+}
+
+func newHistogramVec(name, help string, buckets []float64, labels ...string) *histogramVec {
+	return &histogramVec{name: name, help: help, labels: labels, buckets: buckets, values: make(map[string]*histogram)}
+}
+
+func (h *histogramVec) observe(value float64, labelValues ...string) {
+	key := seriesKey(labelValues)
+	hist, ok := h.values[key]
+	if !ok {
+		hist = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.values[key] = hist
+	}
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			hist.counts[i]++
+		}
+	}
+	hist.sum += value
+	hist.count++
+}
+
+func (h *histogramVec) write(b *strings.Builder) {
+	if len(h.values) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+
+	keys := make([]string, 0, len(h.values))
+	for key := range h.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		hist := h.values[key]
+		values := splitKey(key)
+		bucketLabels := append(append([]string{}, h.labels...), "le")
+
+		for i, bound := range h.buckets {
+			fmt.Fprintf(b, "%s_bucket%s %d\n", h.name,
+				formatLabels(bucketLabels, append(append([]string{}, values...), formatFloat(bound))), hist.counts[i])
+		}
+		fmt.Fprintf(b, "%s_bucket%s %d\n", h.name,
+			formatLabels(bucketLabels, append(append([]string{}, values...), "+Inf")), hist.count)
+		fmt.Fprintf(b, "%s_sum%s %s\n", h.name, formatLabels(h.labels, values), formatFloat(hist.sum))
+		fmt.Fprintf(b, "%s_count%s %d\n", h.name, formatLabels(h.labels, values), hist.count)
+	}
+}
+
+// seriesKey joins label values into a map key
+func seriesKey(values []string) string {
+	return strings.Join(values, "\x00")
+}
+
+// splitKey reverses seriesKey
+func splitKey(key string) []string {
+	return strings.Split(key, "\x00")
+}
+
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatLabels renders {name="value",...} with values escaped per the text format
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		pairs[i] = fmt.Sprintf(`%s="%s"`, name, escaper.Replace(value))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/jasongoecke/go-veo3/internal/logger"
 	"github.com/jasongoecke/go-veo3/pkg/veo3"
 	"github.com/schollz/progressbar/v3"
 )
@@ -17,6 +19,9 @@ import (
 type Downloader struct {
 	client       *http.Client
 	showProgress bool
+	observer     veo3.Observer
+	logger       *slog.Logger
+	onProgress   func(written, total int64)
 }
 
 // NewDownloader creates a new video downloader
@@ -26,11 +31,64 @@ func NewDownloader(showProgress bool) *Downloader {
 			Timeout: 10 * time.Minute, // Allow up to 10 minutes for large video downloads
 		},
 		showProgress: showProgress,
+		observer:     veo3.NopObserver{},
+		logger:       slog.New(slog.DiscardHandler),
 	}
 }
 
+// NewDownloaderForClient creates a downloader that sends requests through the
+// client's transport and middleware chain and reports to its observer and logger
+func NewDownloaderForClient(client *veo3.Client, showProgress bool) *Downloader {
+	d := NewDownloader(showProgress)
+	d.client.Transport = client.Transport()
+	d.SetObserver(client.Observer())
+	d.logger = client.Logger()
+	return d
+}
+
+// SetObserver sets the observer notified of every download attempt
+func (d *Downloader) SetObserver(observer veo3.Observer) {
+	if observer == nil {
+		observer = veo3.NopObserver{}
+	}
+	d.observer = observer
+}
+
+// SetProgressFunc sets a function called as the download is written, with
+// the bytes written so far and the total size (0 if unknown)
+func (d *Downloader) SetProgressFunc(fn func(written, total int64)) {
+	d.onProgress = fn
+}
+
 // DownloadVideo downloads a video from the given URI to the specified path
 func (d *Downloader) DownloadVideo(ctx context.Context, op *veo3.Operation, outputPath string) (*veo3.GeneratedVideo, error) {
+	start := time.Now()
+	video, err := d.download(ctx, op, outputPath)
+
+	event := veo3.DownloadEvent{
+		OperationID: op.ID,
+		Model:       veo3.OperationModel(op),
+		Start:       start,
+		Duration:    time.Since(start),
+		Err:         err,
+	}
+	if video != nil {
+		event.Bytes = video.FileSizeBytes
+	}
+	d.observer.VideoDownloaded(ctx, event)
+
+	log := d.logger.With(logger.OperationID(op.ID), logger.Model(event.Model))
+	if err != nil {
+		log.DebugContext(ctx, "video download failed", "error", err)
+	} else {
+		log.DebugContext(ctx, "video downloaded", "path", outputPath, "bytes", event.Bytes, "duration", event.Duration)
+	}
+
+	return video, err
+}
+
+// download performs a single download attempt
+func (d *Downloader) download(ctx context.Context, op *veo3.Operation, outputPath string) (*veo3.GeneratedVideo, error) {
 	if op.VideoURI == "" {
 		return nil, fmt.Errorf("operation %s has no video URI", op.ID)
 	}
@@ -81,6 +139,12 @@ func (d *Downloader) DownloadVideo(ctx context.Context, op *veo3.Operation, outp
 		reader = &progressReader
 	}
 
+	if d.onProgress != nil {
+		total := max(resp.ContentLength, 0)
+		d.onProgress(0, total)
+		reader = &progressReader{reader: reader, total: total, onProgress: d.onProgress}
+	}
+
 	// Stream the download
 	start := time.Now()
 	written, err := io.Copy(file, reader)
@@ -139,6 +203,23 @@ func (d *Downloader) DownloadVideo(ctx context.Context, op *veo3.Operation, outp
 	return generatedVideo, nil
 }
 
+// progressReader reports the bytes read through it
+type progressReader struct {
+	reader     io.Reader
+	read       int64
+	total      int64
+	onProgress func(written, total int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.read += int64(n)
+		r.onProgress(r.read, r.total)
+	}
+	return n, err
+}
+
 // DownloadVideoWithRetry downloads with automatic retry on failure
 func (d *Downloader) DownloadVideoWithRetry(ctx context.Context, op *veo3.Operation, outputPath string, maxRetries int) (*veo3.GeneratedVideo, error) {
 	var lastErr error
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/jasongoecke/go-veo3/internal/logger"
 	"github.com/jasongoecke/go-veo3/pkg/veo3"
 )
 
@@ -43,7 +44,7 @@ func (p *Poller) PollOperation(ctx context.Context, operationID string, progress
 		}
 
 		// Get current operation status from API
-		op, err := p.client.GetOperation(ctx, operationID)
+		op, err := p.poll(ctx, operationID)
 		if err != nil {
 			retries++
 			if retries > p.maxRetries {
@@ -63,9 +64,6 @@ func (p *Poller) PollOperation(ctx context.Context, operationID string, progress
 		retries = 0
 		interval = p.baseInterval
 
-		// Update operation in manager
-		_ = p.manager.UpdateOperation(op)
-
 		// Call progress callback if provided
 		if progressCallback != nil {
 			progressCallback(op)
@@ -143,12 +141,11 @@ func (p *Poller) StartContinuousPolling(ctx context.Context, progressCallback fu
 			// Poll each active operation once
 			for _, op := range activeOps {
 				go func(operationID string) {
-					updated, err := p.client.GetOperation(ctx, operationID)
+					updated, err := p.poll(ctx, operationID)
 					if err != nil {
 						return // Ignore polling errors in continuous mode
 					}
 
-					_ = p.manager.UpdateOperation(updated)
 					if progressCallback != nil {
 						progressCallback(updated)
 					}
@@ -158,6 +155,72 @@ func (p *Poller) StartContinuousPolling(ctx context.Context, progressCallback fu
 	}
 }
 
+// poll fetches an operation's status, updates the manager and reports the
+// poll, and the completion if the operation just finished, to the observer
+func (p *Poller) poll(ctx context.Context, operationID string) (*veo3.Operation, error) {
+	observer := p.client.Observer()
+	previous, _ := p.manager.GetOperation(operationID)
+
+	start := time.Now()
+	op, err := p.client.GetOperation(ctx, operationID)
+
+	event := veo3.PollEvent{
+		OperationID: operationID,
+		Model:       veo3.OperationModel(previous),
+		Start:       start,
+		Duration:    time.Since(start),
+		Err:         err,
+	}
+	log := p.client.Logger().With(logger.OperationID(operationID), logger.Model(event.Model))
+	if err != nil {
+		observer.OperationPolled(ctx, event)
+		log.DebugContext(ctx, "operation poll failed", "error", err)
+		return nil, err
+	}
+	event.Status = op.Status
+	observer.OperationPolled(ctx, event)
+	log.DebugContext(ctx, "operation polled", "status", op.Status, "progress", op.Progress)
+
+	// The API does not report submission time or request parameters, so keep
+	// what was recorded when the operation was submitted
+	if previous != nil {
+		if !previous.StartTime.IsZero() {
+			op.StartTime = previous.StartTime
+		}
+		for key, value := range previous.Metadata {
+			if _, ok := op.Metadata[key]; !ok {
+				if op.Metadata == nil {
+					op.Metadata = make(map[string]interface{})
+				}
+				op.Metadata[key] = value
+			}
+		}
+	}
+	_ = p.manager.UpdateOperation(op)
+
+	if isTerminal(op.Status) && (previous == nil || !isTerminal(previous.Status)) {
+		end := time.Now()
+		if op.EndTime != nil {
+			end = *op.EndTime
+		}
+		log.DebugContext(ctx, "operation completed", "status", op.Status, "duration", end.Sub(op.StartTime))
+		observer.OperationCompleted(ctx, veo3.CompletionEvent{
+			OperationID: operationID,
+			Model:       veo3.OperationModel(op),
+			Status:      op.Status,
+			Start:       op.StartTime,
+			Duration:    end.Sub(op.StartTime),
+		})
+	}
+
+	return op, nil
+}
+
+// isTerminal reports whether an operation status is final
+func isTerminal(status veo3.OperationStatus) bool {
+	return status == veo3.StatusDone || status == veo3.StatusFailed || status == veo3.StatusCancelled
+}
+
 // WaitForCompletion waits for an operation to complete with progress updates
 func (p *Poller) WaitForCompletion(ctx context.Context, operationID string, showProgress bool) (*veo3.Operation, error) {
 	var progressCallback func(*veo3.Operation)
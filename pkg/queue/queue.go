@@ -0,0 +1,428 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"gopkg.in/yaml.v3"
+)
+
+// Status represents the state of a queued job
+type Status string
+
+const (
+	StatusQueued  Status = "queued"
+	StatusRunning Status = "running"
+	StatusDone    Status = "done"
+	StatusFailed  Status = "failed"
+)
+
+const (
+	// lockTimeout bounds how long a process waits for another to release the queue
+	lockTimeout = 10 * time.Second
+	// staleLockAge is the age after which a lock left by a crashed process is ignored
+	staleLockAge = 2 * time.Minute
+	// runnerHeartbeat is how often a runner refreshes its lock; a runner lock
+	// not refreshed for staleLockAge belongs to a runner that died
+	runnerHeartbeat = staleLockAge / 4
+)
+
+// ErrRunnerActive is returned when another runner is already processing the queue
+var ErrRunnerActive = errors.New("another runner is processing the queue")
+
+// Entry is a batch job waiting in the queue
+type Entry struct {
+	batch.BatchJob `yaml:",inline"`
+	Priority       int        `yaml:"priority" json:"priority"` // Higher runs first
+	NotBefore      *time.Time `yaml:"not_before,omitempty" json:"not_before,omitempty"`
+	Status         Status     `yaml:"status" json:"status"`
+	EnqueuedAt     time.Time  `yaml:"enqueued_at" json:"enqueued_at"`
+	StartedAt      *time.Time `yaml:"started_at,omitempty" json:"started_at,omitempty"`
+	FinishedAt     *time.Time `yaml:"finished_at,omitempty" json:"finished_at,omitempty"`
+	Attempts       int        `yaml:"attempts,omitempty" json:"attempts,omitempty"`
+	ResultOutput   string     `yaml:"result_output,omitempty" json:"result_output,omitempty"` // Path of the downloaded video
+	Error          string     `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+// Ready reports whether a queued entry may start at the given time
+func (e *Entry) Ready(now time.Time) bool {
+	return e.Status == StatusQueued && (e.NotBefore == nil || !now.Before(*e.NotBefore))
+}
+
+// file is the on-disk queue structure
+type file struct {
+	NextID  int     `yaml:"next_id"`
+	Entries []Entry `yaml:"entries"`
+}
+
+// Store is a durable on-disk job queue. Every operation reloads the file under
+// a lock so several processes can enqueue while a runner drains the queue.
+type Store struct {
+	path string
+}
+
+// NewStore creates a store backed by queue.yaml in dir
+func NewStore(dir string) *Store {
+	return &Store{path: filepath.Join(dir, "queue.yaml")}
+}
+
+// Path returns the queue file path
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Add validates and enqueues jobs. Jobs without an ID are assigned one.
+func (s *Store) Add(entries ...Entry) ([]Entry, error) {
+	var added []Entry
+
+	err := s.update(func(f *file) error {
+		active := make(map[string]bool)
+		for _, e := range f.Entries {
+			if e.Status == StatusQueued || e.Status == StatusRunning {
+				active[e.ID] = true
+			}
+		}
+
+		now := time.Now()
+		for _, entry := range entries {
+			if entry.ID == "" {
+				f.NextID++
+				entry.ID = fmt.Sprintf("q%d", f.NextID)
+			}
+			if active[entry.ID] {
+				return fmt.Errorf("job %s is already queued", entry.ID)
+			}
+			if err := batch.ValidateJob(entry.BatchJob); err != nil {
+				return err
+			}
+
+			entry.Status = StatusQueued
+			entry.EnqueuedAt = now
+			active[entry.ID] = true
+			f.Entries = append(f.Entries, entry)
+			added = append(added, entry)
+		}
+		return nil
+	})
+
+	return added, err
+}
+
+// List returns all entries in run order: queued and running first by priority
+// and enqueue time, then finished entries
+func (s *Store) List() ([]Entry, error) {
+	f, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := f.Entries
+	sortEntries(entries)
+	return entries, nil
+}
+
+// Remove deletes queued or finished entries by ID. Running entries cannot be removed.
+func (s *Store) Remove(ids ...string) error {
+	return s.update(func(f *file) error {
+		for _, id := range ids {
+			index := -1
+			for i, e := range f.Entries {
+				if e.ID == id && (index == -1 || e.Status == StatusQueued) {
+					index = i
+				}
+			}
+			if index == -1 {
+				return fmt.Errorf("job not found in queue: %s", id)
+			}
+			if f.Entries[index].Status == StatusRunning {
+				return fmt.Errorf("job %s is running and cannot be removed", id)
+			}
+			f.Entries = append(f.Entries[:index], f.Entries[index+1:]...)
+		}
+		return nil
+	})
+}
+
+// Prune removes finished entries and returns how many were removed
+func (s *Store) Prune() (int, error) {
+	removed := 0
+	err := s.update(func(f *file) error {
+		kept := f.Entries[:0]
+		for _, e := range f.Entries {
+			if e.Status == StatusDone || e.Status == StatusFailed {
+				removed++
+				continue
+			}
+			kept = append(kept, e)
+		}
+		f.Entries = kept
+		return nil
+	})
+	return removed, err
+}
+
+// Claim marks the highest-priority ready entry as running and returns it, or
+// nil if no entry is ready
+func (s *Store) Claim(now time.Time) (*Entry, error) {
+	var claimed *Entry
+
+	err := s.update(func(f *file) error {
+		sortEntries(f.Entries)
+		for i := range f.Entries {
+			if f.Entries[i].Ready(now) {
+				f.Entries[i].Status = StatusRunning
+				f.Entries[i].StartedAt = &now
+				f.Entries[i].Attempts++
+				entry := f.Entries[i]
+				claimed = &entry
+				return nil
+			}
+		}
+		return nil
+	})
+
+	return claimed, err
+}
+
+// Complete records the result of a claimed entry
+func (s *Store) Complete(id string, result *batch.JobResult) error {
+	return s.update(func(f *file) error {
+		for i := range f.Entries {
+			e := &f.Entries[i]
+			if e.ID != id || e.Status != StatusRunning {
+				continue
+			}
+
+			now := time.Now()
+			e.FinishedAt = &now
+			e.Status = StatusFailed
+			if result != nil {
+				e.ResultOutput = result.Output
+				e.Error = result.Error
+				if result.Success {
+					e.Status = StatusDone
+				}
+			}
+			return nil
+		}
+		return fmt.Errorf("running job not found in queue: %s", id)
+	})
+}
+
+// Requeue returns running entries to the queue, e.g. after a runner crashed
+// mid-job, and returns how many were requeued. Only call it while holding the
+// runner lock, or the jobs of a live runner are run twice.
+func (s *Store) Requeue() (int, error) {
+	requeued := 0
+	err := s.update(func(f *file) error {
+		for i := range f.Entries {
+			if f.Entries[i].Status == StatusRunning {
+				f.Entries[i].Status = StatusQueued
+				f.Entries[i].StartedAt = nil
+				requeued++
+			}
+		}
+		return nil
+	})
+	return requeued, err
+}
+
+// Pending returns the number of queued entries and the earliest time one of
+// them becomes ready
+func (s *Store) Pending() (int, *time.Time, error) {
+	f, err := s.read()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	count := 0
+	var next *time.Time
+	for _, e := range f.Entries {
+		if e.Status != StatusQueued {
+			continue
+		}
+		count++
+		readyAt := e.EnqueuedAt
+		if e.NotBefore != nil {
+			readyAt = *e.NotBefore
+		}
+		if next == nil || readyAt.Before(*next) {
+			next = &readyAt
+		}
+	}
+	return count, next, nil
+}
+
+// sortEntries orders active entries by priority (highest first) then enqueue
+// time, followed by finished entries in completion order
+func sortEntries(entries []Entry) {
+	rank := func(s Status) int {
+		switch s {
+		case StatusRunning:
+			return 0
+		case StatusQueued:
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if rank(a.Status) != rank(b.Status) {
+			return rank(a.Status) < rank(b.Status)
+		}
+		if rank(a.Status) == 2 {
+			return a.FinishedAt != nil && b.FinishedAt != nil && a.FinishedAt.Before(*b.FinishedAt)
+		}
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		return a.EnqueuedAt.Before(b.EnqueuedAt)
+	})
+}
+
+// read loads the queue under the lock
+func (s *Store) read() (*file, error) {
+	var snapshot *file
+	err := s.withLock(func() error {
+		f, err := s.load()
+		snapshot = f
+		return err
+	})
+	return snapshot, err
+}
+
+// update loads the queue, applies fn and saves it, all under the lock
+func (s *Store) update(fn func(f *file) error) error {
+	return s.withLock(func() error {
+		f, err := s.load()
+		if err != nil {
+			return err
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+		return s.save(f)
+	})
+}
+
+// load reads the queue file; a missing file is an empty queue
+func (s *Store) load() (*file, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &file{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queue: %w", err)
+	}
+
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse queue: %w", err)
+	}
+	return &f, nil
+}
+
+// save writes the queue atomically so readers never see a partial file
+func (s *Store) save(f *file) error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to marshal queue: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write queue: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write queue: %w", err)
+	}
+	return nil
+}
+
+// withLock runs fn while holding an exclusive lock file next to the queue
+func (s *Store) withLock(fn func() error) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create queue directory: %w", err)
+	}
+
+	lockPath := s.path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		lock, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600) // #nosec G304 -- Lock file lives in the veo3 data directory
+		if err == nil {
+			_ = lock.Close()
+			break
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("failed to lock queue: %w", err)
+		}
+
+		// Break locks left behind by a crashed process
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(lockPath)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for queue lock %s", lockPath)
+		}
+		time.Sleep(25 * time.Millisecond)
+	}
+	defer func() { _ = os.Remove(lockPath) }()
+
+	return fn()
+}
+
+// runnerLock is the exclusive lock of the runner processing a queue. It holds
+// the runner's PID and is kept fresh by a heartbeat, so the lock of a runner
+// that died can be taken over.
+type runnerLock struct {
+	path string
+}
+
+// lockRunner takes the runner lock, failing with ErrRunnerActive while another
+// runner holds a fresh one
+func (s *Store) lockRunner() (*runnerLock, error) {
+	lock := &runnerLock{path: s.path + ".runner"}
+	err := s.withLock(func() error {
+		data, err := os.ReadFile(lock.path)
+		switch {
+		case err == nil:
+			info, statErr := os.Stat(lock.path)
+			if statErr == nil && time.Since(info.ModTime()) <= staleLockAge {
+				return fmt.Errorf("%w (pid %s)", ErrRunnerActive, strings.TrimSpace(string(data)))
+			}
+		case !errors.Is(err, os.ErrNotExist):
+			return fmt.Errorf("failed to read runner lock: %w", err)
+		}
+
+		if err := os.WriteFile(lock.path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0600); err != nil {
+			return fmt.Errorf("failed to write runner lock: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// refresh records a heartbeat
+func (l *runnerLock) refresh() {
+	now := time.Now()
+	_ = os.Chtimes(l.path, now, now)
+}
+
+// release removes the lock
+func (l *runnerLock) release() {
+	_ = os.Remove(l.path)
+}
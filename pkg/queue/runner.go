@@ -0,0 +1,195 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+)
+
+// DefaultPollInterval is how often an idle runner checks the queue for new jobs
+const DefaultPollInterval = 5 * time.Second
+
+// Runner drains a queue with a batch job executor
+type Runner struct {
+	store        *Store
+	executor     batch.JobExecutor
+	concurrency  int
+	pollInterval time.Duration
+	observer     veo3.Observer
+	onStart      func(entry Entry)
+	onFinish     func(entry Entry, result *batch.JobResult)
+}
+
+// NewRunner creates a runner that executes up to concurrency jobs at once
+func NewRunner(store *Store, executor batch.JobExecutor, concurrency int) *Runner {
+	if concurrency < 1 {
+		concurrency = 3 // Default
+	}
+
+	return &Runner{
+		store:        store,
+		executor:     executor,
+		concurrency:  concurrency,
+		pollInterval: DefaultPollInterval,
+		observer:     veo3.NopObserver{},
+	}
+}
+
+// SetObserver sets the observer notified of every job outcome
+func (r *Runner) SetObserver(observer veo3.Observer) {
+	if observer == nil {
+		observer = veo3.NopObserver{}
+	}
+	r.observer = observer
+}
+
+// SetPollInterval sets how often an idle runner checks the queue
+func (r *Runner) SetPollInterval(interval time.Duration) {
+	if interval > 0 {
+		r.pollInterval = interval
+	}
+}
+
+// OnStart registers a callback invoked when a job is claimed
+func (r *Runner) OnStart(fn func(entry Entry)) {
+	r.onStart = fn
+}
+
+// OnFinish registers a callback invoked when a job completes
+func (r *Runner) OnFinish(fn func(entry Entry, result *batch.JobResult)) {
+	r.onFinish = fn
+}
+
+// Run claims and executes ready jobs until ctx is cancelled. When
+// exitWhenEmpty is set it returns once no queued or running jobs remain.
+// Only one runner processes a queue at a time; Run fails with
+// ErrRunnerActive while another is running. Jobs left running by a previous
+// runner that died are requeued first.
+func (r *Runner) Run(ctx context.Context, exitWhenEmpty bool) error {
+	lock, err := r.store.lockRunner()
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+
+	heartbeat := time.NewTicker(runnerHeartbeat)
+	defer heartbeat.Stop()
+
+	if _, err := r.store.Requeue(); err != nil {
+		return err
+	}
+
+	slots := make(chan struct{}, r.concurrency)
+	done := make(chan struct{}, r.concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		// Fill free slots with ready jobs
+		for len(slots) < cap(slots) {
+			entry, err := r.store.Claim(time.Now())
+			if err != nil {
+				return err
+			}
+			if entry == nil {
+				break
+			}
+
+			slots <- struct{}{}
+			wg.Add(1)
+			go func(entry Entry) {
+				defer wg.Done()
+				r.execute(ctx, entry)
+				<-slots
+				// Wake the loop without blocking; one pending signal is enough
+				select {
+				case done <- struct{}{}:
+				default:
+				}
+			}(*entry)
+		}
+
+		if exitWhenEmpty && len(slots) == 0 {
+			pending, _, err := r.store.Pending()
+			if err != nil {
+				return err
+			}
+			if pending == 0 {
+				return nil
+			}
+		}
+
+		wait := r.pollInterval
+		if len(slots) < cap(slots) {
+			if _, next, err := r.store.Pending(); err == nil && next != nil {
+				if until := time.Until(*next); until > 0 && until < wait {
+					wait = until
+				}
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-done:
+			timer.Stop()
+		case <-heartbeat.C:
+			timer.Stop()
+			lock.refresh()
+		case <-timer.C:
+		}
+	}
+}
+
+// execute runs a single claimed job and records its outcome
+func (r *Runner) execute(ctx context.Context, entry Entry) {
+	if r.onStart != nil {
+		r.onStart(entry)
+	}
+
+	startTime := time.Now()
+	result, err := r.executor.Execute(ctx, entry.BatchJob)
+	if err != nil {
+		result = &batch.JobResult{
+			JobID:   entry.ID,
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+	if result == nil {
+		result = &batch.JobResult{JobID: entry.ID, Error: "executor returned no result"}
+	}
+	resultCopy := *result
+	resultCopy.StartTime = startTime
+	resultCopy.EndTime = time.Now()
+	resultCopy.Duration = resultCopy.EndTime.Sub(startTime)
+
+	r.observer.BatchJobCompleted(ctx, veo3.BatchJobEvent{
+		JobID:    entry.ID,
+		Type:     entry.Type,
+		Success:  resultCopy.Success,
+		Start:    startTime,
+		Duration: resultCopy.Duration,
+		Err:      resultCopy.Error,
+	})
+
+	// A cancelled runner leaves the job running so the next run requeues it
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err := r.store.Complete(entry.ID, &resultCopy); err != nil {
+		resultCopy.Success = false
+		resultCopy.Error = fmt.Sprintf("failed to record result: %v", err)
+	}
+
+	if r.onFinish != nil {
+		r.onFinish(entry, &resultCopy)
+	}
+}
@@ -0,0 +1,485 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/internal/format"
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/jasongoecke/go-veo3/pkg/templates"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+)
+
+const (
+	maxRequestBytes  = 1 << 20  // 1MB for generation requests
+	maxManifestBytes = 10 << 20 // 10MB for batch manifests
+)
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":            "ok",
+		"active_operations": len(s.manager.ListActiveOperations()),
+	})
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPISpec)
+}
+
+func (s *Server) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	var request veo3.ReferenceImageRequest
+	if !decodeBody(w, r, &request) {
+		return
+	}
+	paths := make([]*string, len(request.ReferenceImagePaths))
+	for i := range request.ReferenceImagePaths {
+		paths[i] = &request.ReferenceImagePaths[i]
+	}
+	if !s.resolveInputs(w, paths...) {
+		return
+	}
+	s.applyDefaults(&request.GenerationRequest)
+
+	var op *veo3.Operation
+	var err error
+	if len(request.ReferenceImagePaths) > 0 {
+		op, err = s.client.GenerateWithReferenceImages(r.Context(), &request)
+	} else {
+		op, err = s.client.GenerateVideo(r.Context(), &request.GenerationRequest)
+	}
+	s.respondSubmitted(w, op, err, "generate", request.Prompt, request.Model)
+}
+
+func (s *Server) handleAnimate(w http.ResponseWriter, r *http.Request) {
+	var request veo3.ImageRequest
+	if !decodeBody(w, r, &request) || !s.resolveInputs(w, &request.ImagePath) {
+		return
+	}
+	s.applyDefaults(&request.GenerationRequest)
+
+	op, err := s.client.AnimateImage(r.Context(), &request)
+	s.respondSubmitted(w, op, err, "animate", request.Prompt, request.Model)
+}
+
+func (s *Server) handleInterpolate(w http.ResponseWriter, r *http.Request) {
+	var request veo3.InterpolationRequest
+	if !decodeBody(w, r, &request) || !s.resolveInputs(w, &request.FirstFramePath, &request.LastFramePath) {
+		return
+	}
+
+	// Interpolation only supports 16:9 at 8 seconds
+	if request.AspectRatio == "" {
+		request.AspectRatio = "16:9"
+	}
+	if request.DurationSeconds == 0 {
+		request.DurationSeconds = 8
+	}
+	s.applyDefaults(&request.GenerationRequest)
+
+	op, err := s.client.InterpolateFrames(r.Context(), &request)
+	s.respondSubmitted(w, op, err, "interpolate", request.Prompt, request.Model)
+}
+
+func (s *Server) handleExtend(w http.ResponseWriter, r *http.Request) {
+	var request veo3.ExtensionRequest
+	if !decodeBody(w, r, &request) || !s.resolveInputs(w, &request.VideoPath) {
+		return
+	}
+	if request.Model == "" {
+		request.Model = s.opts.Defaults.DefaultModel
+	}
+
+	op, err := s.client.ExtendVideo(r.Context(), &request)
+	s.respondSubmitted(w, op, err, "extend", request.ExtensionPrompt, request.Model)
+}
+
+func (s *Server) handleListOperations(w http.ResponseWriter, r *http.Request) {
+	var ops []*veo3.Operation
+	if status := r.URL.Query().Get("status"); status != "" {
+		ops = s.manager.FilterOperations(veo3.OperationStatus(strings.ToUpper(status)))
+	} else {
+		ops = s.manager.ListOperations()
+	}
+
+	views := make([]*OperationRecord, 0, len(ops))
+	for _, op := range ops {
+		if view := s.view(op.ID); view != nil {
+			views = append(views, view)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"operations": views,
+		"count":      len(views),
+	})
+}
+
+// handleGetOperation serves GET /v1/operations/{name} and {name}:download.
+// Operation names contain slashes, so actions use a ":verb" suffix as in the
+// Google APIs rather than an extra path segment.
+func (s *Server) handleGetOperation(w http.ResponseWriter, r *http.Request) {
+	name, action := splitAction(r.PathValue("name"))
+
+	switch action {
+	case "":
+		view := s.view(name)
+		if view == nil {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("operation %s not found", name))
+			return
+		}
+		writeJSON(w, http.StatusOK, view)
+	case "download":
+		s.serveDownload(w, r, name)
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("unknown operation action: %s", action))
+	}
+}
+
+// handleOperationAction serves POST /v1/operations/{name}:cancel
+func (s *Server) handleOperationAction(w http.ResponseWriter, r *http.Request) {
+	name, action := splitAction(r.PathValue("name"))
+	if action != "cancel" {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("unknown operation action: %s", action))
+		return
+	}
+
+	if _, err := s.manager.GetOperation(name); err != nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+
+	if err := s.manager.CancelOperation(r.Context(), name); err != nil {
+		writeError(w, http.StatusBadGateway, "CANCEL_FAILED", err.Error())
+		return
+	}
+
+	// Cancelled operations are no longer polled, so finalize them here
+	if op, err := s.manager.GetOperation(name); err == nil {
+		s.handleUpdate(op)
+	}
+
+	writeJSON(w, http.StatusOK, s.view(name))
+}
+
+func (s *Server) serveDownload(w http.ResponseWriter, r *http.Request, name string) {
+	view := s.view(name)
+	if view == nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("operation %s not found", name))
+		return
+	}
+
+	if view.FilePath == "" {
+		if view.DownloadError != "" {
+			writeError(w, http.StatusInternalServerError, "DOWNLOAD_FAILED", view.DownloadError)
+			return
+		}
+		writeError(w, http.StatusConflict, "NOT_READY",
+			fmt.Sprintf("operation %s has no downloaded video (status: %s)", name, view.Operation.Status))
+		return
+	}
+
+	if !fileExists(view.FilePath) {
+		writeError(w, http.StatusGone, "FILE_MISSING", fmt.Sprintf("downloaded file %s no longer exists", view.FilePath))
+		return
+	}
+
+	w.Header().Set("Content-Type", "video/mp4")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filepath.Base(view.FilePath)))
+	http.ServeFile(w, r, view.FilePath)
+}
+
+func (s *Server) handleSubmitBatch(w http.ResponseWriter, r *http.Request) {
+	if s.opts.NewExecutor == nil {
+		writeError(w, http.StatusNotImplemented, "NOT_CONFIGURED", "batch processing is not enabled on this server")
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxManifestBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("failed to read manifest: %v", err))
+		return
+	}
+
+	// JSON is valid YAML, so both manifest formats are accepted
+	manifest, err := batch.ParseRemoteManifest(data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_MANIFEST", err.Error())
+		return
+	}
+	lookup := s.opts.Templates
+	if lookup == nil {
+		lookup = func(name string) (*templates.Template, error) {
+			return nil, fmt.Errorf("templates are not available on this server: %s", name)
+		}
+	}
+	if err := batch.ApplyTemplates(manifest, lookup); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_MANIFEST", err.Error())
+		return
+	}
+	if err := checkOutputs(manifest); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_MANIFEST", err.Error())
+		return
+	}
+	if err := s.resolveJobInputs(manifest); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_MANIFEST", err.Error())
+		return
+	}
+	manifest.OutputDirectory = s.opts.OutputDir
+	// Submitted manifests may ask for fewer concurrent jobs, never more
+	if manifest.Concurrency < 1 || manifest.Concurrency > s.opts.MaxConcurrency {
+		manifest.Concurrency = s.opts.MaxConcurrency
+	}
+
+	record := &BatchRecord{
+		ID:          fmt.Sprintf("batch-%d", time.Now().UnixNano()),
+		Status:      "running",
+		Jobs:        len(manifest.Jobs),
+		SubmittedAt: time.Now(),
+	}
+
+	s.mu.Lock()
+	s.batches[record.ID] = record
+	snapshot := *record
+	s.mu.Unlock()
+
+	processor := batch.NewProcessor(s.opts.NewExecutor(manifest), manifest.Concurrency)
+	processor.SetObserver(s.client.Observer())
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		results, err := processor.ProcessManifest(s.ctx, manifest)
+		summary := batch.GenerateSummary(results)
+		now := time.Now()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		record.Summary = &summary
+		record.CompletedAt = &now
+		record.Status = "completed"
+		if err != nil {
+			record.Status = "failed"
+			record.Error = err.Error()
+		}
+	}()
+
+	writeJSON(w, http.StatusAccepted, snapshot)
+}
+
+// checkOutputs rejects a submitted manifest that would write outside the
+// server's output directory: it may not choose the directory, and job outputs
+// must be relative paths inside it
+func checkOutputs(manifest *batch.BatchManifest) error {
+	if manifest.OutputDirectory != "" {
+		return fmt.Errorf("output_directory is set by the server and may not be submitted")
+	}
+	for _, job := range manifest.Jobs {
+		if !filepath.IsLocal(job.Output) {
+			return fmt.Errorf("job %s: output %s must be a relative path inside the output directory", job.ID, job.Output)
+		}
+	}
+	return nil
+}
+
+// inputOptions are the job options that name files the job reads
+var inputOptions = []string{"image", "first_frame", "last_frame", "video", "reference_images"}
+
+// resolveJobInputs resolves the files a submitted manifest's jobs read under
+// the input directory. Options may also reference another job's output,
+// which lies in the output directory.
+func (s *Server) resolveJobInputs(manifest *batch.BatchManifest) error {
+	resolve := func(job batch.BatchJob, value interface{}) (string, error) {
+		path := fmt.Sprint(value)
+		if batch.IsJobOutputRef(path) {
+			return path, nil
+		}
+		resolved, err := s.resolveInput(path)
+		if err != nil {
+			return "", fmt.Errorf("job %s: %w", job.ID, err)
+		}
+		return resolved, nil
+	}
+
+	for i, job := range manifest.Jobs {
+		// Expanded jobs may share an options map, so each gets its own
+		options := make(map[string]interface{}, len(job.Options))
+		for key, value := range job.Options {
+			options[key] = value
+		}
+		job.Options = options
+		manifest.Jobs[i] = job
+
+		for _, key := range inputOptions {
+			var err error
+			switch value := job.Options[key].(type) {
+			case nil:
+			case []interface{}:
+				paths := make([]interface{}, len(value))
+				for i, item := range value {
+					if paths[i], err = resolve(job, item); err != nil {
+						return err
+					}
+				}
+				job.Options[key] = paths
+			case []string:
+				paths := make([]string, len(value))
+				for i, item := range value {
+					if paths[i], err = resolve(job, item); err != nil {
+						return err
+					}
+				}
+				job.Options[key] = paths
+			default:
+				if job.Options[key], err = resolve(job, value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resolveInput returns a path a request reads joined to the input directory.
+// Absolute paths and paths leaving the directory are rejected.
+func (s *Server) resolveInput(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	if !filepath.IsLocal(path) {
+		return "", fmt.Errorf("input %s must be a relative path inside the input directory", path)
+	}
+	return filepath.Join(s.opts.InputDir, path), nil
+}
+
+// resolveInputs resolves request paths in place, writing an error response
+// and returning false if one is rejected
+func (s *Server) resolveInputs(w http.ResponseWriter, paths ...*string) bool {
+	for _, path := range paths {
+		resolved, err := s.resolveInput(*path)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return false
+		}
+		*path = resolved
+	}
+	return true
+}
+
+func (s *Server) handleListBatches(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	records := make([]BatchRecord, 0, len(s.batches))
+	for _, record := range s.batches {
+		records = append(records, *record)
+	}
+	s.mu.RUnlock()
+
+	// Newest first
+	sort.Slice(records, func(i, j int) bool { return records[i].SubmittedAt.After(records[j].SubmittedAt) })
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"batches": records,
+		"count":   len(records),
+	})
+}
+
+func (s *Server) handleGetBatch(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	record, ok := s.batches[r.PathValue("id")]
+	var snapshot BatchRecord
+	if ok {
+		snapshot = *record
+	}
+	s.mu.RUnlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("batch %s not found", r.PathValue("id")))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, snapshot)
+}
+
+// respondSubmitted tracks a newly submitted operation or reports the submit error
+func (s *Server) respondSubmitted(w http.ResponseWriter, op *veo3.Operation, err error, kind, prompt, model string) {
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "SUBMIT_FAILED", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, s.track(op, kind, prompt, model))
+}
+
+// applyDefaults fills unset generation parameters from the configuration
+func (s *Server) applyDefaults(request *veo3.GenerationRequest) {
+	if request.Model == "" {
+		request.Model = s.opts.Defaults.DefaultModel
+	}
+	if request.Resolution == "" {
+		request.Resolution = s.opts.Defaults.DefaultResolution
+	}
+	if request.AspectRatio == "" {
+		request.AspectRatio = s.opts.Defaults.DefaultAspectRatio
+	}
+	if request.DurationSeconds == 0 {
+		request.DurationSeconds = s.opts.Defaults.DefaultDuration
+	}
+}
+
+// splitAction splits "operations/abc:cancel" into its name and action
+func splitAction(path string) (string, string) {
+	if i := strings.LastIndex(path, ":"); i > strings.LastIndex(path, "/") {
+		return path[:i], path[i+1:]
+	}
+	return path, ""
+}
+
+// decodeBody decodes a JSON request body, writing an error response on failure
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, "TOO_LARGE", "request body too large")
+			return false
+		}
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("invalid JSON body: %v", err))
+		return false
+	}
+
+	return true
+}
+
+// writeJSON writes a successful response using the CLI's JSON envelope
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(format.JSONOutput{Success: true, Data: data})
+}
+
+// writeError writes an error response using the CLI's JSON envelope
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(format.JSONOutput{
+		Success: false,
+		Error:   &format.JSONError{Code: code, Message: message},
+	})
+}
+
+// fileExists reports whether a regular file exists at path
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,332 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	_ "embed"
+	"errors"
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/jasongoecke/go-veo3/pkg/config"
+	"github.com/jasongoecke/go-veo3/pkg/hooks"
+	"github.com/jasongoecke/go-veo3/pkg/operations"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+	"github.com/jasongoecke/go-veo3/pkg/webhooks"
+)
+
+// ShutdownTimeout bounds how long in-flight requests may take to finish on shutdown
+const ShutdownTimeout = 30 * time.Second
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+// Options configures a Server
+type Options struct {
+	Token          string                                                // Bearer token required on API routes; empty disables auth
+	OutputDir      string                                                // Directory completed videos are downloaded to
+	InputDir       string                                                // Directory request image and video paths are resolved under
+	PollInterval   time.Duration                                         // Interval between background status polls
+	Defaults       *config.Configuration                                 // Defaults applied to submitted requests
+	NewExecutor    func(manifest *batch.BatchManifest) batch.JobExecutor // Builds the executor for batch submissions
+	Templates      batch.TemplateLookup                                  // Resolves templates named by batch jobs; nil rejects them
+	MaxConcurrency int                                                   // Most jobs of a submitted batch run at once
+	Notifier       *webhooks.Notifier
+	Hooks          *hooks.Runner
+	Metrics        http.Handler // Served at GET /metrics when set
+	DebugVars      bool         // Serve expvar variables at GET /debug/vars
+}
+
+// OperationRecord is the server's view of a submitted operation
+type OperationRecord struct {
+	Operation     *veo3.Operation `json:"operation"`
+	Kind          string          `json:"kind"`
+	SubmittedAt   time.Time       `json:"submitted_at"`
+	FilePath      string          `json:"file_path,omitempty"`
+	DownloadURL   string          `json:"download_url,omitempty"`
+	DownloadError string          `json:"download_error,omitempty"`
+	Hooks         []hooks.Result  `json:"hooks,omitempty"`
+
+	prompt    string
+	model     string
+	finalized bool
+}
+
+// BatchRecord tracks a submitted batch manifest
+type BatchRecord struct {
+	ID          string              `json:"id"`
+	Status      string              `json:"status"` // "running", "completed" or "failed"
+	Jobs        int                 `json:"jobs"`
+	SubmittedAt time.Time           `json:"submitted_at"`
+	CompletedAt *time.Time          `json:"completed_at,omitempty"`
+	Summary     *batch.BatchSummary `json:"summary,omitempty"`
+	Error       string              `json:"error,omitempty"`
+}
+
+// Server exposes the Veo client, operation store and batch processor over HTTP
+type Server struct {
+	client     *veo3.Client
+	manager    *operations.Manager
+	poller     *operations.Poller
+	downloader *operations.Downloader
+	opts       Options
+
+	mu      sync.RWMutex
+	records map[string]*OperationRecord
+	batches map[string]*BatchRecord
+
+	// ctx is cancelled on shutdown to stop the poller and in-flight batches
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a server backed by the given client
+func New(client *veo3.Client, opts Options) *Server {
+	if opts.OutputDir == "" {
+		opts.OutputDir = "."
+	}
+	if opts.InputDir == "" {
+		opts.InputDir = "."
+	}
+	if opts.MaxConcurrency <= 0 {
+		opts.MaxConcurrency = config.DefaultConcurrency
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Duration(config.DefaultPollInterval) * time.Second
+	}
+	if opts.Defaults == nil {
+		opts.Defaults = &config.Configuration{
+			DefaultModel:       config.DefaultModel,
+			DefaultResolution:  config.DefaultResolution,
+			DefaultAspectRatio: config.DefaultAspectRatio,
+			DefaultDuration:    config.DefaultDuration,
+		}
+	}
+
+	manager := operations.NewManager(client)
+	poller := operations.NewPoller(client, manager)
+	poller.SetPollingConfig(opts.PollInterval, 5*time.Minute, 1.5, 10)
+
+	downloader := operations.NewDownloaderForClient(client, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Server{
+		client:     client,
+		manager:    manager,
+		poller:     poller,
+		downloader: downloader,
+		opts:       opts,
+		records:    make(map[string]*OperationRecord),
+		batches:    make(map[string]*BatchRecord),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Handler returns the HTTP handler for the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /openapi.json", s.handleOpenAPI)
+
+	mux.HandleFunc("POST /v1/generate", s.handleGenerate)
+	mux.HandleFunc("POST /v1/animate", s.handleAnimate)
+	mux.HandleFunc("POST /v1/interpolate", s.handleInterpolate)
+	mux.HandleFunc("POST /v1/extend", s.handleExtend)
+
+	mux.HandleFunc("GET /v1/operations", s.handleListOperations)
+	mux.HandleFunc("GET /v1/operations/{name...}", s.handleGetOperation)
+	mux.HandleFunc("POST /v1/operations/{name...}", s.handleOperationAction)
+
+	mux.HandleFunc("POST /v1/batches", s.handleSubmitBatch)
+	mux.HandleFunc("GET /v1/batches", s.handleListBatches)
+	mux.HandleFunc("GET /v1/batches/{id}", s.handleGetBatch)
+
+	if s.opts.Metrics != nil {
+		mux.Handle("GET /metrics", s.opts.Metrics)
+	}
+	if s.opts.DebugVars {
+		mux.Handle("GET /debug/vars", expvar.Handler())
+	}
+
+	return s.authenticate(mux)
+}
+
+// ListenAndServe listens on addr and serves until ctx is cancelled
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve accepts connections on listener and runs the background poller until
+// ctx is cancelled, then shuts down gracefully
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	httpServer := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.poller.StartContinuousPolling(s.ctx, s.handleUpdate)
+	}()
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- httpServer.Serve(listener)
+	}()
+
+	var serveErr error
+	select {
+	case err := <-errChan:
+		if !errors.Is(err, http.ErrServerClosed) {
+			serveErr = err
+		}
+	case <-ctx.Done():
+	}
+
+	// Stop accepting requests, let in-flight ones finish, then stop background work
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	shutdownErr := httpServer.Shutdown(shutdownCtx)
+
+	s.cancel()
+	s.wg.Wait()
+
+	if serveErr != nil {
+		return serveErr
+	}
+	return shutdownErr
+}
+
+// authenticate enforces the bearer token on API routes
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.opts.Token == "" {
+		return next
+	}
+
+	expected := []byte("Bearer " + s.opts.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" || r.URL.Path == "/openapi.json" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="veo3"`)
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "missing or invalid bearer token")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// track registers a newly submitted operation with the store
+func (s *Server) track(op *veo3.Operation, kind, prompt, model string) *OperationRecord {
+	s.manager.AddOperation(op)
+
+	record := &OperationRecord{
+		Operation:   op,
+		Kind:        kind,
+		SubmittedAt: time.Now(),
+		prompt:      prompt,
+		model:       model,
+	}
+
+	s.mu.Lock()
+	s.records[op.ID] = record
+	s.mu.Unlock()
+
+	return s.view(op.ID)
+}
+
+// view returns a snapshot of an operation record with the latest status
+func (s *Server) view(operationID string) *OperationRecord {
+	op, err := s.manager.GetOperation(operationID)
+	if err != nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, ok := s.records[operationID]
+	if !ok {
+		return &OperationRecord{Operation: op}
+	}
+
+	snapshot := *record
+	snapshot.Operation = op
+	if record.FilePath != "" {
+		snapshot.DownloadURL = "/v1/operations/" + operationID + ":download"
+	}
+	return &snapshot
+}
+
+// handleUpdate is called by the background poller for every status update
+func (s *Server) handleUpdate(op *veo3.Operation) {
+	switch op.Status {
+	case veo3.StatusDone, veo3.StatusFailed, veo3.StatusCancelled:
+	default:
+		return
+	}
+
+	s.mu.Lock()
+	record, ok := s.records[op.ID]
+	if !ok || record.finalized {
+		s.mu.Unlock()
+		return
+	}
+	record.finalized = true
+	s.mu.Unlock()
+
+	s.finalize(op, record)
+}
+
+// finalize downloads a completed video, runs hooks and notifies webhooks
+func (s *Server) finalize(op *veo3.Operation, record *OperationRecord) {
+	var filePath, downloadErr string
+	var hookResults []hooks.Result
+
+	if op.Status == veo3.StatusDone {
+		shortID := op.ID[strings.LastIndex(op.ID, "/")+1:]
+		outputPath := filepath.Join(s.opts.OutputDir, shortID+".mp4")
+
+		video, err := s.downloader.DownloadVideo(s.ctx, op, outputPath)
+		if err != nil {
+			downloadErr = err.Error()
+		} else {
+			filePath = video.FilePath
+			video.Prompt = record.prompt
+			video.Model = record.model
+
+			hookResults, err = s.opts.Hooks.Run(s.ctx, video)
+			if err != nil {
+				downloadErr = err.Error()
+			}
+		}
+	}
+
+	s.mu.Lock()
+	record.FilePath = filePath
+	record.DownloadError = downloadErr
+	record.Hooks = hookResults
+	s.mu.Unlock()
+
+	_ = s.opts.Notifier.Notify(s.ctx, webhooks.Notification{Operation: op, FilePath: filePath})
+}
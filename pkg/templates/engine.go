@@ -0,0 +1,563 @@
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The template language is a small, safe subset of text/template that keeps
+// the original {{name}} syntax:
+//
+//	{{subject}}                      variable, required unless it has a default
+//	{{season | default "autumn"}}    filters are applied left to right
+//	{{if mood}}, {{mood}}{{else}}, calm{{end}}
+//	{{if not night}}daylight{{end}}
+//	{{range styles}}[{{. | upper}}]{{end}}
+//
+// List variables are comma-separated values such as "noir, neon".
+
+var identPattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// keywords cannot be used as variable names
+var keywords = map[string]bool{"if": true, "else": true, "end": true, "range": true, "not": true}
+
+// filter transforms a value; args are the string literals after its name
+type filter struct {
+	minArgs, maxArgs int
+	apply            func(v value, args []string) value
+}
+
+var filters = map[string]filter{
+	"default": {1, 1, func(v value, args []string) value {
+		if !v.set || v.empty() {
+			return value{set: true, s: args[0]}
+		}
+		return v
+	}},
+	"upper":      {0, 0, mapStrings(strings.ToUpper)},
+	"lower":      {0, 0, mapStrings(strings.ToLower)},
+	"title":      {0, 0, mapStrings(titleCase)},
+	"capitalize": {0, 0, mapStrings(capitalize)},
+	"trim":       {0, 0, mapStrings(strings.TrimSpace)},
+	"join": {0, 1, func(v value, args []string) value {
+		if !v.set {
+			return v
+		}
+		sep := ", "
+		if len(args) > 0 {
+			sep = args[0]
+		}
+		return value{set: true, s: strings.Join(v.items(), sep)}
+	}},
+}
+
+// FilterNames returns the available filter names in sorted order
+func FilterNames() []string {
+	names := make([]string, 0, len(filters))
+	for name := range filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// value is a string or list during rendering; set is false for a variable
+// that was not provided
+type value struct {
+	set    bool
+	s      string
+	list   []string
+	isList bool
+}
+
+func (v value) empty() bool {
+	if v.isList {
+		return len(v.list) == 0
+	}
+	return strings.TrimSpace(v.s) == ""
+}
+
+// items returns the value as a list, splitting strings on commas
+func (v value) items() []string {
+	if v.isList {
+		return v.list
+	}
+	var items []string
+	for _, item := range strings.Split(v.s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (v value) String() string {
+	if v.isList {
+		return strings.Join(v.list, ", ")
+	}
+	return v.s
+}
+
+func mapStrings(fn func(string) string) func(value, []string) value {
+	return func(v value, _ []string) value {
+		if !v.set {
+			return v
+		}
+		if v.isList {
+			list := make([]string, len(v.list))
+			for i, item := range v.list {
+				list[i] = fn(item)
+			}
+			return value{set: true, list: list, isList: true}
+		}
+		return value{set: true, s: fn(v.s)}
+	}
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		words[i] = capitalize(strings.ToLower(word))
+	}
+	return strings.Join(words, " ")
+}
+
+// Template syntax tree
+
+type node interface{}
+
+type textNode struct{ text string }
+
+type outputNode struct{ pipe *pipeline }
+
+type ifNode struct {
+	cond      *pipeline
+	negate    bool
+	then      []node
+	otherwise []node
+}
+
+type rangeNode struct {
+	list      *pipeline
+	body      []node
+	otherwise []node
+}
+
+type pipeline struct {
+	// variable is the variable name, "." for the range item, or "" for a literal
+	variable string
+	literal  string
+	filters  []filterCall
+}
+
+type filterCall struct {
+	name string
+	args []string
+}
+
+func (p *pipeline) hasDefault() bool {
+	for _, f := range p.filters {
+		if f.name == "default" {
+			return true
+		}
+	}
+	return false
+}
+
+// parse compiles a template into a syntax tree
+func parse(template string) ([]node, error) {
+	type frame struct {
+		action string
+		block  node
+		inElse bool
+	}
+
+	var (
+		root  []node
+		stack []*frame
+	)
+	current := func() *[]node {
+		if len(stack) == 0 {
+			return &root
+		}
+		top := stack[len(stack)-1]
+		switch block := top.block.(type) {
+		case *ifNode:
+			if top.inElse {
+				return &block.otherwise
+			}
+			return &block.then
+		case *rangeNode:
+			if top.inElse {
+				return &block.otherwise
+			}
+			return &block.body
+		}
+		return &root
+	}
+
+	rest := template
+	for rest != "" {
+		open := strings.Index(rest, "{{")
+		if open < 0 {
+			if strings.Contains(rest, "}}") {
+				return nil, fmt.Errorf("unbalanced braces in template")
+			}
+			*current() = append(*current(), &textNode{text: rest})
+			break
+		}
+		if strings.Contains(rest[:open], "}}") {
+			return nil, fmt.Errorf("unbalanced braces in template")
+		}
+		if open > 0 {
+			*current() = append(*current(), &textNode{text: rest[:open]})
+		}
+
+		rest = rest[open+2:]
+		end := strings.Index(rest, "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("unbalanced braces in template")
+		}
+		action := rest[:end]
+		rest = rest[end+2:]
+		if strings.Contains(action, "{{") || strings.HasPrefix(action, "{") || strings.HasPrefix(rest, "}") {
+			return nil, fmt.Errorf("nested braces not allowed in template")
+		}
+
+		tokens, err := tokenize(action)
+		if err != nil {
+			return nil, fmt.Errorf("invalid {{%s}}: %w", action, err)
+		}
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("empty variable name in template")
+		}
+
+		switch tokens[0] {
+		case "if":
+			args := tokens[1:]
+			negate := len(args) > 0 && args[0] == "not"
+			if negate {
+				args = args[1:]
+			}
+			cond, err := parsePipeline(args)
+			if err != nil {
+				return nil, fmt.Errorf("invalid {{%s}}: %w", action, err)
+			}
+			block := &ifNode{cond: cond, negate: negate}
+			*current() = append(*current(), block)
+			stack = append(stack, &frame{action: action, block: block})
+		case "range":
+			list, err := parsePipeline(tokens[1:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid {{%s}}: %w", action, err)
+			}
+			block := &rangeNode{list: list}
+			*current() = append(*current(), block)
+			stack = append(stack, &frame{action: action, block: block})
+		case "else":
+			if len(tokens) > 1 {
+				return nil, fmt.Errorf("invalid {{%s}}: else takes no arguments", action)
+			}
+			if len(stack) == 0 || stack[len(stack)-1].inElse {
+				return nil, fmt.Errorf("unexpected {{else}}")
+			}
+			stack[len(stack)-1].inElse = true
+		case "end":
+			if len(tokens) > 1 {
+				return nil, fmt.Errorf("invalid {{%s}}: end takes no arguments", action)
+			}
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("unexpected {{end}}")
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			pipe, err := parsePipeline(tokens)
+			if err != nil {
+				return nil, fmt.Errorf("invalid {{%s}}: %w", action, err)
+			}
+			*current() = append(*current(), &outputNode{pipe: pipe})
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("missing {{end}} for {{%s}}", strings.TrimSpace(stack[len(stack)-1].action))
+	}
+	return root, nil
+}
+
+// tokenize splits an action into identifiers, ".", "|" and quoted strings.
+// Quoted strings keep their quotes so they can be told apart from names.
+func tokenize(action string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(action); {
+		c := action[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '|' || c == '.':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(action) && action[j] != '"' {
+				if action[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(action) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, action[i:j+1])
+			i = j + 1
+		default:
+			j := i
+			for j < len(action) && (action[j] == '_' || action[j] >= 'a' && action[j] <= 'z' ||
+				action[j] >= 'A' && action[j] <= 'Z' || action[j] >= '0' && action[j] <= '9') {
+				j++
+			}
+			if j == i {
+				return nil, fmt.Errorf("invalid variable name: %s (use only letters, numbers, and underscores)", strings.TrimSpace(action))
+			}
+			tokens = append(tokens, action[i:j])
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+// parsePipeline parses "operand | filter args... | filter..."
+func parsePipeline(tokens []string) (*pipeline, error) {
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("missing variable name")
+	}
+
+	pipe := &pipeline{}
+	switch operand := tokens[0]; {
+	case operand == ".":
+		pipe.variable = "."
+	case strings.HasPrefix(operand, `"`):
+		literal, err := strconv.Unquote(operand)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string %s", operand)
+		}
+		pipe.literal = literal
+	case operand == "|":
+		return nil, fmt.Errorf("missing variable name before |")
+	case keywords[operand]:
+		return nil, fmt.Errorf("%q is a keyword and cannot be used as a variable name", operand)
+	case identPattern.MatchString(operand):
+		pipe.variable = operand
+	default:
+		return nil, fmt.Errorf("invalid variable name: %s", operand)
+	}
+
+	tokens = tokens[1:]
+	for len(tokens) > 0 {
+		if tokens[0] != "|" {
+			return nil, fmt.Errorf("unexpected %s (filters are separated by |)", tokens[0])
+		}
+		if len(tokens) < 2 || tokens[1] == "|" {
+			return nil, fmt.Errorf("missing filter name after |")
+		}
+		call := filterCall{name: tokens[1]}
+		def, ok := filters[call.name]
+		if !ok {
+			return nil, fmt.Errorf("unknown filter %q (available: %s)", call.name, strings.Join(FilterNames(), ", "))
+		}
+		tokens = tokens[2:]
+		for len(tokens) > 0 && tokens[0] != "|" {
+			arg, err := strconv.Unquote(tokens[0])
+			if err != nil || !strings.HasPrefix(tokens[0], `"`) {
+				return nil, fmt.Errorf("filter %s: arguments must be quoted strings, got %s", call.name, tokens[0])
+			}
+			call.args = append(call.args, arg)
+			tokens = tokens[1:]
+		}
+		if len(call.args) < def.minArgs || len(call.args) > def.maxArgs {
+			return nil, fmt.Errorf("filter %s takes %s", call.name, argCount(def))
+		}
+		pipe.filters = append(pipe.filters, call)
+	}
+
+	return pipe, nil
+}
+
+func argCount(def filter) string {
+	switch {
+	case def.maxArgs == 0:
+		return "no arguments"
+	case def.minArgs == def.maxArgs:
+		return fmt.Sprintf("%d argument(s)", def.minArgs)
+	default:
+		return fmt.Sprintf("%d to %d arguments", def.minArgs, def.maxArgs)
+	}
+}
+
+// renderer evaluates a syntax tree against the provided variables
+type renderer struct {
+	variables map[string]string
+	items     []string // range items, innermost last
+}
+
+func (r *renderer) render(nodes []node, out *strings.Builder) error {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *textNode:
+			out.WriteString(n.text)
+		case *outputNode:
+			v, err := r.eval(n.pipe)
+			if err != nil {
+				return err
+			}
+			if !v.set {
+				return fmt.Errorf("missing required variable: %s", n.pipe.variable)
+			}
+			out.WriteString(v.String())
+		case *ifNode:
+			v, err := r.eval(n.cond)
+			if err != nil {
+				return err
+			}
+			branch := n.otherwise
+			if (v.set && !v.empty()) != n.negate {
+				branch = n.then
+			}
+			if err := r.render(branch, out); err != nil {
+				return err
+			}
+		case *rangeNode:
+			v, err := r.eval(n.list)
+			if err != nil {
+				return err
+			}
+			var items []string
+			if v.set {
+				items = v.items()
+			}
+			if len(items) == 0 {
+				if err := r.render(n.otherwise, out); err != nil {
+					return err
+				}
+				continue
+			}
+			for _, item := range items {
+				r.items = append(r.items, item)
+				err := r.render(n.body, out)
+				r.items = r.items[:len(r.items)-1]
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (r *renderer) eval(pipe *pipeline) (value, error) {
+	var v value
+	switch pipe.variable {
+	case "":
+		v = value{set: true, s: pipe.literal}
+	case ".":
+		if len(r.items) == 0 {
+			return value{}, fmt.Errorf("{{.}} can only be used inside {{range}}")
+		}
+		v = value{set: true, s: r.items[len(r.items)-1]}
+	default:
+		if s, ok := r.variables[pipe.variable]; ok {
+			v = value{set: true, s: s}
+		}
+	}
+
+	for _, call := range pipe.filters {
+		v = filters[call.name].apply(v, call.args)
+	}
+	return v, nil
+}
+
+// Variable describes how a template uses a variable
+type Variable struct {
+	Name string `json:"name"`
+	// Required is true when the variable is used outside any {{if}} or
+	// {{range}} block without a default
+	Required   bool   `json:"required"`
+	HasDefault bool   `json:"has_default,omitempty"`
+	Default    string `json:"default,omitempty"`
+	// List is true when the variable is iterated or joined
+	List bool `json:"list,omitempty"`
+}
+
+// AnalyzeVariables parses a template and describes its variables in order of
+// first use
+func AnalyzeVariables(template string) ([]Variable, error) {
+	nodes, err := parse(template)
+	if err != nil {
+		return nil, err
+	}
+
+	var vars []Variable
+	index := make(map[string]int)
+	record := func(pipe *pipeline, conditional, list bool) {
+		if pipe.variable == "" || pipe.variable == "." {
+			return
+		}
+		i, ok := index[pipe.variable]
+		if !ok {
+			i = len(vars)
+			index[pipe.variable] = i
+			vars = append(vars, Variable{Name: pipe.variable})
+		}
+		v := &vars[i]
+		for _, call := range pipe.filters {
+			switch call.name {
+			case "default":
+				if !v.HasDefault {
+					v.HasDefault, v.Default = true, call.args[0]
+				}
+			case "join":
+				v.List = true
+			}
+		}
+		if list {
+			v.List = true
+		}
+		if !conditional && !pipe.hasDefault() {
+			v.Required = true
+		}
+	}
+
+	var walk func(nodes []node, conditional bool)
+	walk = func(nodes []node, conditional bool) {
+		for _, n := range nodes {
+			switch n := n.(type) {
+			case *outputNode:
+				record(n.pipe, conditional, false)
+			case *ifNode:
+				record(n.cond, true, false)
+				walk(n.then, true)
+				walk(n.otherwise, true)
+			case *rangeNode:
+				record(n.list, true, true)
+				walk(n.body, true)
+				walk(n.otherwise, true)
+			}
+		}
+	}
+	walk(nodes, false)
+
+	return vars, nil
+}
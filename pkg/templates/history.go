@@ -0,0 +1,210 @@
+package templates
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TemplateVersion is an earlier revision of a template
+type TemplateVersion struct {
+	Version     int       `yaml:"version" json:"version"`
+	Prompt      string    `yaml:"prompt" json:"prompt"`
+	Description string    `yaml:"description,omitempty" json:"description,omitempty"`
+	Tags        []string  `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Preset      *Preset   `yaml:"preset,omitempty" json:"preset,omitempty"`
+	SavedAt     time.Time `yaml:"saved_at" json:"saved_at"`
+}
+
+// ParseRef splits a template reference such as "promo@4" or "promo@v4" into
+// its name and version. The version is 0 when the reference has none.
+func ParseRef(ref string) (string, int, error) {
+	name, version, found := strings.Cut(ref, "@")
+	if !found {
+		return ref, 0, nil
+	}
+	n, err := ParseVersion(version)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid template reference %q: %w", ref, err)
+	}
+	return name, n, nil
+}
+
+// ParseVersion parses a version number written as "4" or "v4"
+func ParseVersion(version string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(version), "v"))
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid version %q (expected a number such as 3 or v3)", version)
+	}
+	return n, nil
+}
+
+// CurrentVersion returns the template's version number. Templates saved
+// before versioning are version 1.
+func (t *Template) CurrentVersion() int {
+	if t.Version < 1 {
+		return 1
+	}
+	return t.Version
+}
+
+// Ref returns the template's name pinned to its version, e.g. "promo@4"
+func (t *Template) Ref() string {
+	return fmt.Sprintf("%s@%d", t.Name, t.CurrentVersion())
+}
+
+// Versions returns every revision of the template, oldest first, ending with
+// the current one
+func (t *Template) Versions() []TemplateVersion {
+	versions := append([]TemplateVersion(nil), t.History...)
+	return append(versions, t.snapshot())
+}
+
+// At returns the template as it was at the given version. The result has no
+// history.
+func (t *Template) At(version int) (*Template, error) {
+	for _, v := range t.Versions() {
+		if v.Version == version {
+			return &Template{
+				Name:        t.Name,
+				Prompt:      v.Prompt,
+				Description: v.Description,
+				Tags:        v.Tags,
+				Preset:      v.Preset,
+				Version:     v.Version,
+				CreatedAt:   t.CreatedAt,
+				UpdatedAt:   v.SavedAt,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("template %s has no version %d (versions 1-%d)", t.Name, version, t.CurrentVersion())
+}
+
+// snapshot records the template's current content as a version
+func (t *Template) snapshot() TemplateVersion {
+	savedAt := t.UpdatedAt
+	if savedAt.IsZero() {
+		savedAt = t.CreatedAt
+	}
+	return TemplateVersion{
+		Version:     t.CurrentVersion(),
+		Prompt:      t.Prompt,
+		Description: t.Description,
+		Tags:        t.Tags,
+		Preset:      t.Preset,
+		SavedAt:     savedAt,
+	}
+}
+
+// sameContent reports whether two templates have the same prompt, description,
+// tags and preset
+func (t *Template) sameContent(other *Template) bool {
+	a, b := t.snapshot(), other.snapshot()
+	a.Version, b.Version = 0, 0
+	a.SavedAt, b.SavedAt = time.Time{}, time.Time{}
+	if len(a.Tags) == 0 && len(b.Tags) == 0 {
+		a.Tags, b.Tags = nil, nil
+	}
+	if a.Preset.IsEmpty() && b.Preset.IsEmpty() {
+		a.Preset, b.Preset = nil, nil
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// DiffLine is one line of a template diff. Op is ' ' for unchanged lines, '-'
+// for lines only in the older version and '+' for lines only in the newer one.
+type DiffLine struct {
+	Op   byte
+	Text string
+}
+
+// String formats the line like a unified diff
+func (l DiffLine) String() string {
+	return string(l.Op) + " " + l.Text
+}
+
+// Diff compares two versions of a template field by field. Multi-line prompts
+// are compared line by line.
+func Diff(from, to *Template) []DiffLine {
+	return diffLines(from.describe(), to.describe())
+}
+
+// describe renders the template's content as "field: value" lines
+func (t *Template) describe() []string {
+	var lines []string
+	promptLines := strings.Split(t.Prompt, "\n")
+	if len(promptLines) == 1 {
+		lines = append(lines, "prompt: "+t.Prompt)
+	} else {
+		lines = append(lines, "prompt:")
+		for _, line := range promptLines {
+			lines = append(lines, "  "+line)
+		}
+	}
+
+	add := func(field, value string) {
+		if value != "" {
+			lines = append(lines, field+": "+value)
+		}
+	}
+	add("description", t.Description)
+	add("tags", strings.Join(t.Tags, ", "))
+	if p := t.Preset; !p.IsEmpty() {
+		add("type", p.Type)
+		add("model", p.Model)
+		add("resolution", p.Resolution)
+		add("aspect_ratio", p.AspectRatio)
+		if p.Duration != 0 {
+			add("duration", strconv.Itoa(p.Duration))
+		}
+		add("negative_prompt", p.NegativePrompt)
+		add("image", p.Image)
+		add("last_frame", p.LastFrame)
+		add("reference_images", strings.Join(p.ReferenceImages, ", "))
+	}
+	return lines
+}
+
+// diffLines computes a line diff from the longest common subsequence
+func diffLines(a, b []string) []DiffLine {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, DiffLine{Op: ' ', Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, DiffLine{Op: '-', Text: a[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: '+', Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, DiffLine{Op: '-', Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, DiffLine{Op: '+', Text: b[j]})
+	}
+	return diff
+}
@@ -15,8 +15,12 @@ type Template struct {
 	Prompt      string    `yaml:"prompt" json:"prompt"`
 	Description string    `yaml:"description,omitempty" json:"description,omitempty"`
 	Tags        []string  `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Preset      *Preset   `yaml:"preset,omitempty" json:"preset,omitempty"`
+	Version     int       `yaml:"version,omitempty" json:"version,omitempty"`
 	CreatedAt   time.Time `yaml:"created_at" json:"created_at"`
 	UpdatedAt   time.Time `yaml:"updated_at" json:"updated_at"`
+	// History holds the earlier versions, oldest first
+	History []TemplateVersion `yaml:"history,omitempty" json:"history,omitempty"`
 }
 
 // Manager handles template storage and retrieval
@@ -49,25 +53,43 @@ func NewManager(configDir string) (*Manager, error) {
 	return m, nil
 }
 
-// Save saves a template
+// Save saves a template. Saving different content under an existing name
+// keeps the previous content as an earlier version.
 func (m *Manager) Save(template *Template) error {
 	if template.Name == "" {
 		return fmt.Errorf("template name cannot be empty")
 	}
 
-	if err := ValidateTemplate(template.Prompt); err != nil {
+	if err := template.Validate(); err != nil {
 		return fmt.Errorf("invalid template: %w", err)
 	}
 
+	m.put(template)
+
+	return m.save()
+}
+
+// put stores a template, versioning it against the one it replaces
+func (m *Manager) put(template *Template) {
 	now := time.Now()
+	if existing, ok := m.templates[template.Name]; ok && existing != template {
+		if existing.sameContent(template) {
+			template.Version, template.History = existing.Version, existing.History
+		} else {
+			template.History = append(append([]TemplateVersion(nil), existing.History...), existing.snapshot())
+			template.Version = existing.CurrentVersion() + 1
+		}
+		template.CreatedAt = existing.CreatedAt
+	} else if template.Version < 1 {
+		template.Version = 1
+	}
+
 	if template.CreatedAt.IsZero() {
 		template.CreatedAt = now
 	}
 	template.UpdatedAt = now
 
 	m.templates[template.Name] = template
-
-	return m.save()
 }
 
 // Get retrieves a template by name
@@ -79,6 +101,50 @@ func (m *Manager) Get(name string) (*Template, error) {
 	return template, nil
 }
 
+// Resolve returns the template for a reference such as "promo" (the current
+// version) or "promo@4" (version 4)
+func (m *Manager) Resolve(ref string) (*Template, error) {
+	name, version, err := ParseRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := m.Get(name)
+	if err != nil || version == 0 {
+		return template, err
+	}
+	return template.At(version)
+}
+
+// Rollback saves the content of an earlier version as a new version, so the
+// history is never rewritten
+func (m *Manager) Rollback(name string, version int) (*Template, error) {
+	current, err := m.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if version == current.CurrentVersion() {
+		return nil, fmt.Errorf("template %s is already at version %d", name, version)
+	}
+
+	old, err := current.At(version)
+	if err != nil {
+		return nil, err
+	}
+
+	restored := &Template{
+		Name:        name,
+		Prompt:      old.Prompt,
+		Description: old.Description,
+		Tags:        old.Tags,
+		Preset:      old.Preset,
+	}
+	if err := m.Save(restored); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
 // List returns all templates
 func (m *Manager) List() []*Template {
 	templates := make([]*Template, 0, len(m.templates))
@@ -128,12 +194,14 @@ func (m *Manager) Import(path string) error {
 		return fmt.Errorf("failed to parse templates file: %w", err)
 	}
 
-	// Validate and import each template
+	// Validate and import each template; existing templates get a new version
 	for i, template := range collection.Templates {
-		if err := ValidateTemplate(template.Prompt); err != nil {
+		if err := template.Validate(); err != nil {
 			return fmt.Errorf("invalid template at index %d (%s): %w", i, template.Name, err)
 		}
-		m.templates[template.Name] = &collection.Templates[i]
+	}
+	for i := range collection.Templates {
+		m.put(&collection.Templates[i])
 	}
 
 	return m.save()
@@ -183,11 +251,29 @@ func (m *Manager) save() error {
 	return os.WriteFile(m.templatesPath, data, 0600)
 }
 
-// Variables returns the variables required by a template
+// Validate checks the prompt and preset. The prompt may be empty only when
+// the template carries a preset, since animation and interpolation prompts
+// are optional.
+func (t *Template) Validate() error {
+	if t.Prompt != "" || t.Preset.IsEmpty() {
+		if err := ValidateTemplate(t.Prompt); err != nil {
+			return err
+		}
+	}
+	return t.Preset.Validate()
+}
+
+// Variables returns the variables used by a template
 func (t *Template) Variables() []string {
 	return ExtractVariables(t.Prompt)
 }
 
+// VariableInfo describes each variable of a template: whether it is
+// required, its default and whether it is a list
+func (t *Template) VariableInfo() ([]Variable, error) {
+	return AnalyzeVariables(t.Prompt)
+}
+
 // Render renders the template with provided variables
 func (t *Template) Render(variables map[string]string) (string, error) {
 	return SubstituteVariables(t.Prompt, variables)
@@ -6,47 +6,73 @@ import (
 	"strings"
 )
 
-// ExtractVariables extracts variable names from a template string
-// Variables are in the format {{variable_name}}
+// ExtractVariables extracts variable names from a template string, including
+// optional ones and those only used in conditions. Variables are in the
+// format {{variable_name}}, optionally followed by filters.
 func ExtractVariables(template string) []string {
-	re := regexp.MustCompile(`\{\{([a-zA-Z0-9_]+)\}\}`)
+	vars, err := AnalyzeVariables(template)
+	if err != nil {
+		return extractPlainVariables(template)
+	}
+
+	result := make([]string, 0, len(vars))
+	for _, v := range vars {
+		result = append(result, v.Name)
+	}
+	return result
+}
+
+// extractPlainVariables finds {{name}} placeholders in a template that does
+// not parse
+func extractPlainVariables(template string) []string {
+	re := regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_]+)\s*\}\}`)
 	matches := re.FindAllStringSubmatch(template, -1)
 
 	// Use map to deduplicate
-	vars := make(map[string]bool)
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(matches))
 	for _, match := range matches {
-		if len(match) > 1 {
-			vars[match[1]] = true
+		if len(match) > 1 && !seen[match[1]] && !keywords[match[1]] {
+			seen[match[1]] = true
+			result = append(result, match[1])
 		}
 	}
 
-	// Convert to slice
-	result := make([]string, 0, len(vars))
-	for v := range vars {
-		result = append(result, v)
-	}
-
 	return result
 }
 
-// SubstituteVariables replaces variables in a template with provided values
-func SubstituteVariables(template string, variables map[string]string) (string, error) {
-	// Check if all required variables are provided
-	requiredVars := ExtractVariables(template)
-	for _, v := range requiredVars {
-		if _, ok := variables[v]; !ok {
-			return "", fmt.Errorf("missing required variable: %s", v)
+// RequiredVariables returns the variables that must be provided to render a
+// template: those used outside conditional sections without a default
+func RequiredVariables(template string) ([]string, error) {
+	vars, err := AnalyzeVariables(template)
+	if err != nil {
+		return nil, err
+	}
+
+	var required []string
+	for _, v := range vars {
+		if v.Required {
+			required = append(required, v.Name)
 		}
 	}
+	return required, nil
+}
 
-	// Perform substitution
-	result := template
-	for key, value := range variables {
-		placeholder := fmt.Sprintf("{{%s}}", key)
-		result = strings.ReplaceAll(result, placeholder, value)
+// SubstituteVariables renders a template with the provided values. Variables
+// without a value are an error unless they have a default or sit in a
+// section that is not rendered. Extra variables are ignored.
+func SubstituteVariables(template string, variables map[string]string) (string, error) {
+	nodes, err := parse(template)
+	if err != nil {
+		return "", err
 	}
 
-	return result, nil
+	var out strings.Builder
+	r := &renderer{variables: variables}
+	if err := r.render(nodes, &out); err != nil {
+		return "", err
+	}
+	return out.String(), nil
 }
 
 // ValidateTemplate validates a template string
@@ -55,37 +81,6 @@ func ValidateTemplate(template string) error {
 		return fmt.Errorf("template cannot be empty")
 	}
 
-	// Check for balanced braces
-	openCount := strings.Count(template, "{{")
-	closeCount := strings.Count(template, "}}")
-
-	if openCount != closeCount {
-		return fmt.Errorf("unbalanced braces in template")
-	}
-
-	// Check for nested braces
-	if strings.Contains(template, "{{{") || strings.Contains(template, "}}}") {
-		return fmt.Errorf("nested braces not allowed in template")
-	}
-
-	// Extract and validate variable names
-	re := regexp.MustCompile(`\{\{([^}]*)\}\}`)
-	matches := re.FindAllStringSubmatch(template, -1)
-
-	for _, match := range matches {
-		if len(match) > 1 {
-			varName := strings.TrimSpace(match[1])
-			if varName == "" {
-				return fmt.Errorf("empty variable name in template")
-			}
-
-			// Check if variable name contains only valid characters
-			validName := regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
-			if !validName.MatchString(varName) {
-				return fmt.Errorf("invalid variable name: %s (use only letters, numbers, and underscores)", match[1])
-			}
-		}
-	}
-
-	return nil
+	_, err := parse(template)
+	return err
 }
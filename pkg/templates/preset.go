@@ -0,0 +1,152 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Job types a preset can select; they match the batch manifest job types
+const (
+	TypeGenerate    = "generate"
+	TypeAnimate     = "animate"
+	TypeInterpolate = "interpolate"
+)
+
+// Preset holds generation settings a template applies when a command or batch
+// job does not set them explicitly
+type Preset struct {
+	Type           string `yaml:"type,omitempty" json:"type,omitempty"`
+	Model          string `yaml:"model,omitempty" json:"model,omitempty"`
+	Resolution     string `yaml:"resolution,omitempty" json:"resolution,omitempty"`
+	AspectRatio    string `yaml:"aspect_ratio,omitempty" json:"aspect_ratio,omitempty"`
+	Duration       int    `yaml:"duration,omitempty" json:"duration,omitempty"`
+	NegativePrompt string `yaml:"negative_prompt,omitempty" json:"negative_prompt,omitempty"`
+	// Image is the image to animate, or the first frame of an interpolation
+	Image           string   `yaml:"image,omitempty" json:"image,omitempty"`
+	LastFrame       string   `yaml:"last_frame,omitempty" json:"last_frame,omitempty"`
+	ReferenceImages []string `yaml:"reference_images,omitempty" json:"reference_images,omitempty"`
+}
+
+// JobType returns the preset's job type, inferred from its images when unset
+func (p *Preset) JobType() string {
+	switch {
+	case p == nil:
+		return TypeGenerate
+	case p.Type != "":
+		return p.Type
+	case p.LastFrame != "":
+		return TypeInterpolate
+	case p.Image != "":
+		return TypeAnimate
+	default:
+		return TypeGenerate
+	}
+}
+
+// IsEmpty reports whether the preset sets nothing
+func (p *Preset) IsEmpty() bool {
+	return p == nil || (p.Type == "" && p.Model == "" && p.Resolution == "" && p.AspectRatio == "" &&
+		p.Duration == 0 && p.NegativePrompt == "" && p.Image == "" && p.LastFrame == "" &&
+		len(p.ReferenceImages) == 0)
+}
+
+// Validate checks that the preset's images fit its job type. Values such as
+// the model and resolution are validated when a request is built from them.
+func (p *Preset) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	switch p.Type {
+	case "", TypeGenerate, TypeAnimate, TypeInterpolate:
+	default:
+		return fmt.Errorf("invalid preset type: %s (must be %s, %s or %s)", p.Type, TypeGenerate, TypeAnimate, TypeInterpolate)
+	}
+	if p.Duration < 0 {
+		return fmt.Errorf("invalid preset duration: %d", p.Duration)
+	}
+
+	jobType := p.JobType()
+	if p.Image != "" && jobType == TypeGenerate {
+		return fmt.Errorf("preset image requires type %s or %s", TypeAnimate, TypeInterpolate)
+	}
+	if p.LastFrame != "" && jobType != TypeInterpolate {
+		return fmt.Errorf("preset last_frame requires type %s", TypeInterpolate)
+	}
+	if len(p.ReferenceImages) > 0 && jobType != TypeGenerate {
+		return fmt.Errorf("preset reference_images requires type %s", TypeGenerate)
+	}
+	return nil
+}
+
+// Options returns the preset as batch job options for the given job type.
+// Images that do not apply to the job type are left out.
+func (p *Preset) Options(jobType string) map[string]interface{} {
+	options := make(map[string]interface{})
+	if p == nil {
+		return options
+	}
+
+	setString := func(key, value string) {
+		if value != "" {
+			options[key] = value
+		}
+	}
+	setString("model", p.Model)
+	setString("resolution", p.Resolution)
+	setString("aspect_ratio", p.AspectRatio)
+	setString("negative_prompt", p.NegativePrompt)
+	if p.Duration != 0 {
+		options["duration"] = p.Duration
+	}
+
+	switch jobType {
+	case TypeGenerate:
+		if len(p.ReferenceImages) > 0 {
+			options["reference_images"] = append([]string(nil), p.ReferenceImages...)
+		}
+	case TypeAnimate:
+		setString("image", p.Image)
+	case TypeInterpolate:
+		setString("first_frame", p.Image)
+		setString("last_frame", p.LastFrame)
+	}
+	return options
+}
+
+// Summary describes the preset in one line, e.g. "animate, model=veo-3.1-generate-preview, duration=8s"
+func (p *Preset) Summary() string {
+	if p.IsEmpty() {
+		return ""
+	}
+
+	parts := []string{p.JobType()}
+	add := func(key, value string) {
+		if value != "" {
+			parts = append(parts, key+"="+value)
+		}
+	}
+	add("model", p.Model)
+	add("resolution", p.Resolution)
+	add("aspect_ratio", p.AspectRatio)
+	if p.Duration != 0 {
+		add("duration", fmt.Sprintf("%ds", p.Duration))
+	}
+	add("negative_prompt", p.NegativePrompt)
+	add("image", p.Image)
+	add("last_frame", p.LastFrame)
+	add("reference_images", strings.Join(p.ReferenceImages, ","))
+	return strings.Join(parts, ", ")
+}
+
+// CheckType returns an error if the preset's type or images belong to a
+// different job type. Presets that only set generation settings fit any type.
+func (p *Preset) CheckType(jobType string) error {
+	if p == nil || (p.Type == "" && p.Image == "" && p.LastFrame == "" && len(p.ReferenceImages) == 0) {
+		return nil
+	}
+	if presetType := p.JobType(); presetType != jobType {
+		return fmt.Errorf("preset is for %s jobs, not %s", presetType, jobType)
+	}
+	return nil
+}
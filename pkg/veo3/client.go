@@ -6,11 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
-	"os"
+	"net/url"
 	"strings"
 	"time"
+
+	"github.com/jasongoecke/go-veo3/internal/logger"
 )
 
 const (
@@ -22,6 +24,10 @@ type Client struct {
 	APIKey     string
 	BaseURL    string
 	HTTPClient *http.Client
+
+	observer   Observer
+	middleware []Middleware
+	logger     *slog.Logger
 }
 
 // ClientOption is a function that configures a Client
@@ -41,6 +47,22 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
+// WithLogger sets the structured logger used for client diagnostics. Pair it
+// with WithMiddleware(WireLogger(logger)) to log every request and response.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// Logger returns the client's logger, or one that discards records if none is set
+func (c *Client) Logger() *slog.Logger {
+	if c == nil || c.logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return c.logger
+}
+
 // NewClient creates a new Veo API client
 func NewClient(ctx context.Context, apiKey string, opts ...ClientOption) (*Client, error) {
 	// Trim whitespace and validate
@@ -65,16 +87,97 @@ func NewClient(ctx context.Context, apiKey string, opts ...ClientOption) (*Clien
 
 // CancelOperation cancels a running operation
 func (c *Client) CancelOperation(ctx context.Context, operationID string) error {
-	// TODO: Implement actual API call to cancel operation
-	// For now, this is a placeholder
-	return fmt.Errorf("CancelOperation not yet implemented")
+	if operationID == "" {
+		return fmt.Errorf("operation ID cannot be empty")
+	}
+
+	// Long-running operations are cancelled with a custom method on the resource
+	url := fmt.Sprintf("%s/%s:cancel", c.BaseURL, operationID)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader([]byte("{}")))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("x-goog-api-key", c.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req, "cancel")
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return parseErrorResponse(resp.StatusCode, body)
+	}
+
+	return nil
 }
 
-// ListOperations retrieves operations from the API
+// ListOperations retrieves operations from the API, following pagination.
+// An empty filter returns operations in every status.
 func (c *Client) ListOperations(ctx context.Context, filter OperationStatus) ([]*Operation, error) {
-	// TODO: Implement actual API call to list operations
-	// For now, this is a placeholder
-	return nil, fmt.Errorf("ListOperations not yet implemented")
+	var ops []*Operation
+	pageToken := ""
+
+	for {
+		listURL := fmt.Sprintf("%s/operations", c.BaseURL)
+		if pageToken != "" {
+			listURL += "?pageToken=" + url.QueryEscape(pageToken)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", listURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("x-goog-api-key", c.APIKey)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.do(req, "list_operations")
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute request: %w", err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, parseErrorResponse(resp.StatusCode, body)
+		}
+
+		var page struct {
+			Operations    []json.RawMessage `json:"operations"`
+			NextPageToken string            `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		for _, raw := range page.Operations {
+			op, err := parseOperation(raw, c.Logger())
+			if err != nil {
+				return nil, err
+			}
+			if filter == "" || op.Status == filter {
+				ops = append(ops, op)
+			}
+		}
+
+		if page.NextPageToken == "" {
+			return ops, nil
+		}
+		pageToken = page.NextPageToken
+	}
 }
 
 // GetOperation retrieves an operation's current status from the API
@@ -97,7 +200,7 @@ func (c *Client) GetOperation(ctx context.Context, operationID string) (*Operati
 	req.Header.Set("Content-Type", "application/json")
 
 	// Execute request
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.do(req, "get_operation")
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -109,16 +212,16 @@ func (c *Client) GetOperation(ctx context.Context, operationID string) (*Operati
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Debug logging if VEO3_DEBUG environment variable is set
-	if os.Getenv("VEO3_DEBUG") != "" {
-		log.Printf("[DEBUG] GetOperation response body: %s", string(body))
-	}
-
 	// Handle HTTP errors
 	if resp.StatusCode != http.StatusOK {
 		return nil, parseErrorResponse(resp.StatusCode, body)
 	}
 
+	return parseOperation(body, c.Logger())
+}
+
+// parseOperation maps a long-running operation resource to an Operation
+func parseOperation(body []byte, log *slog.Logger) (*Operation, error) {
 	// Parse response with flexible structure to handle various API response formats
 	var apiResp struct {
 		Name     string `json:"name"`
@@ -194,12 +297,19 @@ func (c *Client) GetOperation(ctx context.Context, operationID string) (*Operati
 
 			if videoURI != "" {
 				op.VideoURI = videoURI
-			} else if os.Getenv("VEO3_DEBUG") != "" {
-				// Log response structure for debugging when URI extraction fails
-				log.Printf("[DEBUG] Failed to extract video URI from response. Response structure: %+v", apiResp.Response)
-				if resp, ok := genericResp["response"].(map[string]interface{}); ok {
-					log.Printf("[DEBUG] Generic response map: %+v", resp)
+			} else if reasons := extractFilteredReasons(genericResp); len(reasons) > 0 {
+				// The safety filters blocked every video
+				op.Status = StatusFailed
+				op.Error = &OperationError{
+					Code:    SafetyFilteredCode,
+					Message: "video blocked by the safety filter: " + strings.Join(reasons, "; "),
 				}
+			} else {
+				// Log response structure for debugging when URI extraction fails
+				response, _ := json.Marshal(genericResp["response"])
+				log.Debug("video URI not found in completed operation",
+					logger.OperationIDKey, apiResp.Name,
+					"response", redactBody(response, "application/json"))
 			}
 
 			now := time.Now()
@@ -279,7 +389,7 @@ func (c *Client) GenerateVideo(ctx context.Context, request *GenerationRequest)
 	req.Header.Set("Content-Type", "application/json")
 
 	// Execute request
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.do(req, "generate")
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -325,6 +435,27 @@ func (c *Client) GenerateVideo(ctx context.Context, request *GenerationRequest)
 	return op, nil
 }
 
+// do executes an API request and reports it to the observer
+func (c *Client) do(req *http.Request, call string) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.httpClient().Do(req)
+
+	event := RequestEvent{
+		Call:     call,
+		Method:   req.Method,
+		URL:      req.URL.Redacted(),
+		Start:    start,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	if resp != nil {
+		event.StatusCode = resp.StatusCode
+	}
+	c.Observer().RequestCompleted(req.Context(), event)
+
+	return resp, err
+}
+
 // parseErrorResponse parses API error responses
 func parseErrorResponse(statusCode int, body []byte) error {
 	var errResp struct {
@@ -447,6 +578,28 @@ func extractFromGenericMap(resp map[string]interface{}) string {
 	return ""
 }
 
+// extractFilteredReasons returns the reasons the safety filters gave for
+// blocking a completed operation's videos
+func extractFilteredReasons(genericResp map[string]interface{}) []string {
+	resp, _ := genericResp["response"].(map[string]interface{})
+	if nested, ok := resp["generateVideoResponse"].(map[string]interface{}); ok {
+		resp = nested
+	}
+	reasons, _ := resp["raiMediaFilteredReasons"].([]interface{})
+	var result []string
+	for _, reason := range reasons {
+		if text, ok := reason.(string); ok && text != "" {
+			result = append(result, text)
+		}
+	}
+	if len(result) == 0 {
+		if count, ok := resp["raiMediaFilteredCount"].(float64); ok && count > 0 {
+			result = append(result, fmt.Sprintf("%d video(s) filtered", int(count)))
+		}
+	}
+	return result
+}
+
 // extractFromGenerateVideoResponse extracts URI from generateVideoResponse.generatedSamples[0].video.uri
 func extractFromGenerateVideoResponse(resp map[string]interface{}) string {
 	genVideoResp, ok := resp["generateVideoResponse"].(map[string]interface{})
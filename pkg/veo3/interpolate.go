@@ -2,6 +2,7 @@ package veo3
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -62,6 +63,21 @@ func (r *InterpolationRequest) Validate() error {
 		return fmt.Errorf("first and last frame cannot be the same file")
 	}
 
+	// With a frame fit mode, mismatched dimensions are reconciled at payload
+	// build time, so only validate each image on its own
+	if r.FrameFit != FrameFitNone {
+		if _, err := ParseFrameFitMode(string(r.FrameFit)); err != nil {
+			return err
+		}
+		if err := validation.ValidateImageFile(r.FirstFramePath); err != nil {
+			return fmt.Errorf("first frame: %w", err)
+		}
+		if err := validation.ValidateImageFile(r.LastFramePath); err != nil {
+			return fmt.Errorf("last frame: %w", err)
+		}
+		return nil
+	}
+
 	// Validate both images and compatibility
 	if err := ValidateCompatibleImages(r.FirstFramePath, r.LastFramePath); err != nil {
 		return err
@@ -114,6 +130,22 @@ func EncodeBothImagesToBase64(firstPath, lastPath string) (string, string, error
 	return encoded1, encoded2, nil
 }
 
+// encodeReconciledFrames encodes the first frame as-is and the last frame after
+// matching its dimensions to the first frame
+func encodeReconciledFrames(firstPath, lastPath string, mode FrameFitMode) (string, string, error) {
+	firstEncoded, err := EncodeImageToBase64(firstPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode first frame: %w", err)
+	}
+
+	lastData, _, err := ReconcileLastFrame(firstPath, lastPath, mode)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to reconcile last frame: %w", err)
+	}
+
+	return firstEncoded, base64.StdEncoding.EncodeToString(lastData), nil
+}
+
 // BuildInterpolationPayload builds the API payload for frame interpolation
 func BuildInterpolationPayload(request *InterpolationRequest) (map[string]interface{}, error) {
 	if request == nil {
@@ -125,8 +157,14 @@ func BuildInterpolationPayload(request *InterpolationRequest) (map[string]interf
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
 
-	// Encode both images to base64
-	firstEncoded, lastEncoded, err := EncodeBothImagesToBase64(request.FirstFramePath, request.LastFramePath)
+	// Encode both images to base64, reconciling the last frame if requested
+	var firstEncoded, lastEncoded string
+	var err error
+	if request.FrameFit != FrameFitNone {
+		firstEncoded, lastEncoded, err = encodeReconciledFrames(request.FirstFramePath, request.LastFramePath, request.FrameFit)
+	} else {
+		firstEncoded, lastEncoded, err = EncodeBothImagesToBase64(request.FirstFramePath, request.LastFramePath)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode images: %w", err)
 	}
@@ -212,6 +250,15 @@ func (c *Client) InterpolateFrames(ctx context.Context, req *InterpolationReques
 		},
 	}
 
+	// Record any last frame transformation so it shows up in the operation output
+	if req.FrameFit != FrameFitNone {
+		plan, err := PlanFrameReconciliation(req.FirstFramePath, req.LastFramePath, req.FrameFit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconcile frames: %w", err)
+		}
+		op.Metadata["frame_reconciliation"] = plan
+	}
+
 	return op, nil
 }
 
@@ -0,0 +1,68 @@
+package veo3
+
+import (
+	"net/http"
+)
+
+// Middleware wraps the transport used for every outbound request, e.g. to add
+// headers, request IDs or policy checks. It must return a RoundTripper that
+// calls next to continue the chain, or returns a response or error itself.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to an http.RoundTripper
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithMiddleware appends middleware to the client's transport chain. The first
+// middleware added is the outermost and sees each request first. The chain
+// applies to submission, polling, listing and cancelling, and to downloads by
+// an operations.Downloader created from the client.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// WithHeader is middleware that sets a header on every request, e.g.
+// "x-goog-user-project" to bill a quota project
+func WithHeader(key, value string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			// RoundTrippers must not modify the caller's request
+			req = req.Clone(req.Context())
+			req.Header.Set(key, value)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// Transport returns the client's base transport wrapped in its middleware chain
+func (c *Client) Transport() http.RoundTripper {
+	var transport http.RoundTripper = http.DefaultTransport
+	if c.HTTPClient != nil && c.HTTPClient.Transport != nil {
+		transport = c.HTTPClient.Transport
+	}
+
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		transport = c.middleware[i](transport)
+	}
+	return transport
+}
+
+// httpClient returns an HTTP client that sends requests through the middleware chain
+func (c *Client) httpClient() *http.Client {
+	if len(c.middleware) == 0 && c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+
+	client := &http.Client{}
+	if c.HTTPClient != nil {
+		*client = *c.HTTPClient
+	}
+	client.Transport = c.Transport()
+	return client
+}
@@ -10,6 +10,8 @@ type Model struct {
 	Constraints  ModelConstraints  `json:"constraints"`
 	Tier         string            `json:"tier"`
 	Version      string            `json:"version"`
+	// PricePerSecond is the estimated list price in USD per generated second
+	PricePerSecond float64 `json:"price_per_second,omitempty"`
 }
 
 type ModelCapabilities struct {
@@ -41,8 +43,9 @@ var ModelRegistry = []Model{
 		Constraints: ModelConstraints{
 			MaxReferenceImages: 3,
 		},
-		Tier:    "standard",
-		Version: "3.1",
+		Tier:           "standard",
+		Version:        "3.1",
+		PricePerSecond: 0.40,
 	},
 	{
 		ID:   "veo-3.1-fast-generate-preview",
@@ -57,8 +60,9 @@ var ModelRegistry = []Model{
 		Constraints: ModelConstraints{
 			MaxReferenceImages: 3,
 		},
-		Tier:    "standard",
-		Version: "3.1",
+		Tier:           "standard",
+		Version:        "3.1",
+		PricePerSecond: 0.15,
 	},
 	{
 		ID:   "veo-3-generate-preview",
@@ -73,8 +77,9 @@ var ModelRegistry = []Model{
 		Constraints: ModelConstraints{
 			MaxReferenceImages: 0,
 		},
-		Tier:    "standard",
-		Version: "3.0",
+		Tier:           "standard",
+		Version:        "3.0",
+		PricePerSecond: 0.40,
 	},
 	{
 		ID:   "veo-3-fast-generate-preview",
@@ -89,8 +94,9 @@ var ModelRegistry = []Model{
 		Constraints: ModelConstraints{
 			MaxReferenceImages: 0,
 		},
-		Tier:    "standard",
-		Version: "3.0",
+		Tier:           "standard",
+		Version:        "3.0",
+		PricePerSecond: 0.15,
 	},
 	{
 		ID:   "veo-2.0-generate-001",
@@ -105,8 +111,9 @@ var ModelRegistry = []Model{
 		Constraints: ModelConstraints{
 			MaxReferenceImages: 0,
 		},
-		Tier:    "legacy",
-		Version: "2.0",
+		Tier:           "legacy",
+		Version:        "2.0",
+		PricePerSecond: 0.35,
 	},
 }
 
@@ -120,6 +127,16 @@ func GetModel(id string) (Model, bool) {
 	return Model{}, false
 }
 
+// EstimateCost returns the estimated USD cost of generating seconds of video
+// with a model, or false if the model or its price is unknown
+func EstimateCost(modelID string, seconds int) (float64, bool) {
+	model, exists := GetModel(modelID)
+	if !exists || model.PricePerSecond == 0 {
+		return 0, false
+	}
+	return model.PricePerSecond * float64(seconds), true
+}
+
 // ValidateModelForReferenceImages checks if model supports reference images
 func ValidateModelForReferenceImages(modelID string, count int) error {
 	model, exists := GetModel(modelID)
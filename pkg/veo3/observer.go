@@ -0,0 +1,161 @@
+package veo3
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Observer receives instrumentation events from the client, the operation
+// poller and downloader, and the batch processor. Implementations must be safe
+// for concurrent use. Events carry their start time so tracing adapters can
+// record spans after the fact.
+type Observer interface {
+	// RequestCompleted is called after every HTTP request to the Veo API
+	RequestCompleted(ctx context.Context, event RequestEvent)
+	// OperationPolled is called after every status poll of an operation
+	OperationPolled(ctx context.Context, event PollEvent)
+	// OperationCompleted is called once when a polled operation reaches a terminal status
+	OperationCompleted(ctx context.Context, event CompletionEvent)
+	// VideoDownloaded is called after every video download attempt
+	VideoDownloaded(ctx context.Context, event DownloadEvent)
+	// BatchJobCompleted is called after every batch job
+	BatchJobCompleted(ctx context.Context, event BatchJobEvent)
+}
+
+// RequestEvent describes a single HTTP request to the Veo API
+type RequestEvent struct {
+	Call       string // Client call, e.g. "generate", "get_operation", "cancel"
+	Method     string
+	URL        string
+	StatusCode int // 0 if no response was received
+	Start      time.Time
+	Duration   time.Duration
+	Err        error
+}
+
+// PollEvent describes a single status poll of an operation
+type PollEvent struct {
+	OperationID string
+	Model       string
+	Status      OperationStatus // Empty if the poll failed
+	Start       time.Time
+	Duration    time.Duration
+	Err         error
+}
+
+// CompletionEvent describes an operation reaching DONE, FAILED or CANCELLED
+type CompletionEvent struct {
+	OperationID string
+	Model       string
+	Status      OperationStatus
+	Start       time.Time     // When the operation was submitted or first seen
+	Duration    time.Duration // Time from Start to completion
+}
+
+// DownloadEvent describes a video download attempt
+type DownloadEvent struct {
+	OperationID string
+	Model       string
+	Bytes       int64
+	Start       time.Time
+	Duration    time.Duration
+	Err         error
+}
+
+// BatchJobEvent describes the outcome of a batch job
+type BatchJobEvent struct {
+	JobID    string
+	Type     string // "generate", "animate", "interpolate" or "extend"
+	Success  bool
+	Start    time.Time
+	Duration time.Duration
+	Err      string
+}
+
+// NopObserver ignores all events. Embed it to implement only some methods.
+type NopObserver struct{}
+
+func (NopObserver) RequestCompleted(context.Context, RequestEvent)      {}
+func (NopObserver) OperationPolled(context.Context, PollEvent)          {}
+func (NopObserver) OperationCompleted(context.Context, CompletionEvent) {}
+func (NopObserver) VideoDownloaded(context.Context, DownloadEvent)      {}
+func (NopObserver) BatchJobCompleted(context.Context, BatchJobEvent)    {}
+
+// multiObserver fans events out to several observers
+type multiObserver []Observer
+
+// MultiObserver returns an observer that forwards every event to each of observers
+func MultiObserver(observers ...Observer) Observer {
+	var active multiObserver
+	for _, o := range observers {
+		if o != nil {
+			active = append(active, o)
+		}
+	}
+	return active
+}
+
+func (m multiObserver) RequestCompleted(ctx context.Context, event RequestEvent) {
+	for _, o := range m {
+		o.RequestCompleted(ctx, event)
+	}
+}
+
+func (m multiObserver) OperationPolled(ctx context.Context, event PollEvent) {
+	for _, o := range m {
+		o.OperationPolled(ctx, event)
+	}
+}
+
+func (m multiObserver) OperationCompleted(ctx context.Context, event CompletionEvent) {
+	for _, o := range m {
+		o.OperationCompleted(ctx, event)
+	}
+}
+
+func (m multiObserver) VideoDownloaded(ctx context.Context, event DownloadEvent) {
+	for _, o := range m {
+		o.VideoDownloaded(ctx, event)
+	}
+}
+
+func (m multiObserver) BatchJobCompleted(ctx context.Context, event BatchJobEvent) {
+	for _, o := range m {
+		o.BatchJobCompleted(ctx, event)
+	}
+}
+
+// WithObserver sets the observer notified of client, poller and downloader events
+func WithObserver(observer Observer) ClientOption {
+	return func(c *Client) {
+		c.observer = observer
+	}
+}
+
+// Observer returns the client's observer, or a no-op observer if none is set
+func (c *Client) Observer() Observer {
+	if c == nil || c.observer == nil {
+		return NopObserver{}
+	}
+	return c.observer
+}
+
+// OperationModel returns the model an operation was submitted to, from its
+// metadata or from an operation name of the form "models/<model>/operations/<id>"
+func OperationModel(op *Operation) string {
+	if op == nil {
+		return ""
+	}
+	if model, ok := op.Metadata["model"].(string); ok && model != "" {
+		return model
+	}
+
+	parts := strings.Split(op.ID, "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "models" {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,174 @@
+package veo3
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"strings"
+
+	"github.com/jasongoecke/go-veo3/internal/validation"
+	"golang.org/x/image/draw"
+)
+
+// FrameFitMode controls how a last frame with different dimensions is
+// reconciled with the first frame before interpolation
+type FrameFitMode string
+
+const (
+	// FrameFitNone rejects frames whose dimensions differ (default)
+	FrameFitNone FrameFitMode = ""
+	// FrameFitScale stretches the last frame to the first frame's dimensions
+	FrameFitScale FrameFitMode = "scale"
+	// FrameFitCrop scales the last frame to cover the first frame's dimensions
+	// and center-crops the overflow, preserving its aspect ratio
+	FrameFitCrop FrameFitMode = "crop"
+)
+
+// ParseFrameFitMode parses a frame fit mode from user input
+func ParseFrameFitMode(value string) (FrameFitMode, error) {
+	switch FrameFitMode(strings.ToLower(strings.TrimSpace(value))) {
+	case FrameFitNone:
+		return FrameFitNone, nil
+	case FrameFitScale:
+		return FrameFitScale, nil
+	case FrameFitCrop:
+		return FrameFitCrop, nil
+	default:
+		return FrameFitNone, fmt.Errorf("invalid frame fit mode: %s (must be scale or crop)", value)
+	}
+}
+
+// FrameReconciliation describes the transformation applied to the last frame
+type FrameReconciliation struct {
+	Mode         FrameFitMode     `json:"mode"`
+	SourceWidth  int              `json:"source_width"`
+	SourceHeight int              `json:"source_height"`
+	TargetWidth  int              `json:"target_width"`
+	TargetHeight int              `json:"target_height"`
+	CropRect     *image.Rectangle `json:"crop_rect,omitempty"` // Region of the source kept in crop mode
+}
+
+// Applied reports whether the last frame needs to be transformed
+func (r *FrameReconciliation) Applied() bool {
+	return r.SourceWidth != r.TargetWidth || r.SourceHeight != r.TargetHeight
+}
+
+// String returns a human-readable description of the transformation
+func (r *FrameReconciliation) String() string {
+	if !r.Applied() {
+		return fmt.Sprintf("dimensions already match (%dx%d)", r.TargetWidth, r.TargetHeight)
+	}
+
+	switch r.Mode {
+	case FrameFitCrop:
+		return fmt.Sprintf("cropped %dx%d region of %dx%d last frame and scaled to %dx%d",
+			r.CropRect.Dx(), r.CropRect.Dy(), r.SourceWidth, r.SourceHeight, r.TargetWidth, r.TargetHeight)
+	default:
+		return fmt.Sprintf("rescaled last frame from %dx%d to %dx%d",
+			r.SourceWidth, r.SourceHeight, r.TargetWidth, r.TargetHeight)
+	}
+}
+
+// PlanFrameReconciliation computes the transformation needed to match the last
+// frame to the first frame without decoding pixel data
+func PlanFrameReconciliation(firstPath, lastPath string, mode FrameFitMode) (*FrameReconciliation, error) {
+	if mode != FrameFitScale && mode != FrameFitCrop {
+		return nil, fmt.Errorf("invalid frame fit mode: %s (must be scale or crop)", mode)
+	}
+
+	firstCfg, _, err := validation.DecodeImageConfig(firstPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode first frame: %w", err)
+	}
+
+	lastCfg, _, err := validation.DecodeImageConfig(lastPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode last frame: %w", err)
+	}
+
+	if firstCfg.Width <= 0 || firstCfg.Height <= 0 || lastCfg.Width <= 0 || lastCfg.Height <= 0 {
+		return nil, fmt.Errorf("invalid dimensions: %dx%d vs %dx%d",
+			firstCfg.Width, firstCfg.Height, lastCfg.Width, lastCfg.Height)
+	}
+
+	plan := &FrameReconciliation{
+		Mode:         mode,
+		SourceWidth:  lastCfg.Width,
+		SourceHeight: lastCfg.Height,
+		TargetWidth:  firstCfg.Width,
+		TargetHeight: firstCfg.Height,
+	}
+
+	if mode == FrameFitCrop && plan.Applied() {
+		rect := coverCropRect(plan.SourceWidth, plan.SourceHeight, plan.TargetWidth, plan.TargetHeight)
+		plan.CropRect = &rect
+	}
+
+	return plan, nil
+}
+
+// ReconcileLastFrame returns the last frame's image data transformed to match
+// the first frame's dimensions. If the dimensions already match, the original
+// file contents are returned unchanged.
+func ReconcileLastFrame(firstPath, lastPath string, mode FrameFitMode) ([]byte, *FrameReconciliation, error) {
+	plan, err := PlanFrameReconciliation(firstPath, lastPath, mode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := os.ReadFile(lastPath) // #nosec G304 -- User-specified image path is validated
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read last frame: %w", err)
+	}
+
+	if !plan.Applied() {
+		return data, plan, nil
+	}
+
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode last frame: %w", err)
+	}
+
+	srcRect := src.Bounds()
+	if plan.CropRect != nil {
+		srcRect = plan.CropRect.Add(src.Bounds().Min)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, plan.TargetWidth, plan.TargetHeight))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, srcRect, draw.Src, nil)
+
+	// Keep JPEG sources as JPEG; everything else (including WebP, which has no
+	// encoder in the standard library) is re-encoded as lossless PNG
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 95})
+	} else {
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode reconciled last frame: %w", err)
+	}
+
+	return buf.Bytes(), plan, nil
+}
+
+// coverCropRect returns the centered region of a source image that, when
+// scaled, exactly covers the target dimensions
+func coverCropRect(srcW, srcH, dstW, dstH int) image.Rectangle {
+	// Compare aspect ratios using integer cross-multiplication to avoid rounding drift
+	if srcW*dstH > dstW*srcH {
+		// Source is wider than target: keep full height, trim the sides
+		cropW := dstW * srcH / dstH
+		x0 := (srcW - cropW) / 2
+		return image.Rect(x0, 0, x0+cropW, srcH)
+	}
+
+	// Source is taller than (or equal to) target: keep full width, trim top and bottom
+	cropH := dstH * srcW / dstW
+	y0 := (srcH - cropH) / 2
+	return image.Rect(0, y0, srcW, y0+cropH)
+}
@@ -148,8 +148,9 @@ type ImageRequest struct {
 // InterpolationRequest extends GenerationRequest for frame interpolation
 type InterpolationRequest struct {
 	GenerationRequest
-	FirstFramePath string `json:"first_frame_path" yaml:"first_frame_path"`
-	LastFramePath  string `json:"last_frame_path" yaml:"last_frame_path"`
+	FirstFramePath string       `json:"first_frame_path" yaml:"first_frame_path"`
+	LastFramePath  string       `json:"last_frame_path" yaml:"last_frame_path"`
+	FrameFit       FrameFitMode `json:"frame_fit,omitempty" yaml:"frame_fit,omitempty"` // Reconcile mismatched last frame dimensions
 }
 
 // ReferenceImageRequest extends GenerationRequest with reference images for guided generation
@@ -188,6 +189,10 @@ type Operation struct {
 	Metadata  map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// SafetyFilteredCode is the error code of operations whose video was blocked
+// by the API's safety filters
+const SafetyFilteredCode = "SAFETY_FILTERED"
+
 // OperationError represents error details for failed operations
 type OperationError struct {
 	Code       string                 `json:"code"`
@@ -216,4 +221,5 @@ type GeneratedVideo struct {
 	FileSizeBytes         int64     `json:"file_size_bytes"`
 	GenerationTimeSeconds int       `json:"generation_time_seconds"`
 	CreatedAt             time.Time `json:"created_at"`
+	Template              string    `json:"template,omitempty"` // Prompt template and version used, e.g. "promo@4"
 }
@@ -0,0 +1,228 @@
+package veo3
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// redacted replaces secret header and query values in wire logs
+	redacted = "REDACTED"
+	// maxInlineString is the longest base64-looking string logged in full
+	maxInlineString = 256
+	// keepBase64Prefix is how much of a truncated base64 string is kept
+	keepBase64Prefix = 32
+)
+
+// secretHeaders are never logged in full
+var secretHeaders = map[string]bool{
+	"x-goog-api-key":      true,
+	"x-api-key":           true,
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"set-cookie":          true,
+}
+
+// secretParams are query parameters never logged in full
+var secretParams = map[string]bool{
+	"key":          true,
+	"api_key":      true,
+	"access_token": true,
+}
+
+// WireLogger is middleware that logs every request and response at debug
+// level. API keys in headers and query strings are redacted, base64 payloads
+// in JSON bodies are truncated and non-JSON bodies such as videos are logged
+// by size only.
+func WireLogger(logger *slog.Logger) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			if !logger.Enabled(ctx, slog.LevelDebug) {
+				return next.RoundTrip(req)
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("url", redactURL(req.URL)),
+				slog.Any("headers", redactHeaders(req.Header)),
+			}
+			if body := requestBody(req); body != nil {
+				attrs = append(attrs, slog.String("body", redactBody(body, req.Header.Get("Content-Type"))))
+			}
+			logger.LogAttrs(ctx, slog.LevelDebug, "http request", attrs...)
+
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			attrs = []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("url", redactURL(req.URL)),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if err != nil {
+				logger.LogAttrs(ctx, slog.LevelDebug, "http error", append(attrs, slog.String("error", err.Error()))...)
+				return resp, err
+			}
+
+			attrs = append(attrs,
+				slog.Int("status", resp.StatusCode),
+				slog.Any("headers", redactHeaders(resp.Header)))
+			attrs = append(attrs, slog.String("body", responseBody(resp)))
+			logger.LogAttrs(ctx, slog.LevelDebug, "http response", attrs...)
+			return resp, nil
+		})
+	}
+}
+
+// requestBody returns a copy of the request body without consuming it
+func requestBody(req *http.Request) []byte {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = body.Close() }()
+	data, _ := io.ReadAll(body)
+	return data
+}
+
+// responseBody renders a response body for logging. JSON bodies are read and
+// replaced so the caller still sees them; anything else is logged by size.
+// Text bodies are read and replaced too, since error pages are often text.
+func responseBody(resp *http.Response) string {
+	contentType := resp.Header.Get("Content-Type")
+	if !isTextual(contentType) {
+		return describeBinary(resp.ContentLength, contentType)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return "<unreadable: " + err.Error() + ">"
+	}
+	return redactBody(data, contentType)
+}
+
+// redactBody truncates base64 strings in a JSON body. Bodies that are not
+// JSON are described by size.
+func redactBody(body []byte, contentType string) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		if contentType == "" || isTextual(contentType) {
+			return truncateString(string(body))
+		}
+		return describeBinary(int64(len(body)), contentType)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(truncateValue(value)); err != nil {
+		return describeBinary(int64(len(body)), contentType)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// truncateValue walks decoded JSON and truncates long base64 strings
+func truncateValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = truncateValue(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = truncateValue(item)
+		}
+		return v
+	case string:
+		return truncateString(v)
+	default:
+		return v
+	}
+}
+
+// truncateString shortens long strings that look like base64 data
+func truncateString(s string) string {
+	if len(s) <= maxInlineString || !looksBase64(s) {
+		return s
+	}
+	return s[:keepBase64Prefix] + "...<" + strconv.Itoa(len(s)) + " bytes truncated>"
+}
+
+// looksBase64 reports whether s only contains base64 (standard or URL) characters
+func looksBase64(s string) bool {
+	for _, r := range s {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		case r == '+', r == '/', r == '=', r == '-', r == '_', r == '\n', r == '\r':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// redactHeaders copies headers with secret values replaced
+func redactHeaders(header http.Header) map[string]string {
+	out := make(map[string]string, len(header))
+	for key, values := range header {
+		if secretHeaders[strings.ToLower(key)] {
+			out[key] = redacted
+			continue
+		}
+		out[key] = strings.Join(values, ", ")
+	}
+	return out
+}
+
+// redactURL renders a URL with secret query parameters replaced
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+
+	query := u.Query()
+	for key := range query {
+		if secretParams[strings.ToLower(key)] {
+			query.Set(key, redacted)
+		}
+	}
+	clean := *u
+	clean.RawQuery = query.Encode()
+	return clean.String()
+}
+
+// isTextual reports whether a body of this content type is worth logging
+func isTextual(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") ||
+		strings.HasPrefix(mediaType, "text/")
+}
+
+// describeBinary summarises a body that is not logged
+func describeBinary(size int64, contentType string) string {
+	if contentType == "" {
+		contentType = "unknown type"
+	}
+	if size < 0 {
+		return "<" + contentType + ", unknown length>"
+	}
+	return "<" + strconv.FormatInt(size, 10) + " bytes of " + contentType + ">"
+}
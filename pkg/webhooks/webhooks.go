@@ -0,0 +1,244 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/internal/format"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+)
+
+const (
+	// SignatureHeader carries the HMAC-SHA256 signature of the request
+	SignatureHeader = "X-Veo3-Signature"
+	// TimestampHeader carries the Unix timestamp included in the signature
+	TimestampHeader = "X-Veo3-Timestamp"
+	// EventHeader carries the event name for routing without parsing the body
+	EventHeader = "X-Veo3-Event"
+)
+
+// Notification describes a finished operation to deliver to webhooks
+type Notification struct {
+	Operation *veo3.Operation
+	FilePath  string
+	JobID     string
+}
+
+// Payload is the JSON body posted to each webhook URL
+type Payload struct {
+	Event     string          `json:"event"`
+	Operation json.RawMessage `json:"operation"` // Same shape as format.FormatOperationJSON
+	FilePath  string          `json:"file_path,omitempty"`
+	JobID     string          `json:"job_id,omitempty"`
+	SentAt    time.Time       `json:"sent_at"`
+}
+
+// Notifier delivers signed completion payloads to webhook URLs
+type Notifier struct {
+	urls        []string
+	secret      []byte
+	client      *http.Client
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+// NewNotifier creates a notifier for the given URLs. Payloads are signed when
+// secret is non-empty.
+func NewNotifier(urls []string, secret string) *Notifier {
+	return &Notifier{
+		urls:        dedupe(urls),
+		secret:      []byte(secret),
+		client:      &http.Client{Timeout: 15 * time.Second},
+		maxAttempts: 5,               // Give up after 5 attempts per URL
+		baseDelay:   1 * time.Second, // Double after each failed attempt
+		maxDelay:    30 * time.Second,
+	}
+}
+
+// SetRetryPolicy allows customization of delivery retries
+func (n *Notifier) SetRetryPolicy(maxAttempts int, baseDelay, maxDelay time.Duration) {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	n.maxAttempts = maxAttempts
+	n.baseDelay = baseDelay
+	n.maxDelay = maxDelay
+}
+
+// SetHTTPClient sets the HTTP client used for deliveries
+func (n *Notifier) SetHTTPClient(client *http.Client) {
+	n.client = client
+}
+
+// Enabled reports whether any webhook URLs are configured
+func (n *Notifier) Enabled() bool {
+	return n != nil && len(n.urls) > 0
+}
+
+// Notify posts the notification to every configured URL. Operations that have
+// not reached a terminal state are ignored.
+func (n *Notifier) Notify(ctx context.Context, notification Notification) error {
+	if !n.Enabled() || notification.Operation == nil {
+		return nil
+	}
+
+	event, ok := EventName(notification.Operation.Status)
+	if !ok {
+		return nil
+	}
+
+	body, err := BuildPayload(event, notification)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, url := range n.urls {
+		if err := n.deliver(ctx, url, event, body); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", url, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// EventName maps a terminal operation status to a webhook event name
+func EventName(status veo3.OperationStatus) (string, bool) {
+	switch status {
+	case veo3.StatusDone:
+		return "operation.done", true
+	case veo3.StatusFailed:
+		return "operation.failed", true
+	case veo3.StatusCancelled:
+		return "operation.cancelled", true
+	default:
+		return "", false
+	}
+}
+
+// BuildPayload marshals the webhook body for a notification
+func BuildPayload(event string, notification Notification) ([]byte, error) {
+	opJSON, err := format.FormatOperationJSON(notification.Operation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format operation: %w", err)
+	}
+
+	payload := Payload{
+		Event:     event,
+		Operation: json.RawMessage(opJSON),
+		FilePath:  notification.FilePath,
+		JobID:     notification.JobID,
+		SentAt:    time.Now().UTC(),
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	return body, nil
+}
+
+// Sign computes the signature header value for a body sent at timestamp
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature header value against the body and timestamp
+func Verify(secret []byte, timestamp string, body []byte, signature string) bool {
+	expected := Sign(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// deliver posts a payload to a single URL with exponential backoff
+func (n *Notifier) deliver(ctx context.Context, url, event string, body []byte) error {
+	delay := n.baseDelay
+	var lastErr error
+
+	for attempt := 1; attempt <= n.maxAttempts; attempt++ {
+		retryable, err := n.post(ctx, url, event, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if !retryable || attempt == n.maxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > n.maxDelay {
+			delay = n.maxDelay
+		}
+	}
+
+	return fmt.Errorf("delivery failed: %w", lastErr)
+}
+
+// post performs a single delivery attempt and reports whether a failure is retryable
+func (n *Notifier) post(ctx context.Context, url, event string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(TimestampHeader, timestamp)
+	if len(n.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(n.secret, timestamp, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	// Server errors and rate limiting are worth retrying; other client errors are not
+	retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retryable, fmt.Errorf("HTTP %d", resp.StatusCode)
+}
+
+// dedupe removes empty and duplicate URLs while preserving order
+func dedupe(urls []string) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(urls))
+	for _, url := range urls {
+		url = strings.TrimSpace(url)
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+		result = append(result, url)
+	}
+	return result
+}
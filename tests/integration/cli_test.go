@@ -332,7 +332,7 @@ func TestGenerateCommand_ConfigFile(t *testing.T) {
 
 	// Create test config file
 	configContent := `api_key: test-api-key
-default_model: veo-3.1
+default_model: veo-3.1-generate-preview
 default_resolution: 720p
 default_duration: 6
 default_aspect_ratio: 16:9
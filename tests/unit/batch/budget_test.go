@@ -0,0 +1,158 @@
+package batch_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var budgetDefaults = batch.JobDefaults{Model: "veo-3.1-fast-generate-preview", Duration: 8}
+
+func budgetJobs(n int) []batch.BatchJob {
+	jobs := make([]batch.BatchJob, n)
+	for i := range jobs {
+		id := fmt.Sprintf("job%d", i+1)
+		jobs[i] = batch.BatchJob{ID: id, Type: "generate", Output: id + ".mp4", Options: map[string]interface{}{"prompt": "x"}}
+	}
+	return jobs
+}
+
+func TestParseManifest_Budget(t *testing.T) {
+	manifest, err := batch.ParseManifest([]byte(`
+max_jobs: 10
+max_video_seconds: 120
+max_cost: 12.5
+jobs:
+  - id: a
+    type: generate
+    options:
+      prompt: "x"
+    output: a.mp4
+`))
+	require.NoError(t, err)
+	assert.Equal(t, batch.Budget{MaxJobs: 10, MaxVideoSeconds: 120, MaxCost: 12.5}, manifest.Budget)
+
+	_, err = batch.ParseManifest([]byte("max_cost: -1\njobs:\n  - {id: a, type: generate, options: {prompt: x}, output: a.mp4}\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_cost must not be negative")
+}
+
+func TestEstimateJob(t *testing.T) {
+	tests := []struct {
+		name    string
+		job     batch.BatchJob
+		seconds int
+		cost    float64
+		priced  bool
+	}{
+		{"default duration and model", batch.BatchJob{Type: "generate"}, 8, 1.20, true},
+		{"duration option", batch.BatchJob{Type: "generate", Options: map[string]interface{}{"duration": 4}}, 4, 0.60, true},
+		{"model option", batch.BatchJob{Type: "animate", Options: map[string]interface{}{"model": "veo-3.1-generate-preview"}}, 8, 3.20, true},
+		{"interpolate", batch.BatchJob{Type: "interpolate", Options: map[string]interface{}{"duration": 4}}, 8, 1.20, true},
+		{"extend", batch.BatchJob{Type: "extend"}, 7, 1.05, true},
+		{"unknown model", batch.BatchJob{Type: "generate", Options: map[string]interface{}{"model": "custom"}}, 8, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimate := batch.EstimateJob(tt.job, budgetDefaults)
+			assert.Equal(t, tt.seconds, estimate.Seconds)
+			assert.InDelta(t, tt.cost, estimate.Cost, 1e-9)
+			assert.Equal(t, tt.priced, estimate.Priced)
+		})
+	}
+}
+
+func TestBudget_Check(t *testing.T) {
+	jobs := budgetJobs(4) // 32s, $4.80
+
+	tests := []struct {
+		name   string
+		budget batch.Budget
+		want   string
+	}{
+		{"no caps", batch.Budget{}, ""},
+		{"within caps", batch.Budget{MaxJobs: 4, MaxVideoSeconds: 32, MaxCost: 4.80}, ""},
+		{"max jobs", batch.Budget{MaxJobs: 3}, "batch has 4 jobs, over max_jobs 3"},
+		{"max seconds", batch.Budget{MaxVideoSeconds: 30}, "batch generates about 32s of video, over max_video_seconds 30"},
+		{"max cost", batch.Budget{MaxCost: 4}, "batch costs an estimated $4.80, over max_cost $4.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimate, err := tt.budget.Check(jobs, budgetDefaults)
+			assert.Equal(t, 32, estimate.VideoSeconds)
+			if tt.want == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+
+	unpriced := append(budgetJobs(1), batch.BatchJob{ID: "x", Type: "generate", Options: map[string]interface{}{"model": "custom"}})
+	_, err := batch.Budget{MaxCost: 100}.Check(unpriced, budgetDefaults)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no price known for model custom")
+}
+
+func TestProcessManifest_SkipsJobsOverBudget(t *testing.T) {
+	tests := []struct {
+		name   string
+		budget batch.Budget
+		ran    int
+		reason string
+	}{
+		{"no caps", batch.Budget{}, 5, ""},
+		{"max jobs", batch.Budget{MaxJobs: 3}, 3, "skipped: max_jobs budget of 3 reached"},
+		{"max seconds", batch.Budget{MaxVideoSeconds: 20}, 2, "skipped: max_video_seconds budget of 20 reached"},
+		{"max cost", batch.Budget{MaxCost: 5}, 4, "skipped: max_cost budget of $5.00 reached"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &recordingExecutor{}
+			processor := batch.NewProcessor(executor, 2)
+			processor.SetJobDefaults(budgetDefaults)
+
+			results, err := processor.ProcessManifest(context.Background(), &batch.BatchManifest{
+				Jobs:            budgetJobs(5),
+				Budget:          tt.budget,
+				ContinueOnError: true,
+			})
+			require.NoError(t, err)
+			assert.Len(t, executor.ranIDs(), tt.ran)
+			require.Len(t, results, 5)
+
+			summary := batch.GenerateSummary(results)
+			assert.Equal(t, tt.ran, summary.SuccessfulJobs)
+			assert.Equal(t, 5-tt.ran, summary.SkippedJobs)
+			for _, result := range results[tt.ran:] {
+				assert.True(t, result.Skipped)
+				assert.Equal(t, tt.reason, result.Error)
+			}
+		})
+	}
+}
+
+func TestProcessManifest_BudgetSkipsWaitingDependents(t *testing.T) {
+	executor := &recordingExecutor{}
+	processor := batch.NewProcessor(executor, 1)
+	processor.SetJobDefaults(budgetDefaults)
+
+	jobs := budgetJobs(3)
+	jobs[1].DependsOn = []string{"job1"}
+
+	results, err := processor.ProcessManifest(context.Background(), &batch.BatchManifest{
+		Jobs:            jobs,
+		Budget:          batch.Budget{MaxJobs: 1},
+		ContinueOnError: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"job1"}, executor.ranIDs())
+	require.Len(t, results, 3)
+	assert.Equal(t, []string{"job1", "job2", "job3"}, []string{results[0].JobID, results[1].JobID, results[2].JobID})
+	assert.Equal(t, "generate", results[1].Type, "skipped jobs are described")
+}
@@ -0,0 +1,201 @@
+package batch_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingExecutor succeeds for every job except those listed in fail and
+// records the jobs it ran, in order
+type recordingExecutor struct {
+	mu   sync.Mutex
+	fail map[string]bool
+	ran  []batch.BatchJob
+}
+
+func (e *recordingExecutor) Execute(_ context.Context, job batch.BatchJob) (*batch.JobResult, error) {
+	e.mu.Lock()
+	e.ran = append(e.ran, job)
+	e.mu.Unlock()
+
+	if e.fail[job.ID] {
+		return &batch.JobResult{JobID: job.ID, Error: "generation failed"}, nil
+	}
+	return &batch.JobResult{JobID: job.ID, Success: true, Output: "out/" + job.Output}, nil
+}
+
+func (e *recordingExecutor) ranIDs() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return jobIDs(e.ran)
+}
+
+func TestParseManifest_Dependencies(t *testing.T) {
+	manifest, err := batch.ParseManifest([]byte(`
+jobs:
+  - id: extend
+    type: extend
+    options:
+      video: "${jobs.intro.output}"
+    output: extended.mp4
+  - id: intro
+    type: generate
+    options:
+      prompt: "An intro"
+    output: intro.mp4
+  - id: outro
+    type: generate
+    depends_on: [extend]
+    options:
+      prompt: "An outro"
+    output: outro.mp4
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"extend"}, manifest.Jobs[2].DependsOn)
+	assert.True(t, manifest.Jobs[0].HasDependencies(), "references are implicit dependencies")
+	assert.False(t, manifest.Jobs[1].HasDependencies())
+}
+
+func TestParseManifest_DependencyErrors(t *testing.T) {
+	job := func(id, extra string) string {
+		return `
+  - id: ` + id + `
+    type: generate
+    options:
+      prompt: "x"` + extra + `
+    output: ` + id + `.mp4`
+	}
+
+	tests := []struct {
+		name string
+		jobs string
+		want string
+	}{
+		{"unknown", job("a", "\n    depends_on: [b]"), "job a depends on unknown job b"},
+		{"self", job("a", "\n    depends_on: [a]"), "job a depends on itself"},
+		{"unknown reference", job("a", "\n      image: \"${jobs.b.output}\""), "job a depends on unknown job b"},
+		{"invalid field", job("a", "") + job("b", "\n      image: \"${jobs.a.status}\""), "invalid reference ${jobs.a.status}"},
+		{
+			"cycle",
+			job("a", "\n    depends_on: [c]") + job("b", "\n    depends_on: [a]") + job("c", "\n      image: \"${jobs.b.output}\""),
+			"dependency cycle: a -> c -> b -> a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := batch.ParseManifest([]byte("jobs:" + tt.jobs + "\n"))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestProcessManifest_Dependencies(t *testing.T) {
+	executor := &recordingExecutor{}
+	processor := batch.NewProcessor(executor, 4)
+
+	manifest := &batch.BatchManifest{
+		Jobs: []batch.BatchJob{
+			{ID: "transition", Type: "interpolate", Output: "transition.mp4", Options: map[string]interface{}{
+				"first_frame": "${jobs.intro.output}",
+				"last_frame":  "${jobs.outro.output}",
+			}},
+			{ID: "extend", Type: "extend", Output: "extended.mp4", Options: map[string]interface{}{
+				"video": "${jobs.intro.output}",
+			}},
+			{ID: "intro", Type: "generate", Output: "intro.mp4", Options: map[string]interface{}{"prompt": "A"}},
+			{ID: "outro", Type: "generate", Output: "outro.mp4", Options: map[string]interface{}{"prompt": "B"}, DependsOn: []string{"extend"}},
+		},
+		ContinueOnError: true,
+	}
+
+	results, err := processor.ProcessManifest(context.Background(), manifest)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, []string{"intro", "extend", "outro", "transition"}, executor.ranIDs())
+
+	byID := make(map[string]batch.BatchJob)
+	for _, job := range executor.ran {
+		byID[job.ID] = job
+	}
+	assert.Equal(t, "out/intro.mp4", byID["extend"].Options["video"])
+	assert.Equal(t, "out/intro.mp4", byID["transition"].Options["first_frame"])
+	assert.Equal(t, "out/outro.mp4", byID["transition"].Options["last_frame"])
+	assert.Equal(t, "${jobs.intro.output}", manifest.Jobs[1].Options["video"], "the manifest is not modified")
+}
+
+func TestProcessManifest_SkipsDependentsOfFailedJobs(t *testing.T) {
+	executor := &recordingExecutor{fail: map[string]bool{"intro": true}}
+	processor := batch.NewProcessor(executor, 2)
+
+	manifest := &batch.BatchManifest{
+		Jobs: []batch.BatchJob{
+			{ID: "intro", Type: "generate", Output: "intro.mp4", Options: map[string]interface{}{"prompt": "A"}},
+			{ID: "extend", Type: "extend", Output: "extended.mp4", Options: map[string]interface{}{"video": "${jobs.intro.output}"}},
+			{ID: "final", Type: "extend", Output: "final.mp4", Options: map[string]interface{}{"video": "${jobs.extend.output}"}},
+			{ID: "other", Type: "generate", Output: "other.mp4", Options: map[string]interface{}{"prompt": "B"}},
+		},
+		ContinueOnError: true,
+	}
+
+	results, err := processor.ProcessManifest(context.Background(), manifest)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"intro", "other"}, executor.ranIDs())
+
+	byID := make(map[string]batch.JobResult)
+	for _, result := range results {
+		byID[result.JobID] = result
+	}
+	require.Len(t, byID, 4)
+	assert.True(t, byID["extend"].Skipped)
+	assert.Equal(t, "skipped: dependency intro did not succeed", byID["extend"].Error)
+	assert.True(t, byID["final"].Skipped)
+	assert.Equal(t, "skipped: dependency extend did not succeed", byID["final"].Error)
+	assert.True(t, byID["other"].Success)
+
+	summary := batch.GenerateSummary(results)
+	assert.Equal(t, 1, summary.SuccessfulJobs)
+	assert.Equal(t, 1, summary.FailedJobs)
+	assert.Equal(t, 2, summary.SkippedJobs)
+	assert.Contains(t, summary.FormatSummary(), "Skipped: 2")
+}
+
+func TestProcessManifest_RejectsCycles(t *testing.T) {
+	processor := batch.NewProcessor(&recordingExecutor{}, 1)
+	_, err := processor.ProcessManifest(context.Background(), &batch.BatchManifest{Jobs: []batch.BatchJob{
+		{ID: "a", DependsOn: []string{"b"}},
+		{ID: "b", DependsOn: []string{"a"}},
+	}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dependency cycle: a -> b -> a")
+}
+
+func TestResolveJobOutputs(t *testing.T) {
+	job := batch.BatchJob{ID: "final", Type: "interpolate", Options: map[string]interface{}{
+		"first_frame": "${jobs.intro.output}",
+		"last_frame":  "frames/${jobs.outro.output}",
+		"prompt":      "A transition",
+	}}
+
+	deps, err := job.Dependencies()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"intro", "outro"}, deps)
+	assert.True(t, job.UsesJobOutput("first_frame"))
+	assert.False(t, job.UsesJobOutput("prompt"))
+	assert.False(t, job.UsesJobOutput("missing"))
+
+	resolved, err := batch.ResolveJobOutputs(job, map[string]string{"intro": "out/intro.mp4", "outro": "outro.png"})
+	require.NoError(t, err)
+	assert.Equal(t, "out/intro.mp4", resolved.Options["first_frame"])
+	assert.Equal(t, "frames/outro.png", resolved.Options["last_frame"])
+	assert.Equal(t, "${jobs.intro.output}", job.Options["first_frame"], "the job is not modified")
+
+	_, err = batch.ResolveJobOutputs(job, map[string]string{"intro": "out/intro.mp4"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown jobs output in ${jobs.outro.output}")
+}
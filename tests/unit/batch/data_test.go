@@ -0,0 +1,172 @@
+package batch_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestReadDataFile(t *testing.T) {
+	dir := t.TempDir()
+
+	csvPath := writeFile(t, dir, "products.csv", "\ufeffsku, product ,duration\nW-1,a smartwatch,6\nS-2,\"sneakers, red\",\n")
+	rows, err := batch.ReadDataFile(csvPath, "")
+	require.NoError(t, err)
+	assert.Equal(t, []batch.DataRow{
+		{"sku": "W-1", "product": "a smartwatch", "duration": "6"},
+		{"sku": "S-2", "product": "sneakers, red"},
+	}, rows)
+
+	jsonlPath := writeFile(t, dir, "products.jsonl", `{"sku": "W-1", "duration": 6, "styles": ["noir", "neon"]}
+
+{"sku": "S-2", "prompt": null}
+`)
+	rows, err = batch.ReadDataFile(jsonlPath, "")
+	require.NoError(t, err)
+	assert.Equal(t, []batch.DataRow{
+		{"sku": "W-1", "duration": float64(6), "styles": []interface{}{"noir", "neon"}},
+		{"sku": "S-2"},
+	}, rows)
+}
+
+func TestReadDataFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		format  string
+		want    string
+	}{
+		{"unknown extension", "rows.txt", "a\n1\n", "", `unsupported data format "txt"`},
+		{"header only", "rows.csv", "a,b\n", "", "has no rows"},
+		{"ragged csv", "rows.csv", "a,b\n1,2,3\n", "", "invalid CSV"},
+		{"unnamed column", "rows.csv", "a,,c\n1,2,3\n", "", "CSV column 2 has no name"},
+		{"bad json", "rows.jsonl", "{\"a\": 1}\n[1]\n", "", "line 2: invalid JSON object"},
+		{"explicit format", "rows.txt", "a,b\n", "csv", "has no rows"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := batch.ReadDataFile(writeFile(t, dir, tt.file, tt.content), tt.format)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+
+	_, err := batch.ReadDataFile(filepath.Join(dir, "missing.csv"), "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read data file")
+}
+
+func TestParseManifestFile_Data(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "products.csv", `sku,product,duration,reference_images
+W-1,a smartwatch,6,"a.png, b.png"
+S-2,sneakers,,
+`)
+	manifestPath := writeFile(t, dir, "manifest.yaml", `
+jobs:
+  - id: promo
+    template: promo
+    data:
+      file: products.csv
+      id_column: sku
+    vars:
+      product: "the ${row.product}"
+    options:
+      negative_prompt: "no ${row.sku}"
+    output: videos/promo.mp4
+  - id: plain
+    type: generate
+    data: products.csv
+    options:
+      prompt: "${row.product} on a table"
+    output: "${row.sku}.mp4"
+`)
+
+	manifest, err := batch.ParseManifestFile(manifestPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{"promo-w-1", "promo-s-2", "plain-1", "plain-2"}, jobIDs(manifest.Jobs))
+
+	watch := manifest.Jobs[0]
+	assert.Nil(t, watch.Data)
+	assert.Equal(t, "videos/promo-w-1.mp4", watch.Output)
+	assert.Equal(t, "promo", watch.Template)
+	assert.Equal(t, map[string]string{
+		"sku": "W-1", "product": "the a smartwatch", "duration": "6", "reference_images": "a.png, b.png",
+	}, watch.Vars, "columns become vars; explicit vars win")
+	assert.Equal(t, 6, watch.IntOption("duration", 0), "option columns set options")
+	assert.Equal(t, []string{"a.png", "b.png"}, watch.StringSliceOption("reference_images"))
+	assert.Equal(t, "no W-1", watch.StringOption("negative_prompt", ""))
+
+	sneakers := manifest.Jobs[1]
+	_, hasDuration := sneakers.Options["duration"]
+	assert.False(t, hasDuration, "empty cells are left out")
+
+	plain := manifest.Jobs[2]
+	assert.Equal(t, "a smartwatch on a table", plain.StringOption("prompt", ""))
+	assert.Equal(t, "W-1.mp4", plain.Output)
+	assert.Nil(t, plain.Vars, "vars are only set for templated jobs")
+}
+
+func TestExpandData_Columns(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "shots.jsonl", `{"id": "Intro Shot", "output": "out/intro.mp4", "type": "generate", "prompt": "An intro"}
+{"id": "Outro", "type": "generate", "prompt": "An outro", "seed": 4}
+`)
+
+	manifest := &batch.BatchManifest{Jobs: []batch.BatchJob{{Data: &batch.DataSource{File: path}}}}
+	require.NoError(t, batch.ExpandData(manifest, ""))
+
+	require.Len(t, manifest.Jobs, 2)
+	assert.Equal(t, "intro-shot", manifest.Jobs[0].ID, "the id column is used by default")
+	assert.Equal(t, "out/intro.mp4", manifest.Jobs[0].Output, "the output column is used by default")
+	assert.Equal(t, "generate", manifest.Jobs[0].Type, "a type column sets the job type")
+	assert.Equal(t, "outro", manifest.Jobs[1].ID)
+	assert.Equal(t, "outro.mp4", manifest.Jobs[1].Output, "without an output the id names the file")
+	assert.Equal(t, 4, manifest.Jobs[1].IntOption("seed", 0))
+	require.NoError(t, batch.ValidateJob(manifest.Jobs[1]))
+}
+
+func TestParseManifest_DataErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "rows.csv", "sku,prompt\nA,x\n,y\n")
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"missing file", "data: {id_column: sku}", "data source missing required field: file"},
+		{"empty id", "data: {file: " + path + ", id_column: sku}", "job job: row 2: column sku is empty"},
+		{"unknown column", "data: " + path + "\n    options: {negative_prompt: \"${row.color}\"}", "unknown row column in ${row.color}"},
+		{"invalid row job", "data: " + filepath.Join(dir, "noprompt.csv"), "job job-1: generate job requires 'prompt' option"},
+	}
+	writeFile(t, dir, "noprompt.csv", "model\nveo\n")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := batch.ParseManifest([]byte(`
+jobs:
+  - id: job
+    type: generate
+    ` + tt.data + `
+    output: out.mp4
+`))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
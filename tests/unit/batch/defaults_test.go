@@ -0,0 +1,255 @@
+package batch_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/jasongoecke/go-veo3/pkg/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseManifest_Defaults(t *testing.T) {
+	manifest, err := batch.ParseManifest([]byte(`
+defaults:
+  options:
+    model: veo-3.1-fast-generate-preview
+    resolution: 720p
+    aspect_ratio: "16:9"
+    style: {grade: warm, grain: light}
+  types:
+    animate:
+      resolution: 1080p
+      style: {grain: none}
+jobs:
+  - id: a
+    type: generate
+    options:
+      prompt: "x"
+      aspect_ratio: "9:16"
+      style: {grade: cool}
+    output: a.mp4
+  - id: b
+    type: animate
+    options:
+      image: in.png
+      prompt: "y"
+    output: b.mp4
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"prompt":       "x",
+		"model":        "veo-3.1-fast-generate-preview",
+		"resolution":   "720p",
+		"aspect_ratio": "9:16",
+		"style":        map[string]interface{}{"grade": "cool", "grain": "light"},
+	}, manifest.Jobs[0].Options, "job options win and nested maps are merged")
+
+	assert.Equal(t, "1080p", manifest.Jobs[1].StringOption("resolution", ""), "type defaults win over options")
+	assert.Equal(t, map[string]interface{}{"grade": "warm", "grain": "none"}, manifest.Jobs[1].Options["style"])
+
+	manifest.Jobs[1].Options["style"].(map[string]interface{})["grade"] = "changed"
+	assert.Equal(t, "cool", manifest.Jobs[0].Options["style"].(map[string]interface{})["grade"], "jobs do not share defaults")
+}
+
+func TestParseManifest_DefaultsSatisfyRequiredOptions(t *testing.T) {
+	manifest, err := batch.ParseManifest([]byte(`
+defaults:
+  types:
+    extend:
+      prompt: "The action continues"
+jobs:
+  - {id: a, type: extend, options: {video: a.mp4}, output: b.mp4}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "The action continues", manifest.Jobs[0].StringOption("prompt", ""))
+
+	_, err = batch.ParseManifest([]byte(`
+defaults:
+  types:
+    upscale: {resolution: 4k}
+jobs:
+  - {id: a, type: generate, options: {prompt: x}, output: a.mp4}
+`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "defaults: types: unknown job type upscale")
+}
+
+func TestParseManifest_DefaultsExpandedJobs(t *testing.T) {
+	manifest, err := batch.ParseManifest([]byte(`
+defaults:
+  options: {model: veo-3.0-fast-generate-001}
+jobs:
+  - id: shot
+    type: generate
+    matrix:
+      seed: [1, 2]
+    options:
+      prompt: "x"
+      seed: "${matrix.seed}"
+    output: shot.mp4
+`))
+	require.NoError(t, err)
+	require.Len(t, manifest.Jobs, 2)
+	for _, job := range manifest.Jobs {
+		assert.Equal(t, "veo-3.0-fast-generate-001", job.StringOption("model", ""))
+	}
+}
+
+func TestApplyTemplates_Defaults(t *testing.T) {
+	manifest, err := batch.ParseManifest([]byte(`
+defaults:
+  options: {model: veo-3.0-generate-001, resolution: 720p}
+  types:
+    generate: {negative_prompt: blur}
+jobs:
+  - id: promo
+    template: promo
+    vars: {product: sneaker}
+    output: promo.mp4
+`))
+	require.NoError(t, err)
+
+	lookup := templateLookup(&templates.Template{Name: "promo", Prompt: "A {{product}}", Preset: &templates.Preset{
+		Model: "veo-3.1-generate-preview",
+	}})
+	require.NoError(t, batch.ApplyTemplates(manifest, lookup))
+
+	job := manifest.Jobs[0]
+	assert.Equal(t, "veo-3.1-generate-preview", job.StringOption("model", ""), "the preset wins over defaults")
+	assert.Equal(t, "720p", job.StringOption("resolution", ""))
+	assert.Equal(t, "blur", job.StringOption("negative_prompt", ""), "type defaults apply to the preset's type")
+}
+
+func TestParseManifest_Environment(t *testing.T) {
+	t.Setenv("VEO3_TEST_MODEL", "veo-3.1-generate-preview")
+	t.Setenv("VEO3_TEST_BRAND", "Acme")
+
+	manifest, err := batch.ParseManifest([]byte(`
+defaults:
+  options: {model: "${VEO3_TEST_MODEL}"}
+jobs:
+  - id: a
+    type: generate
+    matrix:
+      n: [1]
+    options:
+      prompt: "An ad for ${VEO3_TEST_BRAND} in ${VEO3_TEST_CITY:-Paris}, take ${matrix.n}"
+      reference_images: ["${VEO3_TEST_BRAND}.png"]
+      video: "${jobs.b.output}"
+    output: a.mp4
+  - {id: b, type: generate, options: {prompt: y}, output: b.mp4}
+`))
+	require.NoError(t, err)
+	job := manifest.Jobs[0]
+	assert.Equal(t, "veo-3.1-generate-preview", job.StringOption("model", ""))
+	assert.Equal(t, "An ad for Acme in Paris, take 1", job.StringOption("prompt", ""))
+	assert.Equal(t, []string{"Acme.png"}, job.StringSliceOption("reference_images"))
+	assert.Equal(t, "${jobs.b.output}", job.StringOption("video", ""), "job references are left alone")
+
+	_, err = batch.ParseManifest([]byte(`
+jobs:
+  - {id: a, type: generate, options: {prompt: "Hi ${VEO3_TEST_UNSET}"}, output: a.mp4}
+`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "job a: option prompt: environment variable VEO3_TEST_UNSET is not set")
+
+	_, err = batch.ParseManifest([]byte(`
+defaults:
+  types:
+    generate: {model: "${VEO3_TEST_UNSET}"}
+jobs:
+  - {id: a, type: generate, options: {prompt: x}, output: a.mp4}
+`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "defaults: types: generate: option model: environment variable VEO3_TEST_UNSET is not set")
+}
+
+func TestParseManifestFile_Include(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "shared"), 0750))
+	writeFile(t, dir, "shared/prompts.csv", "id,prompt\nr1,first\n")
+	writeFile(t, dir, "shared/library.yaml", `
+defaults:
+  options: {resolution: 1080p}
+jobs:
+  - id: intro
+    type: generate
+    options: {prompt: "intro"}
+    output: intro.mp4
+  - id: row
+    type: generate
+    data: {file: prompts.csv}
+    options: {prompt: "${row.prompt}"}
+`)
+	path := writeFile(t, dir, "main.yaml", `
+include: [shared/library.yaml]
+defaults:
+  options: {resolution: 720p, model: veo-3.0-generate-001}
+jobs:
+  - {id: main, type: generate, options: {prompt: "main"}, output: main.mp4}
+`)
+
+	manifest, err := batch.ParseManifestFile(path)
+	require.NoError(t, err)
+	require.Len(t, manifest.Jobs, 3)
+	assert.Equal(t, []string{"intro", "row-r1", "main"}, []string{manifest.Jobs[0].ID, manifest.Jobs[1].ID, manifest.Jobs[2].ID})
+	assert.Equal(t, "1080p", manifest.Jobs[0].StringOption("resolution", ""), "the included manifest's defaults win")
+	assert.Equal(t, "veo-3.0-generate-001", manifest.Jobs[1].StringOption("model", ""))
+	assert.Equal(t, "720p", manifest.Jobs[2].StringOption("resolution", ""))
+	assert.Empty(t, manifest.Include)
+}
+
+func TestParseManifestFile_IncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.yaml", "include: [b.yaml]\njobs: []\n")
+	writeFile(t, dir, "b.yaml", "include: [a.yaml]\njobs: []\n")
+	writeFile(t, dir, "bad.yaml", "jobs:\n  - {id: x, type: generate, options: {prompt: \"${VEO3_TEST_UNSET}\"}, output: x.mp4}\n")
+
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+	}{
+		{"cycle", "include: [a.yaml]\n", []string{"include a.yaml: include b.yaml: include a.yaml: include cycle:", "a.yaml -> ", "b.yaml -> "}},
+		{"missing", "include: [nope.yaml]\n", []string{"include nope.yaml:", "no such file"}},
+		{"nested error", "include: [bad.yaml]\n", []string{"include bad.yaml: job x: option prompt: environment variable VEO3_TEST_UNSET is not set"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, dir, "main.yaml", tt.manifest+"jobs:\n  - {id: m, type: generate, options: {prompt: x}, output: m.mp4}\n")
+			_, err := batch.ParseManifestFile(path)
+			require.Error(t, err)
+			for _, want := range tt.want {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+
+	self := writeFile(t, dir, "self.yaml", "include: [self.yaml]\njobs:\n  - {id: m, type: generate, options: {prompt: x}, output: m.mp4}\n")
+	_, err := batch.ParseManifestFile(self)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "include cycle")
+}
+
+func TestParseRemoteManifest(t *testing.T) {
+	t.Setenv("VEO3_TEST_SECRET", "secret")
+
+	manifest, err := batch.ParseRemoteManifest([]byte(`
+jobs:
+  - {id: a, type: generate, options: {prompt: "${VEO3_TEST_SECRET}"}, output: a.mp4}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "${VEO3_TEST_SECRET}", manifest.Jobs[0].StringOption("prompt", ""), "the server environment is not read")
+
+	_, err = batch.ParseRemoteManifest([]byte("include: [/etc/passwd]\njobs:\n  - {id: a, type: generate, options: {prompt: x}, output: a.mp4}\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "includes are not allowed in submitted manifests")
+
+	_, err = batch.ParseRemoteManifest([]byte("jobs:\n  - {id: rows, type: generate, data: {file: /etc/passwd}, options: {prompt: x}, output: a.mp4}\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "job rows: data sources are not allowed in submitted manifests")
+}
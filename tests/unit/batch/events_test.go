@@ -0,0 +1,268 @@
+package batch_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reportingExecutor reports an operation, two polls and a download for every
+// attempt, failing the first attempt of the jobs listed in flaky
+type reportingExecutor struct {
+	mu    sync.Mutex
+	flaky map[string]bool
+}
+
+func (e *reportingExecutor) Execute(ctx context.Context, job batch.BatchJob) (*batch.JobResult, error) {
+	reporter := batch.Reporter(ctx)
+	reporter.OperationSubmitted("operations/" + job.ID)
+	reporter.Polled(&veo3.Operation{Progress: 40})
+	reporter.Polled(&veo3.Operation{Progress: 90})
+
+	e.mu.Lock()
+	fail := e.flaky[job.ID]
+	delete(e.flaky, job.ID)
+	e.mu.Unlock()
+	if fail {
+		return &batch.JobResult{JobID: job.ID, Error: "HTTP 503"}, nil
+	}
+
+	for written := int64(0); written <= 1000; written += 5 {
+		reporter.Downloading(written, 1000)
+	}
+	return &batch.JobResult{JobID: job.ID, Success: true, Output: job.Output}, nil
+}
+
+// describeEvent summarizes an event for comparison
+func describeEvent(event batch.Event) string {
+	switch e := event.(type) {
+	case *batch.JobQueuedEvent:
+		return fmt.Sprintf("queued %s %v", e.JobID, e.DependsOn)
+	case *batch.JobStartedEvent:
+		return fmt.Sprintf("started %s #%d", e.JobID, e.Attempt)
+	case *batch.OperationSubmittedEvent:
+		return fmt.Sprintf("submitted %s #%d %s", e.JobID, e.Attempt, e.OperationID)
+	case *batch.JobProgressEvent:
+		if e.Stage == batch.StageDownloading {
+			return fmt.Sprintf("downloading %s %d/%d", e.JobID, e.Bytes, e.TotalBytes)
+		}
+		return fmt.Sprintf("polled %s #%d %d %.0f%%", e.JobID, e.Attempt, e.Polls, e.Percent)
+	case *batch.JobRetryingEvent:
+		return fmt.Sprintf("retrying %s after #%d of %d", e.JobID, e.Attempt.Attempt, e.MaxAttempts)
+	case *batch.JobFinishedEvent:
+		return fmt.Sprintf("finished %s %s", e.Result.JobID, e.Result.Status())
+	case *batch.BatchFinishedEvent:
+		return fmt.Sprintf("batch finished %d", len(e.Results))
+	}
+	return "unknown"
+}
+
+func TestProcessor_Events(t *testing.T) {
+	processor := batch.NewProcessor(&reportingExecutor{flaky: map[string]bool{"intro": true}}, 1)
+	var events []string
+	var last batch.Event
+	processor.OnEvent(func(event batch.Event) {
+		if progress, ok := event.(*batch.JobProgressEvent); ok && progress.Stage == batch.StageDownloading &&
+			progress.Bytes != 0 && progress.Bytes != progress.TotalBytes {
+			return // checked below
+		}
+		events = append(events, describeEvent(event))
+		last = event
+	})
+
+	results, err := processor.ProcessManifest(context.Background(), &batch.BatchManifest{
+		Jobs: []batch.BatchJob{
+			{ID: "intro", Type: "generate", Output: "intro.mp4", Options: map[string]interface{}{"prompt": "x"}},
+			{ID: "extend", Type: "extend", Output: "extend.mp4", Options: map[string]interface{}{"video": "${jobs.intro.output}"}},
+		},
+		Retry:           &batch.RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond},
+		ContinueOnError: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"queued intro []",
+		"queued extend [intro]",
+		"started intro #1",
+		"submitted intro #1 operations/intro",
+		"polled intro #1 1 40%",
+		"polled intro #1 2 90%",
+		"retrying intro after #1 of 2",
+		"started intro #2",
+		"submitted intro #2 operations/intro",
+		"polled intro #2 1 40%",
+		"polled intro #2 2 90%",
+		"downloading intro 0/1000",
+		"downloading intro 1000/1000",
+		"finished intro succeeded",
+		"started extend #1",
+		"submitted extend #1 operations/extend",
+		"polled extend #1 1 40%",
+		"polled extend #1 2 90%",
+		"downloading extend 0/1000",
+		"downloading extend 1000/1000",
+		"finished extend succeeded",
+		"batch finished 2",
+	}, events)
+
+	finished, ok := last.(*batch.BatchFinishedEvent)
+	require.True(t, ok)
+	assert.Equal(t, results, finished.Results)
+	assert.NoError(t, finished.Err)
+}
+
+func TestProcessor_EventsForSkippedJobs(t *testing.T) {
+	processor := batch.NewProcessor(&recordingExecutor{fail: map[string]bool{"job1": true}}, 1)
+	var finished []string
+	processor.OnEvent(func(event batch.Event) {
+		if e, ok := event.(*batch.JobFinishedEvent); ok {
+			finished = append(finished, e.Result.JobID+" "+e.Result.Status())
+		}
+	})
+
+	jobs := budgetJobs(3)
+	jobs[1].DependsOn = []string{"job1"}
+	_, err := processor.ProcessManifest(context.Background(), &batch.BatchManifest{
+		Jobs: jobs, Budget: batch.Budget{MaxJobs: 1}, ContinueOnError: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"job1 failed", "job2 skipped", "job3 skipped"}, finished,
+		"every job finishes exactly once")
+}
+
+func TestProcessor_EventsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	processor := batch.NewProcessor(executorFunc(func(ctx context.Context, job batch.BatchJob) (*batch.JobResult, error) {
+		batch.Reporter(ctx).OperationSubmitted("operations/" + job.ID)
+		<-ctx.Done()
+		batch.Reporter(ctx).Polled(nil) // late progress still precedes the job's end
+		return nil, ctx.Err()
+	}), 2)
+
+	var events []string
+	submitted := 0
+	processor.OnEvent(func(event batch.Event) {
+		if _, ok := event.(*batch.OperationSubmittedEvent); ok {
+			if submitted++; submitted == 2 {
+				cancel() // both running jobs have submitted
+			}
+		}
+		events = append(events, describeEvent(event))
+	})
+
+	results, err := processor.ProcessManifest(ctx, &batch.BatchManifest{Jobs: budgetJobs(3), ContinueOnError: true})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, results, 3)
+
+	var finished []string
+	for _, event := range events {
+		if strings.HasPrefix(event, "finished") {
+			finished = append(finished, event)
+		}
+	}
+	assert.ElementsMatch(t, []string{"finished job1 failed", "finished job2 failed", "finished job3 skipped"}, finished,
+		"running jobs finish and unstarted jobs are skipped")
+	assert.Equal(t, "batch finished 3", events[len(events)-1])
+}
+
+func TestProcessor_EventsWhenStoppedOnError(t *testing.T) {
+	processor := batch.NewProcessor(executorFunc(func(ctx context.Context, job batch.BatchJob) (*batch.JobResult, error) {
+		return nil, fmt.Errorf("critical error")
+	}), 1)
+
+	var finished []batch.JobResult
+	var batchFinished *batch.BatchFinishedEvent
+	processor.OnEvent(func(event batch.Event) {
+		switch e := event.(type) {
+		case *batch.JobFinishedEvent:
+			finished = append(finished, e.Result)
+		case *batch.BatchFinishedEvent:
+			batchFinished = e
+		}
+	})
+
+	results, err := processor.ProcessManifest(context.Background(), &batch.BatchManifest{Jobs: budgetJobs(2)})
+	require.EqualError(t, err, "critical error")
+	require.Len(t, finished, 2)
+	assert.Equal(t, "critical error", finished[0].Error)
+	assert.Equal(t, "skipped: batch stopped after an error", finished[1].Error)
+	require.NotNil(t, batchFinished)
+	assert.Equal(t, results, batchFinished.Results)
+	assert.Equal(t, finished, results, "the failed job's result is among the results")
+}
+
+func TestProcessor_OnEventFromHandler(t *testing.T) {
+	processor := batch.NewProcessor(&recordingExecutor{}, 1)
+
+	var late []string
+	processor.OnEvent(func(event batch.Event) {
+		if _, ok := event.(*batch.JobFinishedEvent); ok && late == nil {
+			late = []string{}
+			processor.OnEvent(func(event batch.Event) {
+				late = append(late, describeEvent(event))
+			})
+		}
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := processor.ProcessManifest(context.Background(), &batch.BatchManifest{Jobs: budgetJobs(2), ContinueOnError: true})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("registering a handler from a handler deadlocked")
+	}
+	assert.Equal(t, []string{"started job2 #1", "finished job2 succeeded", "batch finished 2"}, late,
+		"the new handler receives the events after the current one")
+}
+
+func TestJobReporter_DownloadProgress(t *testing.T) {
+	processor := batch.NewProcessor(batch.JobExecutor(executorFunc(func(ctx context.Context, job batch.BatchJob) (*batch.JobResult, error) {
+		reporter := batch.Reporter(ctx)
+		for written := int64(0); written <= 1000; written++ {
+			reporter.Downloading(written, 1000)
+		}
+		// Without a size, every megabyte is reported
+		for written := int64(0); written <= 3<<20; written += 1 << 18 {
+			reporter.Downloading(written, 0)
+		}
+		return &batch.JobResult{JobID: job.ID, Success: true}, nil
+	})), 1)
+
+	var known, unknown int
+	processor.OnEvent(func(event batch.Event) {
+		if e, ok := event.(*batch.JobProgressEvent); ok {
+			if e.TotalBytes > 0 {
+				known++
+			} else {
+				unknown++
+			}
+		}
+	})
+	_, err := processor.ProcessManifest(context.Background(), &batch.BatchManifest{Jobs: budgetJobs(1), ContinueOnError: true})
+	require.NoError(t, err)
+	assert.Equal(t, 101, known, "the start and every percent")
+	assert.Equal(t, 4, unknown, "the start and every megabyte")
+
+	assert.Nil(t, batch.Reporter(context.Background()))
+	assert.NotPanics(t, func() {
+		batch.Reporter(context.Background()).OperationSubmitted("operations/x")
+	})
+}
+
+type executorFunc func(ctx context.Context, job batch.BatchJob) (*batch.JobResult, error)
+
+func (f executorFunc) Execute(ctx context.Context, job batch.BatchJob) (*batch.JobResult, error) {
+	return f(ctx, job)
+}
@@ -0,0 +1,182 @@
+package batch_test
+
+import (
+	"testing"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func jobIDs(jobs []batch.BatchJob) []string {
+	ids := make([]string, len(jobs))
+	for i, job := range jobs {
+		ids[i] = job.ID
+	}
+	return ids
+}
+
+func TestParseManifest_Matrix(t *testing.T) {
+	manifest, err := batch.ParseManifest([]byte(`
+jobs:
+  - id: pets
+    type: generate
+    matrix:
+      prompt: ["A cat", "A dog"]
+      aspect_ratio: ["16:9", "9:16"]
+      seed: [1, 2]
+      exclude:
+        - {prompt: "A dog", aspect_ratio: "9:16"}
+      include:
+        - {prompt: "A bird", aspect_ratio: "16:9", seed: 7}
+    options:
+      duration: 8
+    output: pets.mp4
+  - id: single
+    type: generate
+    options:
+      prompt: "Unchanged"
+    output: single.mp4
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"pets-a-cat-16-9-1", "pets-a-cat-16-9-2", "pets-a-cat-9-16-1", "pets-a-cat-9-16-2",
+		"pets-a-dog-16-9-1", "pets-a-dog-16-9-2",
+		"pets-a-bird-16-9-7",
+		"single",
+	}, jobIDs(manifest.Jobs))
+
+	first := manifest.Jobs[0]
+	assert.Equal(t, "pets-a-cat-16-9-1.mp4", first.Output)
+	assert.Equal(t, "A cat", first.StringOption("prompt", ""))
+	assert.Equal(t, "16:9", first.StringOption("aspect_ratio", ""))
+	assert.Equal(t, 1, first.IntOption("seed", 0))
+	assert.Equal(t, 8, first.IntOption("duration", 0))
+	assert.Nil(t, first.Matrix)
+
+	assert.Equal(t, 7, manifest.Jobs[6].IntOption("seed", 0))
+	assert.Equal(t, "single.mp4", manifest.Jobs[7].Output)
+}
+
+func TestParseManifest_MatrixReferences(t *testing.T) {
+	manifest, err := batch.ParseManifest([]byte(`
+jobs:
+  - id: "${matrix.subject}-${matrix.seed}"
+    template: scene
+    matrix:
+      subject: [fox, owl]
+      seed: [3]
+    vars:
+      subject: "a ${matrix.subject}"
+    options:
+      negative_prompt: "no ${matrix.subject}s"
+      seed: "${matrix.seed}"
+    output: "renders/${matrix.subject}/${matrix.seed}.mp4"
+`))
+	require.NoError(t, err)
+
+	require.Len(t, manifest.Jobs, 2)
+	fox := manifest.Jobs[0]
+	assert.Equal(t, "fox-3", fox.ID)
+	assert.Equal(t, "renders/fox/3.mp4", fox.Output)
+	assert.Equal(t, "a fox", fox.Vars["subject"])
+	assert.Equal(t, "no foxs", fox.StringOption("negative_prompt", ""))
+	assert.Equal(t, 3, fox.Options["seed"], "a whole-value reference keeps the matrix value's type")
+	assert.Equal(t, "owl-3", manifest.Jobs[1].ID)
+}
+
+func TestParseManifest_MatrixSlugCollision(t *testing.T) {
+	manifest, err := batch.ParseManifest([]byte(`
+jobs:
+  - id: ratio
+    type: generate
+    matrix:
+      aspect_ratio: ["16:9", "16-9"]
+    options:
+      prompt: "x"
+    output: ratio.mp4
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ratio-16-9", "ratio-16-9-2"}, jobIDs(manifest.Jobs))
+	assert.Equal(t, "ratio-16-9-2.mp4", manifest.Jobs[1].Output)
+}
+
+func TestParseManifest_MatrixSlugCollisionAvoidsOtherIDs(t *testing.T) {
+	manifest, err := batch.ParseManifest([]byte(`
+jobs:
+  - id: ratio
+    type: generate
+    matrix:
+      aspect_ratio: ["16:9", "16-9", "16 9 2", "16.9.2"]
+    options:
+      prompt: "x"
+    output: ratio.mp4
+  - id: "shot-${matrix.style}"
+    type: generate
+    matrix:
+      style: [noir, noir-2]
+      seed: [1, 2]
+    options:
+      prompt: "x"
+    output: "${matrix.style}.mp4"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"ratio-16-9", "ratio-16-9-3", "ratio-16-9-2", "ratio-16-9-2-4",
+		"shot-noir", "shot-noir-3", "shot-noir-2", "shot-noir-2-4",
+	}, jobIDs(manifest.Jobs), "fallback IDs skip IDs that other values produce")
+
+	var outputs []string
+	for _, job := range manifest.Jobs {
+		outputs = append(outputs, job.Output)
+	}
+	assert.Equal(t, []string{
+		"ratio-16-9.mp4", "ratio-16-9-3.mp4", "ratio-16-9-2.mp4", "ratio-16-9-2-4.mp4",
+		"noir.mp4", "noir-3.mp4", "noir-2.mp4", "noir-2-4.mp4",
+	}, outputs, "outputs get the same suffix as IDs")
+}
+
+func TestParseManifest_MatrixErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix string
+		want   string
+	}{
+		{"empty dimension", "matrix:\n      seed: []", "matrix seed has no values"},
+		{"not a list", "matrix:\n      seed: 3", "matrix seed must be a list"},
+		{"unknown exclude", "matrix:\n      seed: [1]\n      exclude: [{model: x}]", "unknown key model"},
+		{"all excluded", "matrix:\n      seed: [1]\n      exclude: [{seed: 1}]", "matrix produces no jobs"},
+		{"unknown ref", "matrix:\n      seed: [1]\n    options: {prompt: \"${matrix.sede}\"}", "${matrix.sede}"},
+		{"invalid combination", "matrix:\n      seed: [1]", "job job-1: generate job requires 'prompt' option"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := batch.ParseManifest([]byte(`
+jobs:
+  - id: job
+    type: generate
+    ` + tt.matrix + `
+    output: out.mp4
+`))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestMatrix_YAMLRoundTrip(t *testing.T) {
+	var job batch.BatchJob
+	require.NoError(t, yaml.Unmarshal([]byte(`
+id: j
+matrix:
+  seed: [1, 2]
+  prompt: [a]
+  exclude: [{seed: 2}]
+`), &job))
+
+	data, err := yaml.Marshal(job.Matrix)
+	require.NoError(t, err)
+	assert.Equal(t, "seed:\n    - 1\n    - 2\nprompt:\n    - a\nexclude:\n    - seed: 2\n", string(data))
+}
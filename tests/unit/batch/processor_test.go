@@ -161,9 +161,15 @@ func TestProcessManifest_StopOnError(t *testing.T) {
 
 	// Should return error and partial results
 	assert.Error(t, err)
-	assert.NotNil(t, results)
+	require.Len(t, results, 3)
+	assert.True(t, results[0].Success)
+	assert.Equal(t, "critical error", results[1].Error)
 	// Job 3 should not have been attempted
-	assert.LessOrEqual(t, len(results), 2)
+	assert.True(t, results[2].Skipped)
+	assert.Equal(t, "skipped: batch stopped after an error", results[2].Error)
+	executor.AssertNotCalled(t, "Execute", mock.Anything, mock.MatchedBy(func(j batch.BatchJob) bool {
+		return j.ID == "job3"
+	}))
 }
 
 func TestProcessManifest_Concurrency(t *testing.T) {
@@ -0,0 +1,217 @@
+package batch_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a time source tests advance by hand
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestProgress(mode batch.ProgressMode, jobs ...batch.BatchJob) (*batch.Progress, *bytes.Buffer, *fakeClock) {
+	var out bytes.Buffer
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	progress := batch.NewProgress(&out, mode, jobs)
+	progress.Now = clock.Now
+	return progress, &out, clock
+}
+
+func progressJobs() []batch.BatchJob {
+	return []batch.BatchJob{
+		{ID: "intro", Type: "generate"},
+		{ID: "extend", Type: "extend"},
+		{ID: "outro", Type: "generate"},
+	}
+}
+
+func TestProgress_LineMode(t *testing.T) {
+	progress, out, clock := newTestProgress(batch.ProgressLines, progressJobs()...)
+	progress.Start(0)
+
+	progress.JobStarted("intro")
+	progress.JobSubmitted("intro", "models/veo/operations/abc123")
+	clock.advance(5 * time.Second)
+	progress.JobPolled("intro", &veo3.Operation{Progress: 50})
+	progress.JobDownloading("intro", 0, 2048)
+	progress.JobDownloading("intro", 1024, 2048)
+	clock.advance(10 * time.Second)
+	progress.JobFinished(batch.JobResult{JobID: "intro", Success: true, Output: "intro.mp4"})
+	progress.Stop([]batch.JobResult{
+		{JobID: "intro", Success: true},
+		{JobID: "extend", Skipped: true, Error: "skipped: dependency intro did not succeed"},
+	})
+
+	assert.Equal(t, strings.Join([]string{
+		"[00:00] ▶️  intro: submitting generate job",
+		"[00:00] ⏳ intro: generating (models/veo/operations/abc123)",
+		"[00:05] ⬇️  intro: downloading 2.0 KB",
+		"[00:15] ✅ intro: intro.mp4 (15s)",
+		"[00:15] ⏭️  extend: skipped: dependency intro did not succeed",
+		"[00:15] Jobs: 0 running, 1 done, 0 failed, 1 skipped, 1 waiting (15s elapsed)",
+	}, "\n")+"\n", out.String(), "polls and download progress are not logged")
+}
+
+func TestProgress_Retrying(t *testing.T) {
+	progress, out, clock := newTestProgress(batch.ProgressLines, progressJobs()...)
+	progress.Start(0)
+
+	progress.JobStarted("intro")
+	progress.JobSubmitted("intro", "operations/op1")
+	clock.advance(5 * time.Second)
+	progress.JobFinished(batch.JobResult{JobID: "intro", Error: "HTTP 503"})
+	progress.JobRetrying(&batch.JobRetryingEvent{JobID: "intro", Attempt: batch.JobAttempt{Attempt: 1, Error: "HTTP 503"}, MaxAttempts: 3})
+	assert.Equal(t, batch.JobRetrying, progress.Jobs()[0].State)
+	clock.advance(5 * time.Second)
+	progress.JobStarted("intro")
+
+	job := progress.Jobs()[0]
+	assert.Equal(t, batch.JobSubmitting, job.State)
+	assert.Equal(t, 2, job.Attempt)
+	assert.Empty(t, job.OperationID, "the next attempt starts afresh")
+	assert.Contains(t, progress.Frame(), "attempt 2/3")
+	assert.Contains(t, out.String(), "[00:05] 🔁 intro: retrying, attempt 2 of 3\n")
+	assert.Contains(t, out.String(), "[00:10] ▶️  intro: submitting generate job\n")
+}
+
+func TestProgress_JSONMode(t *testing.T) {
+	progress, out, clock := newTestProgress(batch.ProgressJSON, progressJobs()...)
+	progress.Start(time.Second)
+
+	progress.JobStarted("intro")
+	progress.JobSubmitted("intro", "operations/abc")
+	clock.advance(2 * time.Second)
+	progress.JobPolled("intro", nil)
+	progress.JobDownloading("intro", 0, 1000)
+	progress.JobDownloading("intro", 50, 1000)
+	progress.JobDownloading("intro", 500, 1000)
+	progress.JobFinished(batch.JobResult{JobID: "intro", Error: "download failed"})
+	progress.Stop(nil)
+
+	var events []batch.ProgressEvent
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var event batch.ProgressEvent
+		require.NoError(t, json.Unmarshal([]byte(line), &event), line)
+		events = append(events, event)
+	}
+
+	var names []string
+	for _, event := range events {
+		names = append(names, event.Event)
+	}
+	assert.Equal(t, []string{
+		"job_started", "job_submitted", "job_polled", "job_downloading",
+		"job_download_progress", "job_finished", "batch_finished",
+	}, names, "download progress is reported every 10%")
+
+	assert.Equal(t, "operations/abc", events[2].OperationID)
+	assert.Equal(t, 1, events[2].Polls)
+	assert.Equal(t, 2.0, events[2].Elapsed)
+	assert.Equal(t, int64(500), events[4].Bytes)
+	assert.Equal(t, batch.JobFailed, events[5].State)
+	assert.Equal(t, "download failed", events[5].Error)
+
+	require.NotNil(t, events[6].Counts)
+	assert.Equal(t, batch.ProgressCounts{Failed: 1, Waiting: 2}, *events[6].Counts)
+}
+
+func TestProgress_Frame(t *testing.T) {
+	progress, _, clock := newTestProgress(batch.ProgressTTY, progressJobs()...)
+	progress.Start(0)
+	defer progress.Stop(nil)
+
+	progress.JobStarted("intro")
+	progress.JobSubmitted("intro", "models/veo/operations/abc123")
+	progress.JobStarted("extend")
+	clock.advance(65 * time.Second)
+	progress.JobPolled("intro", &veo3.Operation{Progress: 40})
+	progress.JobPolled("intro", nil)
+
+	lines := strings.Split(strings.TrimRight(progress.Frame(), "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "  intro   generating   abc123             1m05s  poll 2, 40%", lines[0])
+	assert.Equal(t, "  extend  submitting                      1m05s", lines[1])
+	assert.Equal(t, "Jobs: 2 running, 0 done, 0 failed, 1 waiting (1m05s elapsed)", lines[2])
+
+	progress.Width = 20
+	for _, line := range strings.Split(strings.TrimRight(progress.Frame(), "\n"), "\n") {
+		assert.LessOrEqual(t, len([]rune(line)), 20)
+	}
+}
+
+func TestProgress_TTYPrintsFinishedJobs(t *testing.T) {
+	progress, out, _ := newTestProgress(batch.ProgressTTY, progressJobs()...)
+	progress.Start(0)
+
+	progress.JobStarted("intro")
+	progress.JobFinished(batch.JobResult{JobID: "intro", Success: true, Output: "intro.mp4"})
+	progress.Stop(nil)
+
+	assert.Contains(t, out.String(), "\033[", "the live view is redrawn in place")
+	assert.Contains(t, out.String(), "✅ intro: intro.mp4 (0s)\n")
+	assert.True(t, strings.HasSuffix(out.String(), "Jobs: 0 running, 1 done, 0 failed, 2 waiting (0s elapsed)\n"))
+}
+
+func TestProgress_HandleEvent(t *testing.T) {
+	progress, out, _ := newTestProgress(batch.ProgressJSON, progressJobs()...)
+	processor := batch.NewProcessor(&recordingExecutor{fail: map[string]bool{"intro": true}}, 1)
+	processor.OnEvent(progress.HandleEvent)
+
+	manifest := &batch.BatchManifest{
+		Jobs: []batch.BatchJob{
+			{ID: "intro", Type: "generate", Output: "intro.mp4"},
+			{ID: "extend", Type: "extend", Output: "extended.mp4", DependsOn: []string{"intro"}},
+			{ID: "outro", Type: "generate", Output: "outro.mp4"},
+		},
+		ContinueOnError: true,
+	}
+
+	progress.Start(0)
+	_, err := processor.ProcessManifest(context.Background(), manifest)
+	require.NoError(t, err)
+
+	states := make(map[string]string)
+	for _, job := range progress.Jobs() {
+		states[job.JobID] = job.State
+	}
+	assert.Equal(t, map[string]string{
+		"intro":  batch.JobFailed,
+		"extend": batch.JobSkipped,
+		"outro":  batch.JobSucceeded,
+	}, states, "skipped jobs are recorded before Stop")
+	assert.Contains(t, out.String(), `"event":"job_skipped","time":"2025-01-01T12:00:00Z","job_id":"extend"`)
+	progress.Stop(nil)
+}
+
+func TestProgress_Nil(t *testing.T) {
+	var progress *batch.Progress
+
+	assert.NotPanics(t, func() {
+		progress.Start(time.Second)
+		progress.JobStarted("intro")
+		progress.JobPolled("intro", nil)
+		progress.JobDownloading("intro", 1, 2)
+		progress.JobFinished(batch.JobResult{JobID: "intro"})
+		progress.HandleEvent(&batch.JobRetryingEvent{JobID: "intro"})
+		progress.Stop(nil)
+	})
+	assert.Empty(t, progress.Jobs())
+}
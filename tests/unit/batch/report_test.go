@@ -0,0 +1,171 @@
+package batch_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reportSummary() batch.BatchSummary {
+	return batch.GenerateSummary([]batch.JobResult{
+		{
+			JobID: "intro", Success: true, Output: "videos/intro clip.mp4", Duration: 40 * time.Second,
+			Type: "generate", Model: "veo-3.0", OperationID: "operations/op1",
+			Options: map[string]interface{}{"prompt": "A <sunrise> | timelapse", "duration": 8},
+		},
+		{
+			JobID: "outro", Error: "quota exceeded", Duration: 20 * time.Second,
+			Type: "generate", Model: "veo-3.0", OperationID: "operations/op2",
+			Options: map[string]interface{}{"prompt": "A sunset"},
+			Attempts: []batch.JobAttempt{
+				{Attempt: 1, Duration: 5 * time.Second, Error: "HTTP 503", ErrorClass: batch.ErrorTransient},
+				{Attempt: 2, Duration: 15 * time.Second, OperationID: "operations/op2", Error: "quota exceeded", ErrorClass: batch.ErrorOther},
+			},
+		},
+		{
+			JobID: "extend", Success: true, Output: "videos/extend.mp4", Duration: 90 * time.Second,
+			Type: "extend", Model: "veo-3.1",
+		},
+		{
+			JobID: "final", Skipped: true, Error: "skipped: dependency outro did not succeed",
+			Type: "extend", Model: "veo-3.1",
+		},
+	})
+}
+
+func TestParseReportFormat(t *testing.T) {
+	for name, want := range map[string]string{
+		"html": batch.ReportHTML, "MD": batch.ReportMarkdown, "markdown": batch.ReportMarkdown,
+		"junit": batch.ReportJUnit, " xml ": batch.ReportJUnit,
+	} {
+		format, err := batch.ParseReportFormat(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, format, name)
+	}
+
+	_, err := batch.ParseReportFormat("pdf")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unsupported report format "pdf"`)
+
+	assert.Equal(t, ".html", batch.ReportExtension(batch.ReportHTML))
+	assert.Equal(t, ".md", batch.ReportExtension(batch.ReportMarkdown))
+	assert.Equal(t, ".xml", batch.ReportExtension(batch.ReportJUnit))
+}
+
+func TestModelStatistics(t *testing.T) {
+	stats := reportSummary().ModelStatistics()
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, "veo-3.0", stats[0].Model)
+	assert.Equal(t, 2, stats[0].Jobs)
+	assert.Equal(t, 1, stats[0].Succeeded)
+	assert.Equal(t, 1, stats[0].Failed)
+	assert.Equal(t, 30*time.Second, stats[0].Mean())
+	assert.Equal(t, 20*time.Second, stats[0].Min)
+	assert.Equal(t, 40*time.Second, stats[0].Max)
+
+	assert.Equal(t, "veo-3.1", stats[1].Model)
+	assert.Equal(t, 1, stats[1].Jobs, "skipped jobs are not counted")
+}
+
+func TestWriteReport_HTML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, batch.WriteReport(&buf, batch.ReportHTML, reportSummary()))
+	html := buf.String()
+
+	assert.Contains(t, html, "4 jobs: 2 succeeded, 1 failed, 1 skipped")
+	assert.Contains(t, html, "A &lt;sunrise&gt; | timelapse", "text is escaped")
+	assert.Contains(t, html, "<code>duration: 8</code>")
+	assert.Contains(t, html, `<video src="videos/intro%20clip.mp4" controls`)
+	assert.Contains(t, html, `<pre class="error">quota exceeded</pre>`)
+	assert.Contains(t, html, `<section class="job skipped">`)
+	assert.Contains(t, html, "attempt 1 failed (transient) after 5s: HTTP 503<br>attempt 2 failed (other)")
+	assert.Contains(t, html, "<td>veo-3.0</td><td>2</td><td>1</td><td>1</td><td>30s</td><td>20s</td><td>40s</td>")
+	assert.Equal(t, 2, strings.Count(html, "<video "), "only successful jobs embed their video")
+}
+
+func TestWriteReport_Markdown(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, batch.WriteReport(&buf, batch.ReportMarkdown, reportSummary()))
+	md := buf.String()
+
+	assert.Contains(t, md, "| veo-3.1 | 1 | 1 | 0 | 1m30s | 1m30s | 1m30s |")
+	assert.Contains(t, md, "| intro | succeeded | generate | veo-3.0 | 40s | operations/op1 | [videos/intro clip.mp4](videos/intro%20clip.mp4) |")
+	assert.Contains(t, md, "| final | skipped | extend | veo-3.1 |  |  |  |")
+	assert.Contains(t, md, "- **Prompt:** A <sunrise> | timelapse")
+	assert.Contains(t, md, "- **Parameters:** `duration: 8`")
+	assert.Contains(t, md, "### outro (failed)\n\n- **Prompt:** A sunset\n- **Error:** quota exceeded\n- **Attempts:**\n"+
+		"  - attempt 1 failed (transient) after 5s: HTTP 503\n"+
+		"  - attempt 2 failed (other) after 15s, operation operations/op2: quota exceeded\n")
+}
+
+func TestWriteReport_JUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, batch.WriteReport(&buf, batch.ReportJUnit, reportSummary()))
+
+	var report struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Skipped  int `xml:"skipped,attr"`
+		Suites   []struct {
+			Cases []struct {
+				Name      string `xml:"name,attr"`
+				ClassName string `xml:"classname,attr"`
+				Time      string `xml:"time,attr"`
+				Failure   *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"failure"`
+				Skipped *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"skipped"`
+				SystemOut string `xml:"system-out"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+
+	assert.Equal(t, 4, report.Tests)
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, 1, report.Skipped)
+	require.Len(t, report.Suites, 1)
+	cases := report.Suites[0].Cases
+	require.Len(t, cases, 4)
+
+	assert.Equal(t, "intro", cases[0].Name)
+	assert.Equal(t, "veo3.batch.generate", cases[0].ClassName)
+	assert.Equal(t, "40.000", cases[0].Time)
+	assert.Nil(t, cases[0].Failure)
+	assert.Contains(t, cases[0].SystemOut, "operation: operations/op1\noutput: videos/intro clip.mp4")
+
+	assert.Contains(t, cases[1].SystemOut, "attempt 1 failed (transient) after 5s: HTTP 503")
+	require.NotNil(t, cases[1].Failure)
+	assert.Equal(t, "quota exceeded", cases[1].Failure.Message)
+	require.NotNil(t, cases[3].Skipped)
+	assert.Nil(t, cases[3].Failure)
+}
+
+func TestProcessManifest_RecordsJobDetails(t *testing.T) {
+	processor := batch.NewProcessor(&recordingExecutor{fail: map[string]bool{"intro": true}}, 1)
+	results, err := processor.ProcessManifest(context.Background(), &batch.BatchManifest{
+		Jobs: []batch.BatchJob{
+			{ID: "intro", Type: "generate", Output: "intro.mp4", Options: map[string]interface{}{"prompt": "A", "model": "veo-3.0"}},
+			{ID: "extend", Type: "extend", Output: "extend.mp4", Options: map[string]interface{}{"video": "${jobs.intro.output}"}},
+		},
+		ContinueOnError: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "generate", results[0].Type)
+	assert.Equal(t, "veo-3.0", results[0].Model)
+	assert.Equal(t, "A", results[0].Prompt())
+	assert.Equal(t, "extend", results[1].Type, "skipped jobs are described too")
+	assert.Equal(t, []string{"video: ${jobs.intro.output}"}, results[1].Parameters())
+}
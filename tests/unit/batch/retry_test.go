@@ -0,0 +1,202 @@
+package batch_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyExecutor fails each job with its listed errors, one per attempt,
+// then succeeds, and records the jobs it ran
+type flakyExecutor struct {
+	mu       sync.Mutex
+	failures map[string][]string
+	ran      []batch.BatchJob
+}
+
+func (e *flakyExecutor) Execute(_ context.Context, job batch.BatchJob) (*batch.JobResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.ran = append(e.ran, job)
+	if failures := e.failures[job.ID]; len(failures) > 0 {
+		e.failures[job.ID] = failures[1:]
+		return &batch.JobResult{JobID: job.ID, Error: failures[0], OperationID: fmt.Sprintf("op%d", len(e.ran))}, nil
+	}
+	return &batch.JobResult{JobID: job.ID, Success: true, Output: job.Output}, nil
+}
+
+func TestParseManifest_Retry(t *testing.T) {
+	manifest, err := batch.ParseManifest([]byte(`
+retry:
+  max_attempts: 3
+  backoff: 30s
+  retry_on: [transient, safety]
+jobs:
+  - {id: a, type: generate, options: {prompt: x}, output: a.mp4}
+  - id: b
+    type: generate
+    options: {prompt: y, seed: 7}
+    output: b.mp4
+    retry: {max_attempts: 5, vary_seed: true}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, batch.RetryPolicy{MaxAttempts: 3, Backoff: 30 * time.Second, RetryOn: []string{"transient", "safety"}},
+		manifest.RetryPolicy(manifest.Jobs[0]))
+	assert.Equal(t, batch.RetryPolicy{MaxAttempts: 5, VarySeed: true}, manifest.RetryPolicy(manifest.Jobs[1]),
+		"a job's policy replaces the manifest's")
+
+	_, err = batch.ParseManifest([]byte(`
+jobs:
+  - {id: a, type: generate, options: {prompt: x}, output: a.mp4, retry: {retry_on: [quota]}}
+`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "job a: retry: unknown retry_on error class quota")
+
+	_, err = batch.ParseManifest([]byte("retry: {max_attempts: -1}\njobs:\n  - {id: a, type: generate, options: {prompt: x}, output: a.mp4}\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "retry: max_attempts must not be negative")
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&veo3.OperationError{Code: "RESOURCE_EXHAUSTED", Message: "quota"}, batch.ErrorTransient},
+		{&veo3.OperationError{Code: "14", Message: "unavailable"}, batch.ErrorTransient},
+		{&veo3.OperationError{Code: "", Message: "boom", Details: map[string]interface{}{"http_code": 502}}, batch.ErrorTransient},
+		{&veo3.OperationError{Code: veo3.SafetyFilteredCode, Message: "blocked"}, batch.ErrorSafety},
+		{&veo3.OperationError{Code: "3", Message: "Generation failed due to safety filters"}, batch.ErrorSafety},
+		{&veo3.OperationError{Code: "INVALID_ARGUMENT", Message: "bad prompt"}, batch.ErrorOther},
+		{fmt.Errorf("failed to submit: %w", &net.OpError{Op: "dial", Err: errors.New("refused")}), batch.ErrorTransient},
+		{context.DeadlineExceeded, batch.ErrorTransient},
+		{errors.New("HTTP 503: Service Unavailable"), batch.ErrorTransient},
+		{errors.New("image file not found: a.png"), batch.ErrorOther},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, batch.ClassifyError(tt.err), tt.err.Error())
+	}
+	assert.Empty(t, batch.ClassifyError(nil))
+}
+
+func TestProcessManifest_Retries(t *testing.T) {
+	executor := &flakyExecutor{failures: map[string][]string{
+		"flaky":   {"HTTP 503: unavailable", "RESOURCE_EXHAUSTED: quota"},
+		"invalid": {"invalid prompt"},
+		"doomed":  {"HTTP 429", "HTTP 429", "HTTP 429"},
+	}}
+	processor := batch.NewProcessor(executor, 3)
+	var retries []*batch.JobRetryingEvent
+	processor.OnEvent(func(event batch.Event) {
+		if retry, ok := event.(*batch.JobRetryingEvent); ok {
+			retries = append(retries, retry)
+		}
+	})
+
+	jobs := budgetJobs(3)
+	jobs[0].ID, jobs[1].ID, jobs[2].ID = "flaky", "invalid", "doomed"
+	results, err := processor.ProcessManifest(context.Background(), &batch.BatchManifest{
+		Jobs:            jobs,
+		Retry:           &batch.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond},
+		ContinueOnError: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	byID := make(map[string]batch.JobResult)
+	for _, result := range results {
+		byID[result.JobID] = result
+	}
+
+	flaky := byID["flaky"]
+	assert.True(t, flaky.Success)
+	require.Len(t, flaky.Attempts, 3)
+	assert.Equal(t, batch.ErrorTransient, flaky.Attempts[0].ErrorClass)
+	assert.Equal(t, "HTTP 503: unavailable", flaky.Attempts[0].Error)
+	assert.True(t, flaky.Attempts[2].Success)
+	assert.Empty(t, flaky.ErrorClass)
+
+	invalid := byID["invalid"]
+	assert.False(t, invalid.Success)
+	assert.Equal(t, batch.ErrorOther, invalid.ErrorClass)
+	assert.Len(t, invalid.Attempts, 1, "other errors are not retried by default")
+
+	doomed := byID["doomed"]
+	assert.False(t, doomed.Success)
+	assert.Len(t, doomed.Attempts, 3)
+	assert.Equal(t, "HTTP 429", doomed.Error)
+
+	assert.Len(t, retries, 4)
+	assert.Equal(t, 3, retries[0].MaxAttempts)
+}
+
+func TestProcessManifest_RetryVariesSeed(t *testing.T) {
+	executor := &flakyExecutor{failures: map[string][]string{
+		"job1": {"blocked by the safety filter", "blocked by the safety filter"},
+	}}
+	jobs := budgetJobs(1)
+	jobs[0].Options["seed"] = 40
+	jobs[0].Retry = &batch.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, RetryOn: []string{batch.ErrorSafety}, VarySeed: true}
+
+	results, err := batch.NewProcessor(executor, 1).ProcessManifest(context.Background(), &batch.BatchManifest{Jobs: jobs, ContinueOnError: true})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Success)
+
+	var seeds []int
+	for _, attempt := range results[0].Attempts {
+		seeds = append(seeds, *attempt.Seed)
+	}
+	assert.Equal(t, []int{40, 41, 42}, seeds)
+	assert.Equal(t, 42, results[0].Options["seed"], "the result records the options of the last attempt")
+	assert.Equal(t, 40, jobs[0].Options["seed"], "the manifest job is not changed")
+	assert.Equal(t, "attempt 1 failed (safety) after 0s, seed 40, operation op1: blocked by the safety filter",
+		results[0].Attempts[0].String())
+}
+
+func TestProcessManifest_RetriesCountAgainstBudget(t *testing.T) {
+	executor := &flakyExecutor{failures: map[string][]string{"job1": {"HTTP 503", "HTTP 503"}}}
+	processor := batch.NewProcessor(executor, 1)
+	processor.SetJobDefaults(budgetDefaults)
+
+	results, err := processor.ProcessManifest(context.Background(), &batch.BatchManifest{
+		Jobs:            budgetJobs(1),
+		Retry:           &batch.RetryPolicy{MaxAttempts: 5, Backoff: time.Millisecond},
+		Budget:          batch.Budget{MaxVideoSeconds: 16},
+		ContinueOnError: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.False(t, results[0].Success)
+	assert.Len(t, results[0].Attempts, 2)
+	assert.Equal(t, "HTTP 503 (not retried: max_video_seconds budget of 16 reached)", results[0].Error)
+}
+
+func TestProcessManifest_RetryStopsOnCancel(t *testing.T) {
+	executor := &flakyExecutor{failures: map[string][]string{"job1": {"HTTP 503", "HTTP 503"}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	processor := batch.NewProcessor(executor, 1)
+	processor.OnEvent(func(event batch.Event) {
+		if _, ok := event.(*batch.JobRetryingEvent); ok {
+			cancel()
+		}
+	})
+
+	_, err := processor.ProcessManifest(ctx, &batch.BatchManifest{
+		Jobs:            budgetJobs(1),
+		Retry:           &batch.RetryPolicy{MaxAttempts: 3, Backoff: time.Hour},
+		ContinueOnError: true,
+	})
+	require.Error(t, err)
+	executor.mu.Lock()
+	defer executor.mu.Unlock()
+	assert.Len(t, executor.ran, 1)
+}
@@ -0,0 +1,102 @@
+package batch_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/jasongoecke/go-veo3/pkg/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func templateLookup(saved ...*templates.Template) batch.TemplateLookup {
+	return func(name string) (*templates.Template, error) {
+		for _, t := range saved {
+			if t.Name == name {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("template not found: %s", name)
+	}
+}
+
+func TestApplyTemplates(t *testing.T) {
+	manifest, err := batch.ParseManifest([]byte(`
+jobs:
+  - id: promo
+    template: promo
+    vars:
+      product: sneaker
+    options:
+      duration: 6
+    output: promo.mp4
+  - id: spin
+    template: spin
+    vars:
+      product: watch
+    output: spin.mp4
+  - id: explicit
+    type: generate
+    template: promo
+    options:
+      prompt: "Custom prompt"
+    output: explicit.mp4
+  - id: plain
+    type: generate
+    options:
+      prompt: "No template"
+    output: plain.mp4
+`))
+	require.NoError(t, err)
+
+	lookup := templateLookup(
+		&templates.Template{Name: "promo", Prompt: "A {{product}} on marble", Preset: &templates.Preset{
+			Model: "veo-3.1-generate-preview", Resolution: "1080p", Duration: 8, NegativePrompt: "text",
+		}},
+		&templates.Template{Name: "spin", Prompt: "The {{product}} rotates", Preset: &templates.Preset{
+			Image: "/assets/product.png", AspectRatio: "9:16",
+		}},
+	)
+	require.NoError(t, batch.ApplyTemplates(manifest, lookup))
+
+	promo := manifest.Jobs[0]
+	assert.Equal(t, "generate", promo.Type)
+	assert.Equal(t, "A sneaker on marble", promo.StringOption("prompt", ""))
+	assert.Equal(t, "1080p", promo.StringOption("resolution", ""))
+	assert.Equal(t, "text", promo.StringOption("negative_prompt", ""))
+	assert.Equal(t, 6, promo.IntOption("duration", 0), "job options override the preset")
+	assert.Empty(t, promo.Template, "expanded jobs no longer reference the template")
+	assert.Equal(t, "promo@1", promo.TemplateRef, "the version used is recorded")
+
+	spin := manifest.Jobs[1]
+	assert.Equal(t, "animate", spin.Type, "type inferred from the preset image")
+	assert.Equal(t, "/assets/product.png", spin.StringOption("image", ""))
+	assert.Equal(t, "The watch rotates", spin.StringOption("prompt", ""))
+
+	assert.Equal(t, "Custom prompt", manifest.Jobs[2].StringOption("prompt", ""))
+	assert.Equal(t, "veo-3.1-generate-preview", manifest.Jobs[2].StringOption("model", ""))
+
+	assert.Equal(t, map[string]interface{}{"prompt": "No template"}, manifest.Jobs[3].Options)
+}
+
+func TestApplyTemplates_Errors(t *testing.T) {
+	parse := func(jobYAML string) *batch.BatchManifest {
+		manifest, err := batch.ParseManifest([]byte("jobs:\n" + jobYAML))
+		require.NoError(t, err)
+		return manifest
+	}
+	lookup := templateLookup(
+		&templates.Template{Name: "scene", Prompt: "A {{subject}}"},
+		&templates.Template{Name: "frames", Preset: &templates.Preset{Type: "interpolate", Image: "/a.png"}},
+	)
+
+	err := batch.ApplyTemplates(parse("  - {id: a, template: missing, output: a.mp4}\n"), lookup)
+	assert.ErrorContains(t, err, "template not found: missing")
+
+	err = batch.ApplyTemplates(parse("  - {id: b, template: scene, output: b.mp4}\n"), lookup)
+	assert.ErrorContains(t, err, "missing required variable: subject")
+
+	err = batch.ApplyTemplates(parse("  - {id: c, template: frames, output: c.mp4}\n"), lookup)
+	assert.ErrorContains(t, err, "requires 'last_frame' option")
+}
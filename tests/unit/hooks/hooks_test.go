@@ -0,0 +1,148 @@
+package hooks_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jasongoecke/go-veo3/pkg/config"
+	"github.com/jasongoecke/go-veo3/pkg/hooks"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testVideo() *veo3.GeneratedVideo {
+	return &veo3.GeneratedVideo{
+		FilePath:    "/videos/cat.mp4",
+		OperationID: "operations/abc123",
+		Model:       "veo-3.1-generate-preview",
+		Prompt:      "A cat playing piano",
+	}
+}
+
+func requireShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use POSIX shell commands")
+	}
+}
+
+func TestRunner_TemplatesArgsAndEnv(t *testing.T) {
+	requireShell(t)
+	out := filepath.Join(t.TempDir(), "out.txt")
+
+	runner := hooks.NewRunner([]config.HookConfig{
+		{
+			Name:    "record",
+			Command: "sh",
+			Args:    []string{"-c", `printf '%s|%s|%s|%s' "$1" "$MODEL_NAME" "$VEO3_OPERATION_ID" "$VEO3_PROMPT" > ` + out, "sh", "{{file_path}}"},
+			Env:     map[string]string{"MODEL_NAME": "model={{model}}"},
+		},
+	})
+
+	results, err := runner.Run(context.Background(), testVideo())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Success)
+	assert.Equal(t, 0, results[0].ExitCode)
+	assert.Contains(t, results[0].Args, "/videos/cat.mp4")
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "/videos/cat.mp4|model=veo-3.1-generate-preview|operations/abc123|A cat playing piano", string(data))
+}
+
+func TestRunner_FailurePolicies(t *testing.T) {
+	requireShell(t)
+
+	tests := []struct {
+		name        string
+		policy      string
+		wantErr     bool
+		wantSkipped bool
+		wantSecond  bool
+	}{
+		{name: "fail stops the pipeline with an error", policy: "", wantErr: true, wantSkipped: true},
+		{name: "stop skips remaining hooks", policy: "stop", wantSkipped: true},
+		{name: "continue runs remaining hooks", policy: "continue", wantSecond: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := hooks.NewRunner([]config.HookConfig{
+				{Name: "broken", Command: "sh", Args: []string{"-c", "echo boom >&2; exit 3"}, OnFailure: tt.policy},
+				{Name: "after", Command: "true"},
+			})
+
+			results, err := runner.Run(context.Background(), testVideo())
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "broken")
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Len(t, results, 2)
+			assert.False(t, results[0].Success)
+			assert.Equal(t, 3, results[0].ExitCode)
+			assert.Equal(t, "exited with code 3", results[0].Error)
+			assert.Equal(t, "boom", strings.TrimSpace(results[0].Output))
+
+			assert.Equal(t, tt.wantSkipped, results[1].Skipped)
+			assert.Equal(t, tt.wantSecond, results[1].Success)
+		})
+	}
+}
+
+func TestRunner_Timeout(t *testing.T) {
+	requireShell(t)
+
+	runner := hooks.NewRunner([]config.HookConfig{
+		{Name: "slow", Command: "sleep", Args: []string{"5"}, TimeoutSeconds: 1, OnFailure: "continue"},
+	})
+
+	results, err := runner.Run(context.Background(), testVideo())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.False(t, results[0].Success)
+	assert.Contains(t, results[0].Error, "timed out")
+	assert.Less(t, results[0].Duration.Seconds(), 4.0)
+}
+
+func TestRunner_UnknownVariable(t *testing.T) {
+	runner := hooks.NewRunner([]config.HookConfig{
+		{Name: "typo", Command: "true", Args: []string{"{{file_pth}}"}},
+	})
+
+	results, err := runner.Run(context.Background(), testVideo())
+	require.Error(t, err)
+	require.Len(t, results, 1)
+	assert.Contains(t, results[0].Error, "missing required variable: file_pth")
+}
+
+func TestRunner_Disabled(t *testing.T) {
+	var nilRunner *hooks.Runner
+	assert.False(t, nilRunner.Enabled())
+
+	results, err := hooks.NewRunner(nil).Run(context.Background(), testVideo())
+	require.NoError(t, err)
+	assert.Nil(t, results)
+}
+
+func TestVariables(t *testing.T) {
+	vars := hooks.Variables(testVideo())
+	assert.Equal(t, "/videos/cat.mp4", vars["file_path"])
+	assert.Equal(t, "operations/abc123", vars["operation_id"])
+	assert.Equal(t, "veo-3.1-generate-preview", vars["model"])
+	assert.Equal(t, "A cat playing piano", vars["prompt"])
+	assert.Equal(t, "0", vars["duration_seconds"])
+	assert.Empty(t, vars["template"])
+
+	video := testVideo()
+	video.Template = "promo@4"
+	assert.Equal(t, "promo@4", hooks.Variables(video)["template"])
+}
@@ -0,0 +1,246 @@
+package metrics_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/jasongoecke/go-veo3/pkg/metrics"
+	"github.com/jasongoecke/go-veo3/pkg/operations"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const operationName = "models/veo-3.1-generate-preview/operations/abc123"
+
+// recordingObserver keeps every event it receives
+type recordingObserver struct {
+	mu          sync.Mutex
+	requests    []veo3.RequestEvent
+	polls       []veo3.PollEvent
+	completions []veo3.CompletionEvent
+	downloads   []veo3.DownloadEvent
+	jobs        []veo3.BatchJobEvent
+}
+
+func (r *recordingObserver) RequestCompleted(_ context.Context, e veo3.RequestEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, e)
+}
+
+func (r *recordingObserver) OperationPolled(_ context.Context, e veo3.PollEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.polls = append(r.polls, e)
+}
+
+func (r *recordingObserver) OperationCompleted(_ context.Context, e veo3.CompletionEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.completions = append(r.completions, e)
+}
+
+func (r *recordingObserver) VideoDownloaded(_ context.Context, e veo3.DownloadEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.downloads = append(r.downloads, e)
+}
+
+func (r *recordingObserver) BatchJobCompleted(_ context.Context, e veo3.BatchJobEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs = append(r.jobs, e)
+}
+
+// fakeVeoAPI reports the operation as running for the first poll and done afterwards
+func fakeVeoAPI(t *testing.T) *httptest.Server {
+	var polls int32
+	var server *httptest.Server
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, ":predictLongRunning"):
+			_, _ = w.Write([]byte(`{"name": "` + operationName + `"}`))
+		case r.URL.Path == "/"+operationName:
+			if atomic.AddInt32(&polls, 1) == 1 {
+				_, _ = w.Write([]byte(`{"name": "` + operationName + `", "done": false}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"name":     operationName,
+				"done":     true,
+				"response": map[string]interface{}{"videoUri": server.URL + "/video.mp4"},
+			})
+		case r.URL.Path == "/video.mp4":
+			_, _ = w.Write([]byte("0123456789"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"code": 404, "message": "not found", "status": "NOT_FOUND"}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// runGeneration submits, polls and downloads a video through the instrumented components
+func runGeneration(t *testing.T, observer veo3.Observer) {
+	t.Helper()
+	server := fakeVeoAPI(t)
+	ctx := context.Background()
+
+	client, err := veo3.NewClient(ctx, "test-api-key", veo3.WithBaseURL(server.URL), veo3.WithObserver(observer))
+	require.NoError(t, err)
+
+	op, err := client.GenerateVideo(ctx, &veo3.GenerationRequest{
+		Prompt:          "A cat playing piano",
+		Model:           "veo-3.1-generate-preview",
+		AspectRatio:     "16:9",
+		Resolution:      "720p",
+		DurationSeconds: 8,
+	})
+	require.NoError(t, err)
+
+	manager := operations.NewManager(client)
+	manager.AddOperation(op)
+	poller := operations.NewPoller(client, manager)
+	poller.SetPollingConfig(10*time.Millisecond, 50*time.Millisecond, 1.0, 3)
+
+	done, err := poller.WaitForCompletion(ctx, op.ID, false)
+	require.NoError(t, err)
+	require.Equal(t, veo3.StatusDone, done.Status)
+
+	downloader := operations.NewDownloader(false)
+	downloader.SetObserver(client.Observer())
+	_, err = downloader.DownloadVideo(ctx, done, filepath.Join(t.TempDir(), "cat.mp4"))
+	require.NoError(t, err)
+
+	// Unknown operations surface API errors as failed requests and polls
+	_, err = client.GetOperation(ctx, "operations/missing")
+	require.Error(t, err)
+}
+
+func TestObserver_ReceivesClientPollerAndDownloaderEvents(t *testing.T) {
+	observer := &recordingObserver{}
+	runGeneration(t, observer)
+
+	require.Len(t, observer.requests, 4) // submit, two polls, failed lookup
+	assert.Equal(t, "generate", observer.requests[0].Call)
+	assert.Equal(t, http.StatusOK, observer.requests[0].StatusCode)
+	assert.Equal(t, "get_operation", observer.requests[1].Call)
+	assert.Equal(t, http.StatusNotFound, observer.requests[3].StatusCode)
+	assert.False(t, observer.requests[0].Start.IsZero())
+
+	require.Len(t, observer.polls, 2)
+	assert.Equal(t, veo3.StatusRunning, observer.polls[0].Status)
+	assert.Equal(t, veo3.StatusDone, observer.polls[1].Status)
+	assert.Equal(t, "veo-3.1-generate-preview", observer.polls[0].Model)
+
+	require.Len(t, observer.completions, 1, "completion is reported once")
+	assert.Equal(t, "veo-3.1-generate-preview", observer.completions[0].Model)
+	assert.Equal(t, veo3.StatusDone, observer.completions[0].Status)
+	assert.Greater(t, observer.completions[0].Duration, time.Duration(0))
+
+	require.Len(t, observer.downloads, 1)
+	assert.Equal(t, int64(10), observer.downloads[0].Bytes)
+	assert.NoError(t, observer.downloads[0].Err)
+}
+
+func TestObserver_BatchJobOutcomes(t *testing.T) {
+	observer := &recordingObserver{}
+	executor := batchExecutor(func(job batch.BatchJob) (*batch.JobResult, error) {
+		if job.ID == "bad" {
+			return &batch.JobResult{JobID: job.ID, Error: "quota exceeded"}, nil
+		}
+		return &batch.JobResult{JobID: job.ID, Success: true}, nil
+	})
+
+	processor := batch.NewProcessor(executor, 2)
+	processor.SetObserver(observer)
+
+	_, err := processor.ProcessManifest(context.Background(), &batch.BatchManifest{
+		Jobs: []batch.BatchJob{
+			{ID: "good", Type: "generate"},
+			{ID: "bad", Type: "animate"},
+		},
+		ContinueOnError: true,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, observer.jobs, 2)
+	outcomes := map[string]veo3.BatchJobEvent{}
+	for _, event := range observer.jobs {
+		outcomes[event.JobID] = event
+	}
+	assert.True(t, outcomes["good"].Success)
+	assert.Equal(t, "generate", outcomes["good"].Type)
+	assert.False(t, outcomes["bad"].Success)
+	assert.Equal(t, "quota exceeded", outcomes["bad"].Err)
+}
+
+func TestPrometheus_TextExposition(t *testing.T) {
+	prometheus := metrics.NewPrometheus()
+	runGeneration(t, prometheus)
+	prometheus.BatchJobCompleted(context.Background(), veo3.BatchJobEvent{Type: "generate", Duration: 45 * time.Second})
+
+	recorder := httptest.NewRecorder()
+	prometheus.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, metrics.ContentType, recorder.Header().Get("Content-Type"))
+	body := recorder.Body.String()
+
+	for _, line := range []string{
+		"# TYPE veo3_api_requests_total counter",
+		`veo3_api_requests_total{call="generate",code="200"} 1`,
+		`veo3_api_requests_total{call="get_operation",code="200"} 2`,
+		`veo3_api_requests_total{call="get_operation",code="404"} 1`,
+		"# TYPE veo3_api_request_duration_seconds histogram",
+		`veo3_api_request_duration_seconds_count{call="get_operation"} 3`,
+		`veo3_operation_polls_total{model="veo-3.1-generate-preview",status="RUNNING"} 1`,
+		`veo3_operation_polls_total{model="veo-3.1-generate-preview",status="DONE"} 1`,
+		`veo3_operation_completion_seconds_count{model="veo-3.1-generate-preview",status="DONE"} 1`,
+		`veo3_operation_completion_seconds_bucket{model="veo-3.1-generate-preview",status="DONE",le="+Inf"} 1`,
+		`veo3_downloads_total{result="success"} 1`,
+		`veo3_downloaded_bytes_total{model="veo-3.1-generate-preview"} 10`,
+		`veo3_batch_jobs_total{type="generate",result="failure"} 1`,
+		`veo3_batch_job_duration_seconds_bucket{type="generate",le="30"} 0`,
+		`veo3_batch_job_duration_seconds_bucket{type="generate",le="60"} 1`,
+		`veo3_batch_job_duration_seconds_sum{type="generate"} 45`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+}
+
+func TestExpvar_PublishesCounters(t *testing.T) {
+	exp := metrics.NewExpvar("veo3_test")
+	runGeneration(t, exp)
+	exp.VideoDownloaded(context.Background(), veo3.DownloadEvent{Err: errors.New("timeout")})
+
+	var snapshot map[string]map[string]float64
+	require.NoError(t, json.Unmarshal([]byte(exp.Map().String()), &snapshot))
+
+	assert.Equal(t, 1.0, snapshot["api_requests"]["generate:200"])
+	assert.Equal(t, 1.0, snapshot["api_requests"]["get_operation:404"])
+	assert.Equal(t, 1.0, snapshot["operation_completions"]["veo-3.1-generate-preview:DONE"])
+	assert.Equal(t, 10.0, snapshot["downloaded_bytes"]["veo-3.1-generate-preview"])
+	assert.Equal(t, 1.0, snapshot["downloads"]["failure"])
+
+	// Re-creating the adapter reuses the published variables
+	assert.Same(t, exp.Map(), metrics.NewExpvar("veo3_test").Map())
+}
+
+type batchExecutor func(job batch.BatchJob) (*batch.JobResult, error)
+
+func (f batchExecutor) Execute(_ context.Context, job batch.BatchJob) (*batch.JobResult, error) {
+	return f(job)
+}
@@ -0,0 +1,341 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/jasongoecke/go-veo3/pkg/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateEntry(id string, priority int) queue.Entry {
+	return queue.Entry{
+		BatchJob: batch.BatchJob{
+			ID:      id,
+			Type:    "generate",
+			Options: map[string]interface{}{"prompt": "A sunset over " + id},
+			Output:  id + ".mp4",
+		},
+		Priority: priority,
+	}
+}
+
+// recordingExecutor records the order jobs run in and fails jobs listed in fail
+type recordingExecutor struct {
+	mu    sync.Mutex
+	order []string
+	fail  map[string]bool
+}
+
+func (e *recordingExecutor) Execute(ctx context.Context, job batch.BatchJob) (*batch.JobResult, error) {
+	e.mu.Lock()
+	e.order = append(e.order, job.ID)
+	e.mu.Unlock()
+
+	if e.fail[job.ID] {
+		return nil, errors.New("quota exceeded")
+	}
+	return &batch.JobResult{JobID: job.ID, Success: true, Output: job.Output}, nil
+}
+
+func TestStore_AddAssignsIDsAndPersists(t *testing.T) {
+	dir := t.TempDir()
+	store := queue.NewStore(dir)
+
+	entry := generateEntry("", 0)
+	added, err := store.Add(entry, generateEntry("named", 0))
+	require.NoError(t, err)
+	require.Len(t, added, 2)
+	assert.Equal(t, "q1", added[0].ID)
+	assert.Equal(t, queue.StatusQueued, added[0].Status)
+	assert.False(t, added[0].EnqueuedAt.IsZero())
+
+	// A new store over the same directory sees the persisted jobs
+	entries, err := queue.NewStore(dir).List()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "A sunset over ", entries[0].StringOption("prompt", ""))
+}
+
+func TestStore_AddRejectsInvalidAndDuplicateJobs(t *testing.T) {
+	store := queue.NewStore(t.TempDir())
+
+	_, err := store.Add(generateEntry("job1", 0))
+	require.NoError(t, err)
+
+	_, err = store.Add(generateEntry("job1", 0))
+	assert.ErrorContains(t, err, "already queued")
+
+	invalid := generateEntry("job2", 0)
+	invalid.Output = ""
+	_, err = store.Add(invalid)
+	assert.ErrorContains(t, err, "output")
+
+	// A failed add leaves the queue untouched
+	entries, err := store.List()
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestStore_ClaimOrdersByPriorityThenAge(t *testing.T) {
+	store := queue.NewStore(t.TempDir())
+
+	_, err := store.Add(generateEntry("low", 0))
+	require.NoError(t, err)
+	_, err = store.Add(generateEntry("high", 10))
+	require.NoError(t, err)
+	_, err = store.Add(generateEntry("low2", 0))
+	require.NoError(t, err)
+
+	var claimed []string
+	for {
+		entry, err := store.Claim(time.Now())
+		require.NoError(t, err)
+		if entry == nil {
+			break
+		}
+		assert.Equal(t, queue.StatusRunning, entry.Status)
+		assert.Equal(t, 1, entry.Attempts)
+		claimed = append(claimed, entry.ID)
+	}
+
+	assert.Equal(t, []string{"high", "low", "low2"}, claimed)
+}
+
+func TestStore_ClaimRespectsNotBefore(t *testing.T) {
+	store := queue.NewStore(t.TempDir())
+	now := time.Now()
+
+	later := generateEntry("later", 100)
+	notBefore := now.Add(time.Hour)
+	later.NotBefore = &notBefore
+	_, err := store.Add(later, generateEntry("now", 0))
+	require.NoError(t, err)
+
+	entry, err := store.Claim(now)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, "now", entry.ID)
+
+	entry, err = store.Claim(now)
+	require.NoError(t, err)
+	assert.Nil(t, entry, "scheduled job must wait for its not-before time")
+
+	entry, err = store.Claim(notBefore)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, "later", entry.ID)
+}
+
+func TestStore_CompleteRemoveAndPrune(t *testing.T) {
+	store := queue.NewStore(t.TempDir())
+
+	_, err := store.Add(generateEntry("done", 1), generateEntry("failed", 1), generateEntry("waiting", 0))
+	require.NoError(t, err)
+
+	first, err := store.Claim(time.Now())
+	require.NoError(t, err)
+	second, err := store.Claim(time.Now())
+	require.NoError(t, err)
+
+	assert.ErrorContains(t, store.Remove(first.ID), "running")
+
+	require.NoError(t, store.Complete(first.ID, &batch.JobResult{Success: true, Output: "/videos/done.mp4"}))
+	require.NoError(t, store.Complete(second.ID, &batch.JobResult{Error: "quota exceeded"}))
+
+	entries, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "waiting", entries[0].ID, "queued jobs are listed before finished ones")
+	assert.Equal(t, queue.StatusDone, entries[1].Status)
+	assert.Equal(t, "/videos/done.mp4", entries[1].ResultOutput)
+	assert.Equal(t, queue.StatusFailed, entries[2].Status)
+	assert.Equal(t, "quota exceeded", entries[2].Error)
+
+	// Finished job IDs may be reused
+	_, err = store.Add(generateEntry("done", 0))
+	require.NoError(t, err)
+
+	pruned, err := store.Prune()
+	require.NoError(t, err)
+	assert.Equal(t, 2, pruned)
+
+	require.NoError(t, store.Remove("waiting"))
+	assert.ErrorContains(t, store.Remove("missing"), "not found")
+
+	entries, err = store.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "done", entries[0].ID)
+	assert.Equal(t, queue.StatusQueued, entries[0].Status)
+}
+
+func TestStore_RequeueRecoversRunningJobs(t *testing.T) {
+	store := queue.NewStore(t.TempDir())
+
+	_, err := store.Add(generateEntry("job1", 0))
+	require.NoError(t, err)
+	_, err = store.Claim(time.Now())
+	require.NoError(t, err)
+
+	requeued, err := store.Requeue()
+	require.NoError(t, err)
+	assert.Equal(t, 1, requeued)
+
+	entry, err := store.Claim(time.Now())
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, 2, entry.Attempts)
+}
+
+func TestStore_ConcurrentAdds(t *testing.T) {
+	store := queue.NewStore(t.TempDir())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := store.Add(generateEntry("", 0))
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	entries, err := store.List()
+	require.NoError(t, err)
+	assert.Len(t, entries, 20)
+}
+
+func TestRunner_DrainsQueueInPriorityOrder(t *testing.T) {
+	store := queue.NewStore(t.TempDir())
+	_, err := store.Add(generateEntry("low", 0), generateEntry("high", 5), generateEntry("broken", 1))
+	require.NoError(t, err)
+
+	executor := &recordingExecutor{fail: map[string]bool{"broken": true}}
+	runner := queue.NewRunner(store, executor, 1)
+	runner.SetPollInterval(10 * time.Millisecond)
+
+	var finished []string
+	runner.OnFinish(func(entry queue.Entry, result *batch.JobResult) {
+		finished = append(finished, entry.ID)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, runner.Run(ctx, true))
+
+	assert.Equal(t, []string{"high", "broken", "low"}, executor.order)
+	assert.Equal(t, executor.order, finished)
+
+	entries, err := store.List()
+	require.NoError(t, err)
+	statuses := make(map[string]queue.Status)
+	for _, entry := range entries {
+		statuses[entry.ID] = entry.Status
+	}
+	assert.Equal(t, queue.StatusDone, statuses["high"])
+	assert.Equal(t, queue.StatusFailed, statuses["broken"])
+	assert.Equal(t, queue.StatusDone, statuses["low"])
+}
+
+func TestRunner_WaitsForScheduledJobs(t *testing.T) {
+	store := queue.NewStore(t.TempDir())
+
+	scheduled := generateEntry("scheduled", 0)
+	notBefore := time.Now().Add(200 * time.Millisecond)
+	scheduled.NotBefore = &notBefore
+	_, err := store.Add(scheduled)
+	require.NoError(t, err)
+
+	executor := &recordingExecutor{}
+	runner := queue.NewRunner(store, executor, 2)
+	runner.SetPollInterval(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	require.NoError(t, runner.Run(ctx, true))
+
+	assert.Equal(t, []string{"scheduled"}, executor.order)
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+	assert.Less(t, time.Since(start), time.Second, "runner should wake at the not-before time, not the poll interval")
+}
+
+// blockingExecutor runs jobs until release is closed
+type blockingExecutor struct {
+	started chan string
+	release chan struct{}
+	mu      sync.Mutex
+	runs    int
+}
+
+func (e *blockingExecutor) Execute(ctx context.Context, job batch.BatchJob) (*batch.JobResult, error) {
+	e.mu.Lock()
+	e.runs++
+	e.mu.Unlock()
+	e.started <- job.ID
+	<-e.release
+	return &batch.JobResult{JobID: job.ID, Success: true, Output: job.Output}, nil
+}
+
+func TestRunner_SecondRunnerDoesNotRequeueRunningJobs(t *testing.T) {
+	store := queue.NewStore(t.TempDir())
+	_, err := store.Add(generateEntry("job1", 0))
+	require.NoError(t, err)
+
+	first := &blockingExecutor{started: make(chan string, 1), release: make(chan struct{})}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- queue.NewRunner(store, first, 1).Run(ctx, true) }()
+	assert.Equal(t, "job1", <-first.started)
+
+	second := &recordingExecutor{}
+	err = queue.NewRunner(store, second, 1).Run(ctx, true)
+	require.ErrorIs(t, err, queue.ErrRunnerActive)
+	assert.Empty(t, second.order)
+
+	entries, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, queue.StatusRunning, entries[0].Status, "the first runner's job stays running")
+
+	close(first.release)
+	require.NoError(t, <-done)
+	assert.Equal(t, 1, first.runs)
+
+	// Once the first runner has stopped, another may run the queue
+	_, err = store.Add(generateEntry("job2", 0))
+	require.NoError(t, err)
+	require.NoError(t, queue.NewRunner(store, second, 1).Run(ctx, true))
+	assert.Equal(t, []string{"job2"}, second.order)
+}
+
+func TestRunner_TakesOverStaleRunnerLock(t *testing.T) {
+	store := queue.NewStore(t.TempDir())
+	_, err := store.Add(generateEntry("job1", 0))
+	require.NoError(t, err)
+	_, err = store.Claim(time.Now())
+	require.NoError(t, err)
+
+	// A runner that died mid-job left its lock behind
+	lockPath := store.Path() + ".runner"
+	require.NoError(t, os.WriteFile(lockPath, []byte("12345\n"), 0600))
+	stale := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(lockPath, stale, stale))
+
+	executor := &recordingExecutor{}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, queue.NewRunner(store, executor, 1).Run(ctx, true))
+	assert.Equal(t, []string{"job1"}, executor.order, "the dead runner's job is requeued")
+	assert.NoFileExists(t, lockPath)
+}
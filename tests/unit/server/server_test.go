@@ -0,0 +1,472 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/batch"
+	"github.com/jasongoecke/go-veo3/pkg/server"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	operationName = "models/veo-3.1-generate-preview/operations/op1"
+	videoContent  = "fake mp4 data"
+)
+
+// fakeVeoAPI simulates the Veo API: submissions return op1, which completes
+// after the first poll, and cancellations are accepted
+type fakeVeoAPI struct {
+	*httptest.Server
+	polls     int32
+	cancelled int32
+}
+
+func newFakeVeoAPI(t *testing.T) *fakeVeoAPI {
+	api := &fakeVeoAPI{}
+	api.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, ":predictLongRunning"):
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"name": operationName})
+		case strings.HasSuffix(r.URL.Path, ":cancel"):
+			atomic.AddInt32(&api.cancelled, 1)
+			_, _ = w.Write([]byte("{}"))
+		case r.URL.Path == "/"+operationName:
+			atomic.AddInt32(&api.polls, 1)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"name":     operationName,
+				"done":     true,
+				"response": map[string]interface{}{"videoUri": api.URL + "/files/op1.mp4"},
+			})
+		case r.URL.Path == "/files/op1.mp4":
+			_, _ = w.Write([]byte(videoContent))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(api.Close)
+	return api
+}
+
+// stubExecutor succeeds for every batch job
+type stubExecutor struct{}
+
+func (stubExecutor) Execute(ctx context.Context, job batch.BatchJob) (*batch.JobResult, error) {
+	return &batch.JobResult{JobID: job.ID, Success: true, Output: job.Output}, nil
+}
+
+type envelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// startServer runs a server on a random port and returns its base URL
+func startServer(t *testing.T, opts server.Options) (string, *fakeVeoAPI, func() error) {
+	t.Helper()
+
+	api := newFakeVeoAPI(t)
+	client, err := veo3.NewClient(context.Background(), "test-key", veo3.WithBaseURL(api.URL))
+	require.NoError(t, err)
+
+	if opts.OutputDir == "" {
+		opts.OutputDir = t.TempDir()
+	}
+	if opts.PollInterval == 0 {
+		opts.PollInterval = 20 * time.Millisecond
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.New(client, opts).Serve(ctx, listener) }()
+
+	shutdown := func() error {
+		cancel()
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(5 * time.Second):
+			return context.DeadlineExceeded
+		}
+	}
+	t.Cleanup(func() { _ = shutdown() })
+
+	return "http://" + listener.Addr().String(), api, shutdown
+}
+
+func doRequest(t *testing.T, method, url, token string, body string) (*http.Response, envelope) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	var env envelope
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		require.NoError(t, json.Unmarshal(data, &env))
+	} else {
+		env.Data = data
+	}
+	return resp, env
+}
+
+func TestServer_GenerateLifecycle(t *testing.T) {
+	baseURL, _, shutdown := startServer(t, server.Options{})
+
+	// Submit a generation using configured defaults
+	resp, env := doRequest(t, "POST", baseURL+"/v1/generate", "", `{"prompt": "A cat playing piano"}`)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	require.True(t, env.Success)
+
+	var record server.OperationRecord
+	require.NoError(t, json.Unmarshal(env.Data, &record))
+	assert.Equal(t, operationName, record.Operation.ID)
+	assert.Equal(t, "generate", record.Kind)
+
+	// The background poller completes and downloads the video
+	require.Eventually(t, func() bool {
+		_, env := doRequest(t, "GET", baseURL+"/v1/operations/"+operationName, "", "")
+		var current server.OperationRecord
+		_ = json.Unmarshal(env.Data, &current)
+		return current.FilePath != "" && current.Operation.Status == veo3.StatusDone
+	}, 3*time.Second, 20*time.Millisecond)
+
+	resp, env = doRequest(t, "GET", baseURL+"/v1/operations/"+operationName+":download", "", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "video/mp4", resp.Header.Get("Content-Type"))
+	assert.Equal(t, videoContent, string(env.Data))
+
+	// List with status filter
+	_, env = doRequest(t, "GET", baseURL+"/v1/operations?status=done", "", "")
+	var list struct {
+		Count int `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(env.Data, &list))
+	assert.Equal(t, 1, list.Count)
+
+	_, env = doRequest(t, "GET", baseURL+"/v1/operations?status=RUNNING", "", "")
+	require.NoError(t, json.Unmarshal(env.Data, &list))
+	assert.Equal(t, 0, list.Count)
+
+	// Graceful shutdown returns cleanly
+	assert.NoError(t, shutdown())
+}
+
+func TestServer_SubmitValidation(t *testing.T) {
+	baseURL, _, _ := startServer(t, server.Options{})
+
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		wantCode int
+		wantErr  string
+	}{
+		{name: "invalid JSON", path: "/v1/generate", body: `{`, wantCode: http.StatusBadRequest, wantErr: "INVALID_REQUEST"},
+		{name: "unknown field", path: "/v1/generate", body: `{"promt": "typo"}`, wantCode: http.StatusBadRequest, wantErr: "INVALID_REQUEST"},
+		{name: "empty prompt", path: "/v1/generate", body: `{}`, wantCode: http.StatusBadRequest, wantErr: "SUBMIT_FAILED"},
+		{name: "invalid resolution", path: "/v1/generate", body: `{"prompt": "x", "resolution": "4k"}`, wantCode: http.StatusBadRequest, wantErr: "SUBMIT_FAILED"},
+		{name: "absolute image path", path: "/v1/animate", body: `{"prompt": "x", "image_path": "/etc/passwd.png"}`, wantCode: http.StatusBadRequest, wantErr: "INVALID_REQUEST"},
+		{name: "parent frame path", path: "/v1/interpolate", body: `{"first_frame_path": "a.png", "last_frame_path": "../b.png"}`, wantCode: http.StatusBadRequest, wantErr: "INVALID_REQUEST"},
+		{name: "absolute reference path", path: "/v1/generate", body: `{"prompt": "x", "reference_image_paths": ["/etc/ref.png"]}`, wantCode: http.StatusBadRequest, wantErr: "INVALID_REQUEST"},
+		{name: "parent video path", path: "/v1/extend", body: `{"video_path": "../../clip.mp4", "extension_prompt": "x"}`, wantCode: http.StatusBadRequest, wantErr: "INVALID_REQUEST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, env := doRequest(t, "POST", baseURL+tt.path, "", tt.body)
+			assert.Equal(t, tt.wantCode, resp.StatusCode)
+			assert.False(t, env.Success)
+			require.NotNil(t, env.Error)
+			assert.Equal(t, tt.wantErr, env.Error.Code)
+		})
+	}
+}
+
+func TestServer_OperationErrors(t *testing.T) {
+	baseURL, _, _ := startServer(t, server.Options{PollInterval: time.Hour})
+
+	resp, _ := doRequest(t, "GET", baseURL+"/v1/operations/operations/missing", "", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = doRequest(t, "POST", baseURL+"/v1/operations/operations/missing:cancel", "", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// Submitted but not yet polled: download is not ready
+	resp, _ = doRequest(t, "POST", baseURL+"/v1/generate", "", `{"prompt": "A cat"}`)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	resp, env := doRequest(t, "GET", baseURL+"/v1/operations/"+operationName+":download", "", "")
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "NOT_READY", env.Error.Code)
+
+	resp, _ = doRequest(t, "POST", baseURL+"/v1/operations/"+operationName+":explode", "", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestServer_Cancel(t *testing.T) {
+	baseURL, api, _ := startServer(t, server.Options{PollInterval: time.Hour})
+
+	resp, _ := doRequest(t, "POST", baseURL+"/v1/generate", "", `{"prompt": "A cat"}`)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	resp, env := doRequest(t, "POST", baseURL+"/v1/operations/"+operationName+":cancel", "", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var record server.OperationRecord
+	require.NoError(t, json.Unmarshal(env.Data, &record))
+	assert.Equal(t, veo3.StatusCancelled, record.Operation.Status)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&api.cancelled))
+}
+
+func TestServer_BearerAuth(t *testing.T) {
+	baseURL, _, _ := startServer(t, server.Options{Token: "s3cret", PollInterval: time.Hour})
+
+	// Health and OpenAPI are public
+	resp, _ := doRequest(t, "GET", baseURL+"/healthz", "", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, _ = doRequest(t, "GET", baseURL+"/openapi.json", "", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, env := doRequest(t, "GET", baseURL+"/v1/operations", "", "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, "UNAUTHORIZED", env.Error.Code)
+
+	resp, _ = doRequest(t, "GET", baseURL+"/v1/operations", "wrong", "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = doRequest(t, "GET", baseURL+"/v1/operations", "s3cret", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestServer_OpenAPI(t *testing.T) {
+	baseURL, _, _ := startServer(t, server.Options{PollInterval: time.Hour})
+
+	resp, err := http.Get(baseURL + "/openapi.json")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	var spec struct {
+		OpenAPI string                 `json:"openapi"`
+		Paths   map[string]interface{} `json:"paths"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&spec))
+	assert.Equal(t, "3.0.3", spec.OpenAPI)
+	for _, path := range []string{"/v1/generate", "/v1/operations/{name}:cancel", "/v1/operations/{name}:download", "/v1/batches"} {
+		assert.Contains(t, spec.Paths, path)
+	}
+}
+
+func TestServer_MetricsEndpoints(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		baseURL, _, _ := startServer(t, server.Options{PollInterval: time.Hour})
+
+		resp, err := http.Get(baseURL + "/metrics")
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("serves configured handlers", func(t *testing.T) {
+		metricsHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("veo3_up 1\n"))
+		})
+		baseURL, _, _ := startServer(t, server.Options{PollInterval: time.Hour, Metrics: metricsHandler, DebugVars: true})
+
+		resp, err := http.Get(baseURL + "/metrics")
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		assert.Equal(t, "veo3_up 1\n", string(body))
+
+		resp, err = http.Get(baseURL + "/debug/vars")
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestServer_Batches(t *testing.T) {
+	t.Run("not configured", func(t *testing.T) {
+		baseURL, _, _ := startServer(t, server.Options{PollInterval: time.Hour})
+		resp, _ := doRequest(t, "POST", baseURL+"/v1/batches", "", "jobs: []")
+		assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+	})
+
+	t.Run("submit and track", func(t *testing.T) {
+		baseURL, _, _ := startServer(t, server.Options{
+			PollInterval: time.Hour,
+			NewExecutor: func(manifest *batch.BatchManifest) batch.JobExecutor {
+				return stubExecutor{}
+			},
+		})
+
+		resp, env := doRequest(t, "POST", baseURL+"/v1/batches", "", "{}")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "INVALID_MANIFEST", env.Error.Code)
+
+		manifest := `
+jobs:
+  - id: job1
+    type: generate
+    options:
+      prompt: "A sunset"
+    output: sunset.mp4
+  - id: job2
+    type: generate
+    options:
+      prompt: "A sunrise"
+    output: sunrise.mp4
+`
+		resp, env = doRequest(t, "POST", baseURL+"/v1/batches", "", manifest)
+		require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+		var record server.BatchRecord
+		require.NoError(t, json.Unmarshal(env.Data, &record))
+		assert.Equal(t, 2, record.Jobs)
+
+		require.Eventually(t, func() bool {
+			_, env := doRequest(t, "GET", baseURL+"/v1/batches/"+record.ID, "", "")
+			var current server.BatchRecord
+			_ = json.Unmarshal(env.Data, &current)
+			return current.Status == "completed" && current.Summary != nil && current.Summary.SuccessfulJobs == 2
+		}, 3*time.Second, 20*time.Millisecond)
+
+		resp, _ = doRequest(t, "GET", baseURL+"/v1/batches/missing", "", "")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("outputs stay in the output directory", func(t *testing.T) {
+		outputDir := t.TempDir()
+		var got string
+		baseURL, _, _ := startServer(t, server.Options{
+			OutputDir:    outputDir,
+			PollInterval: time.Hour,
+			NewExecutor: func(manifest *batch.BatchManifest) batch.JobExecutor {
+				got = manifest.OutputDirectory
+				return stubExecutor{}
+			},
+		})
+
+		for _, manifest := range []string{
+			"output_directory: /tmp/elsewhere\njobs:\n  - {id: a, type: generate, options: {prompt: x}, output: a.mp4}\n",
+			"jobs:\n  - {id: a, type: generate, options: {prompt: x}, output: /etc/cron.d/a.mp4}\n",
+			"jobs:\n  - {id: a, type: generate, options: {prompt: x}, output: ../a.mp4}\n",
+		} {
+			resp, env := doRequest(t, "POST", baseURL+"/v1/batches", "", manifest)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, manifest)
+			require.NotNil(t, env.Error)
+			assert.Equal(t, "INVALID_MANIFEST", env.Error.Code)
+		}
+
+		resp, _ := doRequest(t, "POST", baseURL+"/v1/batches", "",
+			"jobs:\n  - {id: a, type: generate, options: {prompt: x}, output: clips/a.mp4}\n")
+		require.Equal(t, http.StatusAccepted, resp.StatusCode)
+		assert.Equal(t, outputDir, got)
+	})
+
+	t.Run("inputs stay in the input directory", func(t *testing.T) {
+		var got []batch.BatchJob
+		baseURL, _, _ := startServer(t, server.Options{
+			InputDir:     "media",
+			PollInterval: time.Hour,
+			NewExecutor: func(manifest *batch.BatchManifest) batch.JobExecutor {
+				got = manifest.Jobs
+				return stubExecutor{}
+			},
+		})
+
+		for _, manifest := range []string{
+			"jobs:\n  - {id: a, type: animate, options: {prompt: x, image: /etc/passwd.png}, output: a.mp4}\n",
+			"jobs:\n  - {id: a, type: extend, options: {prompt: x, video: ../a.mp4}, output: a.mp4}\n",
+			"jobs:\n  - {id: a, type: generate, options: {prompt: x, reference_images: [ok.png, /etc/b.png]}, output: a.mp4}\n",
+		} {
+			resp, env := doRequest(t, "POST", baseURL+"/v1/batches", "", manifest)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, manifest)
+			require.NotNil(t, env.Error)
+			assert.Equal(t, "INVALID_MANIFEST", env.Error.Code)
+			assert.Contains(t, env.Error.Message, "must be a relative path inside the input directory")
+		}
+
+		resp, _ := doRequest(t, "POST", baseURL+"/v1/batches", "", `
+jobs:
+  - {id: a, type: animate, options: {prompt: x, image: stills/a.png}, output: a.mp4}
+  - {id: b, type: extend, options: {prompt: x, video: "${jobs.a.output}"}, output: b.mp4}
+`)
+		require.Equal(t, http.StatusAccepted, resp.StatusCode)
+		require.Len(t, got, 2)
+		assert.Equal(t, filepath.Join("media", "stills", "a.png"), got[0].StringOption("image", ""))
+		assert.Equal(t, "${jobs.a.output}", got[1].StringOption("video", ""), "job outputs are left to the batch")
+	})
+
+	t.Run("concurrency is limited by the server", func(t *testing.T) {
+		var got int
+		baseURL, _, _ := startServer(t, server.Options{
+			MaxConcurrency: 2,
+			PollInterval:   time.Hour,
+			NewExecutor: func(manifest *batch.BatchManifest) batch.JobExecutor {
+				got = manifest.Concurrency
+				return stubExecutor{}
+			},
+		})
+
+		for concurrency, want := range map[int]int{1: 1, 2: 2, 50: 2} {
+			resp, _ := doRequest(t, "POST", baseURL+"/v1/batches", "", fmt.Sprintf(
+				"concurrency: %d\njobs:\n  - {id: a, type: generate, options: {prompt: x}, output: a.mp4}\n", concurrency))
+			require.Equal(t, http.StatusAccepted, resp.StatusCode)
+			assert.Equal(t, want, got, "concurrency %d", concurrency)
+		}
+	})
+
+	t.Run("data sources are rejected", func(t *testing.T) {
+		baseURL, _, _ := startServer(t, server.Options{
+			PollInterval: time.Hour,
+			NewExecutor: func(manifest *batch.BatchManifest) batch.JobExecutor {
+				return stubExecutor{}
+			},
+		})
+
+		manifest := `
+jobs:
+  - id: rows
+    type: generate
+    data: {file: /etc/passwd}
+    options:
+      prompt: "{{.name}}"
+    output: "{{.name}}.mp4"
+`
+		resp, env := doRequest(t, "POST", baseURL+"/v1/batches", "", manifest)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "INVALID_MANIFEST", env.Error.Code)
+		assert.Contains(t, env.Error.Message, "job rows: data sources are not allowed in submitted manifests")
+	})
+}
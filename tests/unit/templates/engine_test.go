@@ -0,0 +1,177 @@
+package templates_test
+
+import (
+	"testing"
+
+	"github.com/jasongoecke/go-veo3/pkg/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubstituteVariables_Engine(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  string
+		variables map[string]string
+		want      string
+	}{
+		{
+			name:     "default used when unset",
+			template: "A forest in {{season | default \"autumn\"}}",
+			want:     "A forest in autumn",
+		},
+		{
+			name:      "default used when empty",
+			template:  "A forest in {{ season | default \"autumn\" }}",
+			variables: map[string]string{"season": ""},
+			want:      "A forest in autumn",
+		},
+		{
+			name:      "default ignored when set",
+			template:  "A forest in {{season | default \"autumn\"}}",
+			variables: map[string]string{"season": "winter"},
+			want:      "A forest in winter",
+		},
+		{
+			name:      "case filters",
+			template:  "{{a | upper}} {{b | lower}} {{c | title}} {{d | capitalize}} [{{e | trim}}]",
+			variables: map[string]string{"a": "loud", "b": "QUIET", "c": "the RED fox", "d": "a cat", "e": "  x  "},
+			want:      "LOUD quiet The Red Fox A cat [x]",
+		},
+		{
+			name:      "filters chain after default",
+			template:  "{{mood | default \"calm\" | upper}}",
+			want:      "CALM",
+			variables: map[string]string{},
+		},
+		{
+			name:      "join list",
+			template:  "Styles: {{styles | join \" and \"}}",
+			variables: map[string]string{"styles": "noir, neon ,watercolor"},
+			want:      "Styles: noir and neon and watercolor",
+		},
+		{
+			name:      "join default separator",
+			template:  "{{styles | upper | join}}",
+			variables: map[string]string{"styles": "noir,neon"},
+			want:      "NOIR, NEON",
+		},
+		{
+			name:      "if set",
+			template:  "A fox{{if mood}}, {{mood}} mood{{end}}",
+			variables: map[string]string{"mood": "eerie"},
+			want:      "A fox, eerie mood",
+		},
+		{
+			name:     "if unset skips missing variable",
+			template: "A fox{{if mood}}, {{mood}} mood{{end}}",
+			want:     "A fox",
+		},
+		{
+			name:     "if else",
+			template: "{{if night}}moonlit{{else}}sunlit{{end}} field",
+			want:     "sunlit field",
+		},
+		{
+			name:      "if not",
+			template:  "field{{if not night}} at noon{{end}}",
+			variables: map[string]string{"night": "yes"},
+			want:      "field",
+		},
+		{
+			name:      "range",
+			template:  "A city{{range styles}}, {{. | upper}}{{end}}",
+			variables: map[string]string{"styles": "noir,neon"},
+			want:      "A city, NOIR, NEON",
+		},
+		{
+			name:     "range else",
+			template: "{{range styles}}#{{.}} {{else}}no style{{end}}",
+			want:     "no style",
+		},
+		{
+			name:      "nested blocks",
+			template:  "{{range shots}}{{if lens}}{{lens}} {{end}}{{.}};{{end}}",
+			variables: map[string]string{"shots": "wide,close", "lens": "35mm"},
+			want:      "35mm wide;35mm close;",
+		},
+		{
+			name:      "extra variables ignored",
+			template:  "A {{subject}}",
+			variables: map[string]string{"subject": "cat", "unused": "x"},
+			want:      "A cat",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := templates.SubstituteVariables(tt.template, tt.variables)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSubstituteVariables_EngineErrors(t *testing.T) {
+	_, err := templates.SubstituteVariables("{{subject | upper}} at dusk", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required variable: subject")
+
+	_, err = templates.SubstituteVariables("{{.}}", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "{{range}}")
+}
+
+func TestValidateTemplate_Engine(t *testing.T) {
+	valid := []string{
+		"{{ subject }} in {{season | default \"autumn\" | title}}",
+		"{{if mood}}{{mood}}{{else}}calm{{end}}",
+		"{{range styles}}{{.}}{{else}}none{{end}}",
+		"{{styles | join \", \"}}",
+	}
+	for _, template := range valid {
+		assert.NoError(t, templates.ValidateTemplate(template), template)
+	}
+
+	invalid := map[string]string{
+		"{{subject | shout}}":               "unknown filter",
+		"{{subject | default}}":             "takes 1 argument",
+		"{{subject | upper \"x\"}}":         "takes no arguments",
+		"{{subject | default autumn}}":      "must be quoted",
+		"{{if mood}}calm":                   "missing {{end}}",
+		"calm{{end}}":                       "unexpected {{end}}",
+		"{{else}}":                          "unexpected {{else}}",
+		"{{if a}}x{{else}}y{{else}}{{end}}": "unexpected {{else}}",
+		"{{range}}x{{end}}":                 "missing variable name",
+		"{{subject \"x\"}}":                 "separated by |",
+		"{{sub-ject}}":                      "invalid variable name",
+		"{{season | default \"autumn}}":     "unterminated string",
+	}
+	for template, want := range invalid {
+		err := templates.ValidateTemplate(template)
+		if assert.Error(t, err, template) {
+			assert.Contains(t, err.Error(), want, template)
+		}
+	}
+}
+
+func TestAnalyzeVariables(t *testing.T) {
+	vars, err := templates.AnalyzeVariables(
+		`{{subject}} in {{season | default "autumn"}}{{if mood}}, {{mood}}{{end}}{{range styles}} {{.}}{{end}}, {{tags | join}}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []templates.Variable{
+		{Name: "subject", Required: true},
+		{Name: "season", HasDefault: true, Default: "autumn"},
+		{Name: "mood"},
+		{Name: "styles", List: true},
+		{Name: "tags", Required: true, List: true},
+	}, vars)
+
+	required, err := templates.RequiredVariables(`{{subject}}{{if mood}}{{mood}}{{end}}, {{mood}}`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"subject", "mood"}, required, "used outside a section is required")
+
+	assert.Equal(t, []string{"subject", "season", "mood"},
+		templates.ExtractVariables(`{{subject}} {{season | default "x"}}{{if mood}}!{{end}}`))
+}
@@ -0,0 +1,141 @@
+package templates_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jasongoecke/go-veo3/pkg/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// saveVersions saves each prompt in turn under the same name
+func saveVersions(t *testing.T, manager *templates.Manager, name string, prompts ...string) {
+	t.Helper()
+	for _, prompt := range prompts {
+		require.NoError(t, manager.Save(&templates.Template{Name: name, Prompt: prompt}))
+	}
+}
+
+func TestManager_SaveKeepsVersions(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := templates.NewManager(dir)
+	require.NoError(t, err)
+
+	saveVersions(t, manager, "promo", "A red car", "A blue car", "A blue car", "A green car")
+
+	current, err := manager.Get("promo")
+	require.NoError(t, err)
+	assert.Equal(t, 3, current.CurrentVersion(), "saving unchanged content does not add a version")
+	assert.Equal(t, "promo@3", current.Ref())
+
+	versions := current.Versions()
+	require.Len(t, versions, 3)
+	assert.Equal(t, []string{"A red car", "A blue car", "A green car"},
+		[]string{versions[0].Prompt, versions[1].Prompt, versions[2].Prompt})
+	assert.Equal(t, current.CreatedAt, versions[0].SavedAt)
+
+	// History survives a reload
+	reloaded, err := templates.NewManager(dir)
+	require.NoError(t, err)
+	pinned, err := reloaded.Resolve("promo@v1")
+	require.NoError(t, err)
+	assert.Equal(t, "A red car", pinned.Prompt)
+	assert.Equal(t, 1, pinned.CurrentVersion())
+	assert.Empty(t, pinned.History)
+
+	_, err = reloaded.Resolve("promo@9")
+	assert.ErrorContains(t, err, "has no version 9")
+	_, err = reloaded.Resolve("promo@latest")
+	assert.ErrorContains(t, err, "invalid version")
+}
+
+func TestManager_LegacyTemplateIsVersionOne(t *testing.T) {
+	manager, err := templates.NewManager(t.TempDir())
+	require.NoError(t, err)
+
+	legacy := &templates.Template{Name: "old", Prompt: "A cat"}
+	require.NoError(t, manager.Save(legacy))
+	legacy.Version = 0 // as written before versioning
+
+	assert.Equal(t, 1, legacy.CurrentVersion())
+	require.NoError(t, manager.Save(&templates.Template{Name: "old", Prompt: "A dog"}))
+	updated, err := manager.Get("old")
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated.CurrentVersion())
+}
+
+func TestManager_Rollback(t *testing.T) {
+	manager, err := templates.NewManager(t.TempDir())
+	require.NoError(t, err)
+	saveVersions(t, manager, "promo", "v1 prompt", "v2 prompt", "v3 prompt")
+
+	restored, err := manager.Rollback("promo", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 4, restored.CurrentVersion(), "rollback adds a version")
+	assert.Equal(t, "v1 prompt", restored.Prompt)
+	assert.Len(t, restored.History, 3)
+
+	_, err = manager.Rollback("promo", 4)
+	assert.ErrorContains(t, err, "already at version 4")
+	_, err = manager.Rollback("missing", 1)
+	assert.Error(t, err)
+}
+
+func TestManager_ImportAddsVersion(t *testing.T) {
+	dir := t.TempDir()
+	source, err := templates.NewManager(filepath.Join(dir, "source"))
+	require.NoError(t, err)
+	saveVersions(t, source, "promo", "imported prompt")
+	exportPath := filepath.Join(dir, "export.yaml")
+	require.NoError(t, source.Export(exportPath))
+
+	target, err := templates.NewManager(filepath.Join(dir, "target"))
+	require.NoError(t, err)
+	saveVersions(t, target, "promo", "local v1", "local v2")
+	require.NoError(t, target.Import(exportPath))
+
+	promo, err := target.Get("promo")
+	require.NoError(t, err)
+	assert.Equal(t, 3, promo.CurrentVersion())
+	assert.Equal(t, "imported prompt", promo.Prompt)
+}
+
+func TestDiff(t *testing.T) {
+	from := &templates.Template{Name: "promo", Prompt: "Line one\nLine two", Tags: []string{"a"}}
+	to := &templates.Template{Name: "promo", Prompt: "Line one\nLine 2", Tags: []string{"a"},
+		Preset: &templates.Preset{Resolution: "1080p"}}
+
+	var lines []string
+	for _, line := range templates.Diff(from, to) {
+		lines = append(lines, line.String())
+	}
+	assert.Equal(t, []string{
+		"  prompt:",
+		"    Line one",
+		"-   Line two",
+		"+   Line 2",
+		"  tags: a",
+		"+ resolution: 1080p",
+	}, lines)
+
+	for _, line := range templates.Diff(from, from) {
+		assert.Equal(t, byte(' '), line.Op, strings.TrimSpace(line.Text))
+	}
+}
+
+func TestParseRef(t *testing.T) {
+	name, version, err := templates.ParseRef("promo@4")
+	require.NoError(t, err)
+	assert.Equal(t, "promo", name)
+	assert.Equal(t, 4, version)
+
+	name, version, err = templates.ParseRef("promo")
+	require.NoError(t, err)
+	assert.Equal(t, "promo", name)
+	assert.Zero(t, version)
+
+	_, _, err = templates.ParseRef("promo@0")
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package templates_test
+
+import (
+	"testing"
+
+	"github.com/jasongoecke/go-veo3/pkg/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_SavePreset(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := templates.NewManager(dir)
+	require.NoError(t, err)
+
+	preset := &templates.Preset{
+		Model:          "veo-3.1-generate-preview",
+		Resolution:     "1080p",
+		Duration:       8,
+		NegativePrompt: "text",
+	}
+	require.NoError(t, manager.Save(&templates.Template{Name: "promo", Prompt: "{{product}}", Preset: preset}))
+
+	// Reload from disk
+	reloaded, err := templates.NewManager(dir)
+	require.NoError(t, err)
+	got, err := reloaded.Get("promo")
+	require.NoError(t, err)
+	assert.Equal(t, preset, got.Preset)
+
+	t.Run("empty prompt allowed with preset", func(t *testing.T) {
+		err := manager.Save(&templates.Template{Name: "spin", Preset: &templates.Preset{Image: "/tmp/a.png"}})
+		assert.NoError(t, err)
+	})
+
+	t.Run("empty prompt rejected without preset", func(t *testing.T) {
+		err := manager.Save(&templates.Template{Name: "empty"})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid preset", func(t *testing.T) {
+		err := manager.Save(&templates.Template{Name: "bad", Prompt: "x", Preset: &templates.Preset{Type: "generate", Image: "/tmp/a.png"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "preset image requires")
+	})
+}
+
+func TestPreset_JobType(t *testing.T) {
+	var none *templates.Preset
+	assert.Equal(t, templates.TypeGenerate, none.JobType())
+	assert.Equal(t, templates.TypeGenerate, (&templates.Preset{Model: "m"}).JobType())
+	assert.Equal(t, templates.TypeAnimate, (&templates.Preset{Image: "a.png"}).JobType())
+	assert.Equal(t, templates.TypeInterpolate, (&templates.Preset{Image: "a.png", LastFrame: "b.png"}).JobType())
+	assert.Equal(t, templates.TypeInterpolate, (&templates.Preset{Type: "interpolate"}).JobType())
+}
+
+func TestPreset_CheckType(t *testing.T) {
+	assert.NoError(t, (&templates.Preset{Model: "m", Duration: 8}).CheckType(templates.TypeAnimate), "settings-only presets fit any type")
+	assert.NoError(t, (&templates.Preset{Image: "a.png"}).CheckType(templates.TypeAnimate))
+	assert.Error(t, (&templates.Preset{Image: "a.png"}).CheckType(templates.TypeGenerate))
+	assert.Error(t, (&templates.Preset{Type: "interpolate"}).CheckType(templates.TypeAnimate))
+}
+
+func TestPreset_Options(t *testing.T) {
+	preset := &templates.Preset{
+		Model:           "veo-3.1-fast-generate-preview",
+		AspectRatio:     "9:16",
+		Duration:        6,
+		Image:           "/in/first.png",
+		LastFrame:       "/in/last.png",
+		ReferenceImages: []string{"/in/ref.png"},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"model":        "veo-3.1-fast-generate-preview",
+		"aspect_ratio": "9:16",
+		"duration":     6,
+		"first_frame":  "/in/first.png",
+		"last_frame":   "/in/last.png",
+	}, preset.Options(templates.TypeInterpolate))
+
+	assert.Equal(t, "/in/first.png", preset.Options(templates.TypeAnimate)["image"])
+	assert.Equal(t, []string{"/in/ref.png"}, preset.Options(templates.TypeGenerate)["reference_images"])
+}
@@ -236,6 +236,9 @@ func TestClient_GetOperation(t *testing.T) {
 		mockStatusCode int
 		wantErr        bool
 		expectedStatus veo3.OperationStatus
+		// Checked for failed operations when set
+		expectedErrorCode    string
+		expectedErrorMessage string
 	}{
 		{
 			name:        "operation pending",
@@ -283,6 +286,26 @@ func TestClient_GetOperation(t *testing.T) {
 			wantErr:        false,
 			expectedStatus: veo3.StatusFailed,
 		},
+		{
+			name:        "operation blocked by safety filter",
+			operationID: "operations/test-op-filtered",
+			mockResponse: map[string]interface{}{
+				"name": "operations/test-op-filtered",
+				"done": true,
+				"response": map[string]interface{}{
+					"@type": "type.googleapis.com/google.ai.generativelanguage.v1beta.PredictLongRunningResponse",
+					"generateVideoResponse": map[string]interface{}{
+						"raiMediaFilteredCount":   1,
+						"raiMediaFilteredReasons": []string{"The prompt could not be submitted."},
+					},
+				},
+			},
+			mockStatusCode:       http.StatusOK,
+			wantErr:              false,
+			expectedStatus:       veo3.StatusFailed,
+			expectedErrorCode:    veo3.SafetyFilteredCode,
+			expectedErrorMessage: "The prompt could not be submitted.",
+		},
 		{
 			name:        "operation not found",
 			operationID: "operations/not-found",
@@ -330,13 +353,56 @@ func TestClient_GetOperation(t *testing.T) {
 				case veo3.StatusDone:
 					assert.NotEmpty(t, operation.VideoURI, "Completed operation should have video URI")
 				case veo3.StatusFailed:
-					assert.NotNil(t, operation.Error, "Failed operation should have error details")
+					require.NotNil(t, operation.Error, "Failed operation should have error details")
+					if tt.expectedErrorCode != "" {
+						assert.Equal(t, tt.expectedErrorCode, operation.Error.Code)
+					}
+					assert.Contains(t, operation.Error.Message, tt.expectedErrorMessage)
 				}
 			}
 		})
 	}
 }
 
+func TestClient_CancelOperation(t *testing.T) {
+	t.Run("posts to the cancel method", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "/models/veo/operations/abc123:cancel", r.URL.Path)
+			assert.Equal(t, "test-api-key", r.Header.Get("x-goog-api-key"))
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("{}"))
+		}))
+		defer mockServer.Close()
+
+		client, err := veo3.NewClient(context.Background(), "test-api-key", veo3.WithBaseURL(mockServer.URL))
+		require.NoError(t, err)
+
+		require.NoError(t, client.CancelOperation(context.Background(), "models/veo/operations/abc123"))
+	})
+
+	t.Run("surfaces API errors", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Operation not found","status":"NOT_FOUND"}}`))
+		}))
+		defer mockServer.Close()
+
+		client, err := veo3.NewClient(context.Background(), "test-api-key", veo3.WithBaseURL(mockServer.URL))
+		require.NoError(t, err)
+
+		err = client.CancelOperation(context.Background(), "operations/missing")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Operation not found")
+	})
+
+	t.Run("requires an operation ID", func(t *testing.T) {
+		client, err := veo3.NewClient(context.Background(), "test-api-key")
+		require.NoError(t, err)
+		assert.Error(t, client.CancelOperation(context.Background(), ""))
+	})
+}
+
 func TestClient_Authentication(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -0,0 +1,212 @@
+package veo3_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jasongoecke/go-veo3/pkg/operations"
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// headerRecorder records the value of a header on every request by path
+type headerRecorder struct {
+	mu      sync.Mutex
+	headers map[string]string
+}
+
+func (h *headerRecorder) record(r *http.Request, key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.headers[r.URL.Path] = r.Header.Get(key)
+}
+
+func (h *headerRecorder) get(path string) (string, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	value, ok := h.headers[path]
+	return value, ok
+}
+
+func TestMiddleware_AppliesToEveryCall(t *testing.T) {
+	recorder := &headerRecorder{headers: map[string]string{}}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder.record(r, "x-goog-user-project")
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, ":predictLongRunning"):
+			_, _ = w.Write([]byte(`{"name": "operations/op-1"}`))
+		case strings.HasSuffix(r.URL.Path, ":cancel"):
+			_, _ = w.Write([]byte(`{}`))
+		case r.URL.Path == "/operations":
+			_, _ = w.Write([]byte(`{"operations": [{"name": "operations/op-1", "done": false}]}`))
+		case r.URL.Path == "/operations/op-1":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"name":     "operations/op-1",
+				"done":     true,
+				"response": map[string]interface{}{"videoUri": server.URL + "/video.mp4"},
+			})
+		case r.URL.Path == "/video.mp4":
+			_, _ = w.Write([]byte("video"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client, err := veo3.NewClient(ctx, "test-api-key",
+		veo3.WithBaseURL(server.URL),
+		veo3.WithMiddleware(veo3.WithHeader("x-goog-user-project", "billing-project")))
+	require.NoError(t, err)
+
+	_, err = client.GenerateVideo(ctx, &veo3.GenerationRequest{
+		Prompt:          "A cat playing piano",
+		Model:           "veo-3.1-generate-preview",
+		AspectRatio:     "16:9",
+		Resolution:      "720p",
+		DurationSeconds: 8,
+	})
+	require.NoError(t, err)
+
+	op, err := client.GetOperation(ctx, "operations/op-1")
+	require.NoError(t, err)
+
+	_, err = client.ListOperations(ctx, "")
+	require.NoError(t, err)
+
+	require.NoError(t, client.CancelOperation(ctx, "operations/op-1"))
+
+	downloader := operations.NewDownloaderForClient(client, false)
+	_, err = downloader.DownloadVideo(ctx, op, filepath.Join(t.TempDir(), "video.mp4"))
+	require.NoError(t, err)
+
+	for _, path := range []string{
+		"/models/veo-3.1-generate-preview:predictLongRunning",
+		"/operations/op-1",
+		"/operations",
+		"/operations/op-1:cancel",
+		"/video.mp4",
+	} {
+		value, ok := recorder.get(path)
+		require.True(t, ok, "no request to %s", path)
+		assert.Equal(t, "billing-project", value, path)
+	}
+}
+
+func TestMiddleware_Order(t *testing.T) {
+	var calls []string
+	trace := func(name string) veo3.Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return veo3.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" before")
+				resp, err := next.RoundTrip(req)
+				calls = append(calls, name+" after")
+				return resp, err
+			})
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "server")
+		_, _ = w.Write([]byte(`{"name": "operations/op-1", "done": false}`))
+	}))
+	defer server.Close()
+
+	client, err := veo3.NewClient(context.Background(), "test-api-key",
+		veo3.WithBaseURL(server.URL),
+		veo3.WithMiddleware(trace("outer")),
+		veo3.WithMiddleware(trace("inner")))
+	require.NoError(t, err)
+
+	_, err = client.GetOperation(context.Background(), "operations/op-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"outer before", "inner before", "server", "inner after", "outer after"}, calls)
+}
+
+func TestMiddleware_CanRejectRequests(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer server.Close()
+
+	denied := errors.New("blocked by policy")
+	policy := func(next http.RoundTripper) http.RoundTripper {
+		return veo3.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodPost {
+				return nil, denied
+			}
+			return next.RoundTrip(req)
+		})
+	}
+
+	client, err := veo3.NewClient(context.Background(), "test-api-key",
+		veo3.WithBaseURL(server.URL), veo3.WithMiddleware(policy))
+	require.NoError(t, err)
+
+	err = client.CancelOperation(context.Background(), "operations/op-1")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, denied)
+	assert.Zero(t, hits)
+}
+
+func TestMiddleware_PreservesCustomHTTPClient(t *testing.T) {
+	var sawBase bool
+	base := veo3.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		sawBase = req.Header.Get("X-Request-Id") == "req-42"
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"name": "operations/op-1", "done": false}`))
+	}))
+	defer server.Close()
+
+	client, err := veo3.NewClient(context.Background(), "test-api-key",
+		veo3.WithBaseURL(server.URL),
+		veo3.WithHTTPClient(&http.Client{Transport: base}),
+		veo3.WithMiddleware(veo3.WithHeader("X-Request-Id", "req-42")))
+	require.NoError(t, err)
+
+	_, err = client.GetOperation(context.Background(), "operations/op-1")
+	require.NoError(t, err)
+	assert.True(t, sawBase, "middleware should wrap the custom client's transport")
+}
+
+func TestClient_ListOperations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-api-key", r.Header.Get("x-goog-api-key"))
+		if r.URL.Query().Get("pageToken") == "" {
+			_, _ = w.Write([]byte(`{"operations": [{"name": "operations/a", "done": false}], "nextPageToken": "p2"}`))
+			return
+		}
+		assert.Equal(t, "p2", r.URL.Query().Get("pageToken"))
+		_, _ = w.Write([]byte(`{"operations": [{"name": "operations/b", "done": true, "response": {"videoUri": "https://example.com/b.mp4"}}]}`))
+	}))
+	defer server.Close()
+
+	client, err := veo3.NewClient(context.Background(), "test-api-key", veo3.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	all, err := client.ListOperations(context.Background(), "")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "operations/a", all[0].ID)
+	assert.Equal(t, "operations/b", all[1].ID)
+
+	done, err := client.ListOperations(context.Background(), veo3.StatusDone)
+	require.NoError(t, err)
+	require.Len(t, done, 1)
+	assert.Equal(t, "operations/b", done[0].ID)
+}
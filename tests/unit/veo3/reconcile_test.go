@@ -0,0 +1,174 @@
+package veo3_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestPNG creates a solid-color PNG of the given size and returns its path
+func writeTestPNG(t *testing.T, dir, name string, width, height int) string {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0600))
+	return path
+}
+
+func TestParseFrameFitMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    veo3.FrameFitMode
+		wantErr bool
+	}{
+		{input: "", want: veo3.FrameFitNone},
+		{input: "scale", want: veo3.FrameFitScale},
+		{input: "CROP", want: veo3.FrameFitCrop},
+		{input: "stretch", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := veo3.ParseFrameFitMode(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "invalid frame fit mode")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPlanFrameReconciliation(t *testing.T) {
+	dir := t.TempDir()
+	first := writeTestPNG(t, dir, "first.png", 160, 90)
+	wide := writeTestPNG(t, dir, "wide.png", 200, 90)
+	tall := writeTestPNG(t, dir, "tall.png", 160, 120)
+	same := writeTestPNG(t, dir, "same.png", 160, 90)
+
+	t.Run("scale reports source and target", func(t *testing.T) {
+		plan, err := veo3.PlanFrameReconciliation(first, wide, veo3.FrameFitScale)
+		require.NoError(t, err)
+		assert.True(t, plan.Applied())
+		assert.Equal(t, 200, plan.SourceWidth)
+		assert.Equal(t, 160, plan.TargetWidth)
+		assert.Nil(t, plan.CropRect)
+		assert.Contains(t, plan.String(), "rescaled last frame from 200x90 to 160x90")
+	})
+
+	t.Run("crop trims sides of wider frame", func(t *testing.T) {
+		plan, err := veo3.PlanFrameReconciliation(first, wide, veo3.FrameFitCrop)
+		require.NoError(t, err)
+		require.NotNil(t, plan.CropRect)
+		assert.Equal(t, image.Rect(20, 0, 180, 90), *plan.CropRect)
+	})
+
+	t.Run("crop trims top and bottom of taller frame", func(t *testing.T) {
+		plan, err := veo3.PlanFrameReconciliation(first, tall, veo3.FrameFitCrop)
+		require.NoError(t, err)
+		require.NotNil(t, plan.CropRect)
+		assert.Equal(t, image.Rect(0, 15, 160, 105), *plan.CropRect)
+	})
+
+	t.Run("matching dimensions need no transformation", func(t *testing.T) {
+		plan, err := veo3.PlanFrameReconciliation(first, same, veo3.FrameFitCrop)
+		require.NoError(t, err)
+		assert.False(t, plan.Applied())
+		assert.Nil(t, plan.CropRect)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		_, err := veo3.PlanFrameReconciliation(first, wide, veo3.FrameFitNone)
+		require.Error(t, err)
+	})
+}
+
+func TestReconcileLastFrame(t *testing.T) {
+	dir := t.TempDir()
+	first := writeTestPNG(t, dir, "first.png", 160, 90)
+	last := writeTestPNG(t, dir, "last.png", 64, 64)
+
+	for _, mode := range []veo3.FrameFitMode{veo3.FrameFitScale, veo3.FrameFitCrop} {
+		t.Run(string(mode), func(t *testing.T) {
+			data, plan, err := veo3.ReconcileLastFrame(first, last, mode)
+			require.NoError(t, err)
+			assert.True(t, plan.Applied())
+
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+			require.NoError(t, err)
+			assert.Equal(t, "png", format)
+			assert.Equal(t, 160, cfg.Width)
+			assert.Equal(t, 90, cfg.Height)
+		})
+	}
+
+	t.Run("jpeg source stays jpeg", func(t *testing.T) {
+		data, _, err := veo3.ReconcileLastFrame("testdata/1920x1080_frame1.jpg", "testdata/1280x720_frame2.jpg", veo3.FrameFitScale)
+		require.NoError(t, err)
+
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+		assert.Equal(t, 1920, cfg.Width)
+		assert.Equal(t, 1080, cfg.Height)
+	})
+}
+
+func TestInterpolationRequest_FrameFit(t *testing.T) {
+	dir := t.TempDir()
+	first := writeTestPNG(t, dir, "first.png", 160, 90)
+	last := writeTestPNG(t, dir, "last.png", 320, 240)
+
+	request := &veo3.InterpolationRequest{
+		GenerationRequest: veo3.GenerationRequest{
+			Model:           "veo-3.1-generate-preview",
+			AspectRatio:     "16:9",
+			Resolution:      "720p",
+			DurationSeconds: 8,
+		},
+		FirstFramePath: first,
+		LastFramePath:  last,
+	}
+
+	// Without a fit mode, mismatched frames are rejected
+	err := request.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "image dimensions mismatch")
+
+	// With a fit mode, the payload carries the reconciled last frame
+	request.FrameFit = veo3.FrameFitCrop
+	require.NoError(t, request.Validate())
+
+	payload, err := veo3.BuildInterpolationPayload(request)
+	require.NoError(t, err)
+
+	lastFrame := payload["lastFrame"].(map[string]interface{})
+	decoded, err := base64.StdEncoding.DecodeString(lastFrame["data"].(string))
+	require.NoError(t, err)
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(decoded))
+	require.NoError(t, err)
+	assert.Equal(t, 160, cfg.Width)
+	assert.Equal(t, 90, cfg.Height)
+}
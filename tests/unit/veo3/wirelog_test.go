@@ -0,0 +1,139 @@
+package veo3_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wireRecords decodes JSON log lines written by a slog JSON handler
+func wireRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestWireLogger_RedactsKeysAndTruncatesBase64(t *testing.T) {
+	blob := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("frame"), 400))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Contains(t, string(body), blob, "server must receive the full payload")
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name": "operations/op-1", "echo": "` + blob + `"}`))
+	}))
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	transport := veo3.WireLogger(logger)(http.DefaultTransport)
+
+	req, err := http.NewRequest("POST", server.URL+"/v1/models?key=secret-key&alt=json",
+		strings.NewReader(`{"image": {"bytesBase64Encoded": "`+blob+`"}, "prompt": "A cat"}`))
+	require.NoError(t, err)
+	req.Header.Set("x-goog-api-key", "secret-key")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	// The caller still receives the full response body
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), blob)
+
+	output := buf.String()
+	assert.NotContains(t, output, "secret-key")
+	assert.NotContains(t, output, blob)
+
+	records := wireRecords(t, buf)
+	require.Len(t, records, 2)
+
+	request := records[0]
+	assert.Equal(t, "http request", request["msg"])
+	assert.Equal(t, "DEBUG", request["level"])
+	assert.Contains(t, request["url"], "key=REDACTED")
+	assert.Equal(t, "REDACTED", request["headers"].(map[string]interface{})["X-Goog-Api-Key"])
+	assert.Contains(t, request["body"], `"prompt":"A cat"`)
+	assert.Contains(t, request["body"], "bytes truncated>")
+
+	response := records[1]
+	assert.Equal(t, "http response", response["msg"])
+	assert.Equal(t, float64(http.StatusOK), response["status"])
+	assert.Contains(t, response["body"], `"name":"operations/op-1"`)
+	assert.Contains(t, response["body"], "bytes truncated>")
+}
+
+func TestWireLogger_LogsBinaryBodiesBySize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "video/mp4")
+		_, _ = w.Write([]byte("0123456789"))
+	}))
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	transport := veo3.WireLogger(logger)(http.DefaultTransport)
+
+	req, err := http.NewRequest("GET", server.URL+"/video.mp4", nil)
+	require.NoError(t, err)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(body))
+
+	records := wireRecords(t, buf)
+	require.Len(t, records, 2)
+	assert.Equal(t, "<10 bytes of video/mp4>", records[1]["body"])
+}
+
+func TestWireLogger_SilentAboveDebug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"name": "operations/op-1", "done": false}`))
+	}))
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	client, err := veo3.NewClient(context.Background(), "test-api-key",
+		veo3.WithBaseURL(server.URL),
+		veo3.WithLogger(logger),
+		veo3.WithMiddleware(veo3.WireLogger(logger)))
+	require.NoError(t, err)
+
+	_, err = client.GetOperation(context.Background(), "operations/op-1")
+	require.NoError(t, err)
+	assert.Empty(t, buf.String())
+}
+
+func TestClient_LoggerDefaultsToDiscard(t *testing.T) {
+	client, err := veo3.NewClient(context.Background(), "test-api-key")
+	require.NoError(t, err)
+	require.NotNil(t, client.Logger())
+	assert.False(t, client.Logger().Enabled(context.Background(), slog.LevelError))
+}
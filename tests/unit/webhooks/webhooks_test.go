@@ -0,0 +1,164 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jasongoecke/go-veo3/pkg/veo3"
+	"github.com/jasongoecke/go-veo3/pkg/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func doneOperation() *veo3.Operation {
+	return &veo3.Operation{
+		ID:        "operations/test-123",
+		Status:    veo3.StatusDone,
+		Progress:  1.0,
+		VideoURI:  "https://example.com/video.mp4",
+		StartTime: time.Now().Add(-time.Minute),
+	}
+}
+
+func TestNotifier_SignedDelivery(t *testing.T) {
+	secret := "s3cret"
+	var received webhooks.Payload
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "operation.done", r.Header.Get(webhooks.EventHeader))
+		assert.True(t, webhooks.Verify([]byte(secret), r.Header.Get(webhooks.TimestampHeader), body, r.Header.Get(webhooks.SignatureHeader)))
+
+		require.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := webhooks.NewNotifier([]string{server.URL}, secret)
+	err := notifier.Notify(context.Background(), webhooks.Notification{
+		Operation: doneOperation(),
+		FilePath:  "/tmp/video.mp4",
+		JobID:     "job1",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "operation.done", received.Event)
+	assert.Equal(t, "/tmp/video.mp4", received.FilePath)
+	assert.Equal(t, "job1", received.JobID)
+
+	// The operation uses the same envelope as the CLI's --json output
+	var op struct {
+		Success bool           `json:"success"`
+		Data    veo3.Operation `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(received.Operation, &op))
+	assert.True(t, op.Success)
+	assert.Equal(t, "operations/test-123", op.Data.ID)
+	assert.Equal(t, veo3.StatusDone, op.Data.Status)
+}
+
+func TestNotifier_RetryPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{name: "retries server errors", statuses: []int{500, 503, 200}, wantAttempts: 3},
+		{name: "retries rate limiting", statuses: []int{429, 200}, wantAttempts: 2},
+		{name: "does not retry client errors", statuses: []int{400}, wantAttempts: 1, wantErr: true},
+		{name: "gives up after max attempts", statuses: []int{500, 500, 500, 500}, wantAttempts: 3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&attempts, 1)
+				w.WriteHeader(tt.statuses[n-1])
+			}))
+			defer server.Close()
+
+			notifier := webhooks.NewNotifier([]string{server.URL}, "")
+			notifier.SetRetryPolicy(3, time.Millisecond, 5*time.Millisecond)
+
+			err := notifier.Notify(context.Background(), webhooks.Notification{Operation: doneOperation()})
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), server.URL)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+func TestNotifier_IgnoresNonTerminalOperations(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	notifier := webhooks.NewNotifier([]string{server.URL, server.URL, " "}, "")
+	op := doneOperation()
+	op.Status = veo3.StatusRunning
+
+	require.NoError(t, notifier.Notify(context.Background(), webhooks.Notification{Operation: op}))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	// Duplicate and blank URLs are delivered to once
+	op.Status = veo3.StatusFailed
+	require.NoError(t, notifier.Notify(context.Background(), webhooks.Notification{Operation: op}))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestNotifier_Disabled(t *testing.T) {
+	var nilNotifier *webhooks.Notifier
+	assert.False(t, nilNotifier.Enabled())
+	assert.False(t, webhooks.NewNotifier(nil, "secret").Enabled())
+	assert.NoError(t, nilNotifier.Notify(context.Background(), webhooks.Notification{Operation: doneOperation()}))
+}
+
+func TestEventName(t *testing.T) {
+	tests := []struct {
+		status veo3.OperationStatus
+		want   string
+		ok     bool
+	}{
+		{veo3.StatusDone, "operation.done", true},
+		{veo3.StatusFailed, "operation.failed", true},
+		{veo3.StatusCancelled, "operation.cancelled", true},
+		{veo3.StatusPending, "", false},
+		{veo3.StatusRunning, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			got, ok := webhooks.EventName(tt.status)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	secret := []byte("key")
+	body := []byte(`{"event":"operation.done"}`)
+
+	sig := webhooks.Sign(secret, "1700000000", body)
+	assert.Contains(t, sig, "sha256=")
+	assert.True(t, webhooks.Verify(secret, "1700000000", body, sig))
+	assert.False(t, webhooks.Verify(secret, "1700000001", body, sig))
+	assert.False(t, webhooks.Verify([]byte("other"), "1700000000", body, sig))
+}